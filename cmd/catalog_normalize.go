@@ -103,6 +103,9 @@ func (a *App) RunCatalogDiscover(sourceArg string, jsonOut bool) error {
 	if err != nil {
 		return fmt.Errorf("invalid source: %w", err)
 	}
+	if source.Type != internal.RoleRepoSourceGitHub {
+		return fmt.Errorf("catalog discover only supports GitHub sources, got %s source %s", source.Type, source.FullName())
+	}
 
 	client := internal.NewRoleRepoGitHubClient()
 	roles, err := client.DiscoverRemoteRoles(context.Background(), source)
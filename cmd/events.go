@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+// eventsFollowInterval is overridable in tests.
+var eventsFollowInterval = 500 * time.Millisecond
+
+func newEventsCmd() *cobra.Command {
+	var follow bool
+	var types []string
+	var taskID string
+	var since string
+	var jsonOutput bool
+	cmd := &cobra.Command{
+		Use:   "events [--follow] [--type <type>] [--task <task-id>] [--since <time|duration>]",
+		Short: "Show the state change event stream",
+		Long: "Reads .agent-team/events.jsonl. --since accepts an RFC3339 timestamp or a duration such as 2h.\n" +
+			"Event types: task.created, task.transition, planning.created, planning.moved, planning.transition,\n" +
+			"planning.linked, planning.unlinked, worker.spawned, worker.closed, worker.merged, worker.deleted,\n" +
+			"message.sent, workflow_plan.transition, role.installed, role.updated, role.removed.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter, err := buildEventFilter(types, taskID, since, time.Now().UTC())
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			if follow {
+				var stop context.CancelFunc
+				ctx, stop = signal.NotifyContext(ctx, os.Interrupt)
+				defer stop()
+			}
+			return GetApp(cmd).RunEvents(ctx, cmd.OutOrStdout(), filter, follow, jsonOutput)
+		},
+	}
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep streaming new events")
+	cmd.Flags().StringArrayVar(&types, "type", nil, "Only show events of this type (repeatable)")
+	cmd.Flags().StringVar(&taskID, "task", "", "Only show events for this task")
+	cmd.Flags().StringVar(&since, "since", "", "Only show events at or after this time")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print raw JSON lines")
+	return cmd
+}
+
+func (a *App) RunEvents(ctx context.Context, out io.Writer, filter internal.EventFilter, follow, jsonOutput bool) error {
+	root := a.Git.Root()
+	events, offset, err := internal.ReadEvents(root, filter)
+	if err != nil {
+		return err
+	}
+	for _, evt := range events {
+		if err := printEvent(out, evt, jsonOutput); err != nil {
+			return err
+		}
+	}
+	if !follow {
+		if len(events) == 0 && !jsonOutput {
+			fmt.Fprintln(out, "No events found.")
+		}
+		return nil
+	}
+	return internal.FollowEvents(ctx, root, filter, offset, eventsFollowInterval, func(evt internal.Event) error {
+		return printEvent(out, evt, jsonOutput)
+	})
+}
+
+func buildEventFilter(types []string, taskID, since string, now time.Time) (internal.EventFilter, error) {
+	filter := internal.EventFilter{TaskID: strings.TrimSpace(taskID)}
+	for _, t := range types {
+		filter.Types = append(filter.Types, internal.EventType(strings.TrimSpace(t)))
+	}
+	ts, err := parseSinceFlag(since, now)
+	filter.Since = ts
+	return filter, err
+}
+
+// parseSinceFlag accepts an RFC3339 timestamp or a duration back from now.
+// An empty value yields the zero time.
+func parseSinceFlag(since string, now time.Time) (time.Time, error) {
+	since = strings.TrimSpace(since)
+	if since == "" {
+		return time.Time{}, nil
+	}
+	if ts, err := time.Parse(time.RFC3339, since); err == nil {
+		return ts, nil
+	}
+	if d, err := time.ParseDuration(since); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use an RFC3339 timestamp or a duration like 2h", since)
+}
+
+func printEvent(out io.Writer, evt internal.Event, jsonOutput bool) error {
+	if jsonOutput {
+		data, err := json.Marshal(evt)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	}
+
+	parts := []string{evt.Time, string(evt.Type)}
+	if evt.TaskID != "" {
+		parts = append(parts, "task="+evt.TaskID)
+	}
+	if evt.WorkerID != "" {
+		parts = append(parts, "worker="+evt.WorkerID)
+	}
+	if evt.Subject != "" {
+		parts = append(parts, "subject="+evt.Subject)
+	}
+	if evt.From != "" || evt.To != "" {
+		from := evt.From
+		if from == "" {
+			from = "-"
+		}
+		parts = append(parts, from+" → "+evt.To)
+	}
+	if evt.Message != "" {
+		parts = append(parts, fmt.Sprintf("%q", evt.Message))
+	}
+	_, err := fmt.Fprintln(out, strings.Join(parts, "  "))
+	return err
+}
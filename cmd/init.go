@@ -17,7 +17,9 @@ func newInitCmd() *cobra.Command {
 		Short: "Initialize agent-team for this project",
 		Long: `Creates project-level structure under .agent-team/, including .agent-team/teams/ and .agent-team/rules/.
 It writes the fixed rules entry/core files, regenerates AI-based project rule files under
-.agent-team/rules/project/, and updates root provider files (CLAUDE.md, AGENTS.md, GEMINI.md).
+.agent-team/rules/project/, updates root provider files (CLAUDE.md, AGENTS.md, GEMINI.md), and
+registers the agent-team MCP server in provider settings (.mcp.json, .gemini/settings.json,
+opencode.json, .codex/config.toml).
 
 Built-in entry/core rule files are created if missing. Project rules are regenerated on each run.
 Provider files only update the tagged section.
@@ -73,6 +75,7 @@ func runInit() error {
 		return err
 	}
 	fmt.Println("✓ Provider files updated (CLAUDE.md, AGENTS.md, GEMINI.md)")
+	fmt.Println("✓ MCP server registered (.mcp.json, .gemini/settings.json, opencode.json, .codex/config.toml)")
 
 	// Step 5: Validate generated rules after writing
 	if err := internal.ValidateRules(cwd); err != nil {
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newMCPCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mcp",
+		Short: "Run the agent-team MCP server over stdio",
+		Long: "Serves agent-team operations as Model Context Protocol tools and resources on stdin/stdout.\n" +
+			"Tools: task_create, task_assign, task_done, task_show, reply_main, worker_status, planning_show,\n" +
+			"catalog_search. Resources: agent-team://rules/index, agent-team://task/{task_id}/context and\n" +
+			"agent-team://task/{task_id}/verification. `agent-team init` registers this server for each provider.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunMCP(cmd.Context(), os.Stdin, os.Stdout)
+		},
+	}
+}
+
+func (a *App) RunMCP(ctx context.Context, in io.Reader, out io.Writer) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	server := internal.NewMCPServer("agent-team", Version)
+	a.addMCPTools(server)
+	internal.AddProjectMCPResources(server, a.Git.Root())
+	return server.Serve(ctx, in, out)
+}
+
+func (a *App) addMCPTools(server *internal.MCPServer) {
+	server.AddTool(internal.MCPTool{
+		Name:        "task_create",
+		Description: "Create a task package for a role.",
+		InputSchema: internal.MCPObjectSchema(map[string]string{
+			"title":    "Task title",
+			"role":     "Role bound to this task",
+			"design":   "Optional path to a design/brainstorming file",
+			"phase":    "Optional phase to link the task to",
+			"template": "Optional task template name",
+		}, "title", "role"),
+		Handler: func(raw json.RawMessage) (string, error) {
+			var args struct{ Title, Role, Design, Phase, Template string }
+			if err := internal.DecodeMCPArgs(raw, &args); err != nil {
+				return "", err
+			}
+			return captureCommandOutput(func() error { return a.RunTaskCreate(args.Title, args.Role, args.Design, args.Phase, args.Template) })
+		},
+	})
+	server.AddTool(internal.MCPTool{
+		Name:        "task_assign",
+		Description: "Assign a task and open its worker session.",
+		InputSchema: internal.MCPObjectSchema(map[string]string{
+			"task_id":   "Task to assign",
+			"worker_id": "Existing worker ID for same-role reassignment",
+			"provider":  "AI provider for a new worker",
+			"model":     "AI model identifier",
+			"reason":    "Why the task is (re)assigned",
+		}, "task_id"),
+		Handler: func(raw json.RawMessage) (string, error) {
+			var args struct {
+				TaskID   string `json:"task_id"`
+				WorkerID string `json:"worker_id"`
+				Provider string `json:"provider"`
+				Model    string `json:"model"`
+				Reason   string `json:"reason"`
+			}
+			if err := internal.DecodeMCPArgs(raw, &args); err != nil {
+				return "", err
+			}
+			return captureCommandOutput(func() error {
+				return a.RunTaskAssign(args.TaskID, args.WorkerID, args.Provider, args.Model, args.Reason, false)
+			})
+		},
+	})
+	server.AddTool(internal.MCPTool{
+		Name:        "task_done",
+		Description: "Mark an assigned task as verifying.",
+		InputSchema: internal.MCPObjectSchema(map[string]string{
+			"task_id": "Task to complete",
+			"reason":  "Note recorded in the task history",
+		}, "task_id"),
+		Handler: func(raw json.RawMessage) (string, error) {
+			var args struct {
+				TaskID string `json:"task_id"`
+				Reason string `json:"reason"`
+			}
+			if err := internal.DecodeMCPArgs(raw, &args); err != nil {
+				return "", err
+			}
+			return captureCommandOutput(func() error { return a.RunTaskDone(args.TaskID, args.Reason) })
+		},
+	})
+	server.AddTool(internal.MCPTool{
+		Name:        "task_show",
+		Description: "Show task package details, context and verification.",
+		InputSchema: internal.MCPObjectSchema(map[string]string{"task_id": "Task to show"}, "task_id"),
+		Handler: func(raw json.RawMessage) (string, error) {
+			var args struct {
+				TaskID string `json:"task_id"`
+			}
+			if err := internal.DecodeMCPArgs(raw, &args); err != nil {
+				return "", err
+			}
+			return captureCommandOutput(func() error { return a.RunTaskShow(args.TaskID) })
+		},
+	})
+	server.AddTool(internal.MCPTool{
+		Name:        "reply_main",
+		Description: "Send a message from this worker to the main controller's session.",
+		InputSchema: internal.MCPObjectSchema(map[string]string{"message": "Message for the controller"}, "message"),
+		Handler: func(raw json.RawMessage) (string, error) {
+			var args struct{ Message string }
+			if err := internal.DecodeMCPArgs(raw, &args); err != nil {
+				return "", err
+			}
+			return captureCommandOutput(func() error { return a.RunReplyMain(args.Message) })
+		},
+	})
+	server.AddTool(internal.MCPTool{
+		Name:        "worker_status",
+		Description: "Show all workers, their roles, running state, and active tasks.",
+		InputSchema: internal.MCPObjectSchema(map[string]string{}),
+		Handler: func(raw json.RawMessage) (string, error) {
+			return captureCommandOutput(a.RunWorkerStatus)
+		},
+	})
+	server.AddTool(internal.MCPTool{
+		Name:        "planning_show",
+		Description: "Show a planning artifact and its reference checks.",
+		InputSchema: internal.MCPObjectSchema(map[string]string{"id": "Planning artifact ID"}, "id"),
+		Handler: func(raw json.RawMessage) (string, error) {
+			var args struct{ ID string }
+			if err := internal.DecodeMCPArgs(raw, &args); err != nil {
+				return "", err
+			}
+			return captureCommandOutput(func() error { return a.RunPlanningShow(args.ID) })
+		},
+	})
+	server.AddTool(internal.MCPTool{
+		Name:        "catalog_search",
+		Description: "Search the role catalog. Returns JSON.",
+		InputSchema: internal.MCPObjectSchema(map[string]string{
+			"query":  "Search query",
+			"status": "discovered, verified, invalid, unreachable, or all (default: all)",
+		}, "query"),
+		Handler: func(raw json.RawMessage) (string, error) {
+			var args struct{ Query, Status string }
+			if err := internal.DecodeMCPArgs(raw, &args); err != nil {
+				return "", err
+			}
+			return captureCommandOutput(func() error { return a.RunCatalogSearch(args.Query, args.Status, true) })
+		},
+	})
+}
+
+// captureCommandOutput runs fn with os.Stdout redirected so the human-readable
+// command output can be returned as a tool result instead of corrupting the
+// MCP stream. The server handles one request at a time, so the swap is safe.
+func captureCommandOutput(fn func() error) (string, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	runErr := fn()
+	os.Stdout = orig
+	w.Close()
+	output := <-done
+	r.Close()
+	return strings.TrimRight(output, "\n"), runErr
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type mcpTestResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type mcpTestToolResult struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+	IsError bool `json:"isError"`
+}
+
+func runMCPRequests(t *testing.T, app *App, requests ...string) []mcpTestResponse {
+	t.Helper()
+	var out bytes.Buffer
+	in := strings.NewReader(strings.Join(requests, "\n") + "\n")
+	if err := app.RunMCP(context.Background(), in, &out); err != nil {
+		t.Fatalf("RunMCP: %v", err)
+	}
+	var responses []mcpTestResponse
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var resp mcpTestResponse
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("unmarshal response %q: %v", line, err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestRunMCPTaskCreateAndShow(t *testing.T) {
+	app, dir := initTestApp(t)
+	roleDir := filepath.Join(dir, ".agent-team", "teams", "backend")
+	if err := os.MkdirAll(roleDir, 0755); err != nil {
+		t.Fatalf("mkdir role dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(roleDir, "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatalf("write skill: %v", err)
+	}
+
+	responses := runMCPRequests(t, app,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"task_create","arguments":{"title":"Wire MCP","role":"backend"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"task_done","arguments":{"task_id":"missing"}}}`,
+	)
+	if len(responses) != 3 {
+		t.Fatalf("responses = %d, want 3 (notifications get no reply)", len(responses))
+	}
+
+	var created mcpTestToolResult
+	if err := json.Unmarshal(responses[1].Result, &created); err != nil {
+		t.Fatalf("unmarshal task_create: %v", err)
+	}
+	if created.IsError || !strings.Contains(created.Content[0].Text, "✓ Created task") {
+		t.Fatalf("unexpected task_create result: %+v", created)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, ".agent-team", "task"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one task package, err=%v", err)
+	}
+
+	var done mcpTestToolResult
+	if err := json.Unmarshal(responses[2].Result, &done); err != nil {
+		t.Fatalf("unmarshal task_done: %v", err)
+	}
+	if !done.IsError {
+		t.Fatalf("task_done on missing task should report isError: %+v", done)
+	}
+
+	taskID := entries[0].Name()
+	responses = runMCPRequests(t, app,
+		`{"jsonrpc":"2.0","id":4,"method":"resources/read","params":{"uri":"agent-team://task/`+taskID+`/verification"}}`,
+	)
+	if responses[0].Error != nil || !strings.Contains(string(responses[0].Result), "verification") {
+		t.Fatalf("unexpected resources/read response: %s (%+v)", responses[0].Result, responses[0].Error)
+	}
+}
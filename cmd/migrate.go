@@ -4,20 +4,79 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/JsonLee12138/agent-team/internal"
 	"github.com/spf13/cobra"
 )
 
 func newMigrateCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "migrate",
-		Short: "Migrate agents/ directory to .agents/ (hidden)",
-		Long:  "Moves the agents/ directory to .agents/ for Claude Code Plugin compatibility.",
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "migrate [--dry-run]",
+		Short: "Upgrade .agent-team artifacts to the current schema",
+		Long: "Moves the legacy agents/ directory to .agents/ and rewrites task, worker, planning and workflow plan\n" +
+			"files under .agent-team/ (including worker worktrees) to the current schema_version. Also adds *.lock\n" +
+			"to .agent-team/.gitignore so advisory lock files stay untracked.",
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			app := GetApp(cmd)
-			return runMigrate(app.Git.Root())
+			return app.RunMigrate(dryRun)
 		},
 	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report pending changes without writing")
+	return cmd
+}
+
+func (a *App) RunMigrate(dryRun bool) error {
+	root := a.Git.Root()
+	if dryRun {
+		if _, err := os.Stat(filepath.Join(root, "agents")); err == nil {
+			fmt.Println("Would migrate agents/ → .agents/")
+		}
+	} else if err := runMigrate(root); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(internal.AgentTeamDir(root)); err == nil && info.IsDir() && !internal.StateLocksIgnored(root) {
+		if dryRun {
+			fmt.Println("Would add *.lock to .agent-team/.gitignore")
+		} else if err := internal.EnsureStateLocksIgnored(root); err != nil {
+			return err
+		} else {
+			fmt.Println("✓ Added *.lock to .agent-team/.gitignore")
+		}
+	}
+
+	report, err := internal.MigrateStateTree(root, a.WtBase, dryRun)
+	if err != nil {
+		return err
+	}
+	if len(report) == 0 {
+		fmt.Println("All .agent-team artifacts are at the current schema version.")
+		return nil
+	}
+
+	verb := "Migrated"
+	if dryRun {
+		verb = "Would migrate"
+	}
+	for _, entry := range report {
+		rel, err := filepath.Rel(root, entry.Path)
+		if err != nil {
+			rel = entry.Path
+		}
+		fmt.Printf("%s %s (%s v%d → v%d)\n", verb, filepath.ToSlash(rel), entry.Kind, entry.FromVersion, entry.ToVersion)
+		for _, step := range entry.Applied {
+			fmt.Printf("  - %s\n", step)
+		}
+	}
+	if dryRun {
+		fmt.Printf("\n%d artifact(s) need migration. Re-run without --dry-run to apply.\n", len(report))
+	} else {
+		fmt.Printf("\n✓ Migrated %d artifact(s)\n", len(report))
+	}
+	return nil
 }
 
 func runMigrate(root string) error {
@@ -26,7 +85,6 @@ func runMigrate(root string) error {
 
 	// 检测 agents/ 是否存在
 	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
-		fmt.Println("Nothing to migrate: agents/ directory not found.")
 		return nil
 	}
 
@@ -11,5 +11,9 @@ func newPlanningCmd() *cobra.Command {
 	cmd.AddCommand(newPlanningListCmd())
 	cmd.AddCommand(newPlanningShowCmd())
 	cmd.AddCommand(newPlanningMoveCmd())
+	cmd.AddCommand(newPlanningStatusCmd())
+	cmd.AddCommand(newPlanningTreeCmd())
+	cmd.AddCommand(newPlanningLinkCmd())
+	cmd.AddCommand(newPlanningUnlinkCmd())
 	return cmd
 }
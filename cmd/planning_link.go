@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newPlanningLinkCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "link <parent-id> <child-id>",
+		Short: "Attach a milestone, phase, or task to a planning artifact",
+		Long: "Roadmaps hold milestones and phases, milestones hold phases, and any planning artifact can hold tasks.\n" +
+			"The back-reference is recorded on the child as well.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunPlanningLink(args[0], args[1])
+		},
+	}
+}
+
+func newPlanningUnlinkCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unlink <parent-id> <child-id>",
+		Short: "Remove a link between a planning artifact and its child",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunPlanningUnlink(args[0], args[1])
+		},
+	}
+}
+
+func (a *App) RunPlanningLink(parentID, childID string) error {
+	link, err := internal.LinkPlanning(a.Git.Root(), parentID, childID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Linked %s '%s' to %s '%s'\n", link.ChildKind(), link.ChildID(), link.Parent.Kind, link.Parent.ID)
+	return nil
+}
+
+func (a *App) RunPlanningUnlink(parentID, childID string) error {
+	link, err := internal.UnlinkPlanning(a.Git.Root(), parentID, childID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Unlinked %s '%s' from %s '%s'\n", link.ChildKind(), link.ChildID(), link.Parent.Kind, link.Parent.ID)
+	return nil
+}
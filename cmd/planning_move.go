@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/JsonLee12138/agent-team/internal"
@@ -11,16 +12,26 @@ import (
 func newPlanningMoveCmd() *cobra.Command {
 	var to string
 	var reason string
+	var cascade bool
+	var yes bool
 	cmd := &cobra.Command{
-		Use:   "move <id> --to <planning|archived|deprecated>",
+		Use:   "move <id> --to <planning|archived|deprecated> [--cascade]",
 		Short: "Move a planning artifact between planning, archived, and deprecated",
-		Args:  cobra.ExactArgs(1),
+		Long: "With --cascade every milestone and phase below <id> moves too. The affected records are\n" +
+			"listed first and the move asks for confirmation unless --yes is given.",
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return GetApp(cmd).RunPlanningMove(args[0], to, reason)
+			app := GetApp(cmd)
+			if cascade {
+				return app.RunPlanningMoveCascade(cmd.InOrStdin(), cmd.OutOrStdout(), args[0], to, reason, yes)
+			}
+			return app.RunPlanningMove(args[0], to, reason)
 		},
 	}
 	cmd.Flags().StringVar(&to, "to", "", "Target lifecycle: planning, archived, or deprecated")
 	cmd.Flags().StringVar(&reason, "reason", "", "Deprecated reason when moving to deprecated")
+	cmd.Flags().BoolVar(&cascade, "cascade", false, "Also move every planning artifact below <id>")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt for --cascade")
 	_ = cmd.MarkFlagRequired("to")
 	return cmd
 }
@@ -38,3 +49,45 @@ func (a *App) RunPlanningMove(id, toRaw, reason string) error {
 	fmt.Printf("  → Path: %s\n", record.Path)
 	return nil
 }
+
+func (a *App) RunPlanningMoveCascade(in io.Reader, out io.Writer, id, toRaw, reason string, yes bool) error {
+	to, err := internal.ParsePlanningLifecycle(toRaw)
+	if err != nil {
+		return err
+	}
+	root := a.Git.Root()
+	records, err := internal.PlanningSubtree(root, id)
+	if err != nil {
+		return err
+	}
+	var affected []*internal.PlanningRecord
+	for _, record := range records {
+		if record.Lifecycle != to {
+			affected = append(affected, record)
+		}
+	}
+	if len(affected) == 0 {
+		fmt.Fprintf(out, "Nothing to move: all records are already %s.\n", to)
+		return nil
+	}
+
+	fmt.Fprintf(out, "The following %d record(s) will move to %s:\n", len(affected), to)
+	for _, record := range affected {
+		fmt.Fprintf(out, "  %-10s %-40s %s\n", record.Kind, record.ID, record.Lifecycle)
+	}
+	if !yes {
+		ok, err := promptConfirm(in, out, fmt.Sprintf("Move %d record(s)?", len(affected)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("move cancelled")
+		}
+	}
+
+	moved, err := internal.MovePlanningTree(root, id, to, reason, time.Now().UTC())
+	for _, record := range moved {
+		fmt.Fprintf(out, "✓ Moved %s '%s' to %s\n", record.Kind, record.ID, record.Lifecycle)
+	}
+	return err
+}
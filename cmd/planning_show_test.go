@@ -25,3 +25,88 @@ func TestRunPlanningShowDisplaysReferenceChecks(t *testing.T) {
 		}
 	}
 }
+
+func TestRunPlanningTreeFormats(t *testing.T) {
+	app, dir := initTestApp(t)
+	record, err := internal.CreatePlanningRecord(dir, internal.PlanningKindPhase, "Phase A", time.Now().UTC())
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord: %v", err)
+	}
+	out := captureStdout(t, func() {
+		if err := app.RunPlanningTree("", "text"); err != nil {
+			t.Fatalf("RunPlanningTree: %v", err)
+		}
+	})
+	for _, needle := range []string{"phase " + record.ID, "Phase A", "0% done"} {
+		if !strings.Contains(out, needle) {
+			t.Fatalf("output missing %q:\n%s", needle, out)
+		}
+	}
+	out = captureStdout(t, func() {
+		if err := app.RunPlanningTree(record.ID, "json"); err != nil {
+			t.Fatalf("RunPlanningTree json: %v", err)
+		}
+	})
+	if !strings.Contains(out, `"progress"`) {
+		t.Fatalf("json output = %s", out)
+	}
+	if err := app.RunPlanningTree("", "dot"); err == nil {
+		t.Fatal("expected error for unsupported format")
+	}
+}
+
+func TestRunPlanningMoveCascadeConfirms(t *testing.T) {
+	app, dir := initTestApp(t)
+	now := time.Now().UTC()
+	milestone, err := internal.CreatePlanningRecord(dir, internal.PlanningKindMilestone, "Milestone", now)
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord: %v", err)
+	}
+	phase, err := internal.CreatePlanningRecord(dir, internal.PlanningKindPhase, "Phase", now)
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord: %v", err)
+	}
+	if err := app.RunPlanningLink(milestone.ID, phase.ID); err != nil {
+		t.Fatalf("RunPlanningLink: %v", err)
+	}
+
+	var out strings.Builder
+	if err := app.RunPlanningMoveCascade(strings.NewReader(""), &out, milestone.ID, "deprecated", "dropped", false); err == nil {
+		t.Fatal("expected non-interactive cascade without --yes to be cancelled")
+	}
+	if !strings.Contains(out.String(), "2 record(s) will move to deprecated") || !strings.Contains(out.String(), phase.ID) {
+		t.Fatalf("preview = %s", out.String())
+	}
+	if record, _ := internal.LoadPlanningRecord(dir, phase.ID); record.Lifecycle != internal.PlanningLifecycleActive {
+		t.Fatalf("phase moved without confirmation: %s", record.Lifecycle)
+	}
+
+	out.Reset()
+	if err := app.RunPlanningMoveCascade(strings.NewReader(""), &out, milestone.ID, "deprecated", "dropped", true); err != nil {
+		t.Fatalf("RunPlanningMoveCascade: %v", err)
+	}
+	record, err := internal.LoadPlanningRecord(dir, phase.ID)
+	if err != nil {
+		t.Fatalf("LoadPlanningRecord: %v", err)
+	}
+	if record.Lifecycle != internal.PlanningLifecycleDeprecated || record.DeprecatedReason != "dropped" {
+		t.Fatalf("phase = %s (%q)", record.Lifecycle, record.DeprecatedReason)
+	}
+}
+
+func TestRunPlanningStatusValidatesTransition(t *testing.T) {
+	app, dir := initTestApp(t)
+	record, err := internal.CreatePlanningRecord(dir, internal.PlanningKindPhase, "Phase", time.Now().UTC())
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord: %v", err)
+	}
+	if err := app.RunPlanningStatus(record.ID, "shipped"); err == nil {
+		t.Fatal("expected unknown status error")
+	}
+	if err := app.RunPlanningStatus(record.ID, "done"); err == nil {
+		t.Fatal("expected proposed → done to be rejected")
+	}
+	if err := app.RunPlanningStatus(record.ID, "active"); err != nil {
+		t.Fatalf("RunPlanningStatus: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newPlanningStatusCmd() *cobra.Command {
+	var to string
+	cmd := &cobra.Command{
+		Use:   "status <id> --to <proposed|active|done|cancelled>",
+		Short: "Change a planning artifact's status",
+		Long: "Transitions: proposed → active | cancelled, active → done | cancelled, done → active (reopen),\n" +
+			"cancelled → proposed. Artifacts are promoted to done automatically once all of their tasks are archived.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunPlanningStatus(args[0], to)
+		},
+	}
+	cmd.Flags().StringVar(&to, "to", "", "Target status: proposed, active, done, or cancelled")
+	_ = cmd.MarkFlagRequired("to")
+	return cmd
+}
+
+func (a *App) RunPlanningStatus(id, toRaw string) error {
+	to, err := internal.ParsePlanningStatus(toRaw)
+	if err != nil {
+		return err
+	}
+	record, err := internal.SetPlanningStatus(a.Git.Root(), id, to, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ %s '%s' is now %s\n", record.Kind, record.ID, record.Status)
+	return nil
+}
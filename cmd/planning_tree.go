@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newPlanningTreeCmd() *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "tree [<id>] [--format text|mermaid|json]",
+		Short: "Show the planning hierarchy with task progress",
+		Long: "Renders roadmaps → milestones → phases → tasks. Without <id> every active top-level artifact is shown.\n" +
+			"Progress rolls up the statuses of all tasks below each node; dangling references are flagged.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := ""
+			if len(args) == 1 {
+				id = args[0]
+			}
+			return GetApp(cmd).RunPlanningTree(id, format)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, mermaid, or json")
+	return cmd
+}
+
+func (a *App) RunPlanningTree(id, format string) error {
+	switch format {
+	case "text", "mermaid", "json":
+	default:
+		return fmt.Errorf("invalid --format %q: use text, mermaid, or json", format)
+	}
+	nodes, err := internal.BuildPlanningTree(a.Git.Root(), id)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(nodes, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "mermaid":
+		fmt.Print(internal.RenderPlanningTreeMermaid(nodes))
+	default:
+		if len(nodes) == 0 {
+			fmt.Println("No planning artifacts found.")
+			return nil
+		}
+		for i, node := range nodes {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(planningTreeLabel(node))
+			printPlanningTreeChildren(node, "")
+		}
+	}
+	return nil
+}
+
+func printPlanningTreeChildren(node *internal.PlanningTreeNode, prefix string) {
+	var lines []string
+	var children []*internal.PlanningTreeNode
+	for _, issue := range node.Issues {
+		lines = append(lines, "⚠ "+issue)
+		children = append(children, nil)
+	}
+	for _, child := range node.Children {
+		lines = append(lines, planningTreeLabel(child))
+		children = append(children, child)
+	}
+	for _, task := range node.Tasks {
+		lines = append(lines, fmt.Sprintf("task %s  %s [%s]", task.TaskID, task.Title, task.Status))
+		children = append(children, nil)
+	}
+
+	for i, line := range lines {
+		branch, indent := "├── ", "│   "
+		if i == len(lines)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Println(prefix + branch + line)
+		if children[i] != nil {
+			printPlanningTreeChildren(children[i], prefix+indent)
+		}
+	}
+}
+
+func planningTreeLabel(node *internal.PlanningTreeNode) string {
+	status := string(node.Status)
+	if node.Lifecycle != internal.PlanningLifecycleActive {
+		status = strings.TrimPrefix(status+", "+string(node.Lifecycle), ", ")
+	}
+	p := node.Progress
+	return fmt.Sprintf("%s %s  %s [%s]  %d%% done · draft %d · assigned %d · verifying %d · archived %d",
+		node.Kind, node.ID, node.Title, status, p.DonePercent(),
+		p.Counts[internal.TaskStatusDraft], p.Counts[internal.TaskStatusAssigned],
+		p.Counts[internal.TaskStatusVerifying], p.Counts[internal.TaskStatusArchived])
+}
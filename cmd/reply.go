@@ -31,6 +31,7 @@ func (a *App) RunReply(workerID, answer string) error {
 	}
 
 	a.Session.PaneSend(cfg.PaneID, "[Main Controller Reply] "+answer)
+	internal.EmitEvent(root, internal.Event{Type: internal.EventMessageSent, WorkerID: workerID, TaskID: cfg.TaskID, Message: answer, Data: map[string]string{"direction": "to_worker"}})
 	fmt.Printf("✓ Replied to worker '%s'\n", workerID)
 	return nil
 }
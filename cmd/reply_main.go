@@ -59,6 +59,7 @@ func (a *App) RunReplyMain(message string) error {
 	}
 
 	a.Session.PaneSend(wcfg.ControllerPaneID, fmt.Sprintf("[Worker: %s] %s", workerID, message))
+	internal.EmitEvent(projectRoot, internal.Event{Type: internal.EventMessageSent, WorkerID: workerID, TaskID: wcfg.TaskID, Message: message, Data: map[string]string{"direction": "to_controller"}})
 	fmt.Printf("✓ Sent to main controller from worker '%s'\n", workerID)
 	return nil
 }
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func newRequirementCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "requirement",
+		Short: "Manage requirements and their sub-tasks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newRequirementCreateCmd())
+	cmd.AddCommand(newRequirementListCmd())
+	cmd.AddCommand(newRequirementShowCmd())
+	cmd.AddCommand(newRequirementSplitCmd())
+	cmd.AddCommand(newRequirementAssignCmd())
+	cmd.AddCommand(newRequirementDoneCmd())
+	cmd.AddCommand(newRequirementSkipCmd())
+	cmd.AddCommand(newRequirementReindexCmd())
+	return cmd
+}
+
+func parseSubTaskID(raw string) (int, error) {
+	id, err := strconv.Atoi(raw)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid sub-task id %q: must be a positive integer", raw)
+	}
+	return id, nil
+}
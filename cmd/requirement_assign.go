@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newRequirementAssignCmd() *cobra.Command {
+	var role string
+	var open bool
+	var workerID string
+	var provider string
+	var model string
+	var newWindow bool
+	cmd := &cobra.Command{
+		Use:   "assign <name> <sub-task-id> --role <role> [--open]",
+		Short: "Create a task package for a sub-task and mark it assigned",
+		Long: "Creates a draft task package from the sub-task title, linked back via requirement/sub_task_id in\n" +
+			"task.yaml and task_id in requirement.yaml. With --open the task is also assigned to a worker\n" +
+			"exactly like `agent-team task assign`.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			subTaskID, err := parseSubTaskID(args[1])
+			if err != nil {
+				return err
+			}
+			return GetApp(cmd).RunRequirementAssign(args[0], subTaskID, role, open, workerID, provider, model, newWindow)
+		},
+	}
+	cmd.Flags().StringVar(&role, "role", "", "Role bound to the created task")
+	cmd.Flags().BoolVar(&open, "open", false, "Also assign the task and open its worker session")
+	cmd.Flags().StringVar(&workerID, "worker", "", "Existing worker ID (with --open)")
+	cmd.Flags().StringVarP(&provider, "provider", "p", "", workerProviderFlagHelp)
+	cmd.Flags().StringVarP(&model, "model", "m", "", "AI model identifier")
+	cmd.Flags().BoolVarP(&newWindow, "new-window", "w", false, "Open in a new window instead of a tab")
+	_ = cmd.MarkFlagRequired("role")
+	return cmd
+}
+
+func (a *App) RunRequirementAssign(name string, subTaskID int, role string, open bool, workerID, provider, model string, newWindow bool) error {
+	root := a.Git.Root()
+	if _, err := internal.ResolveRole(root, role); err != nil {
+		return err
+	}
+	req, err := internal.LoadRequirement(root, name)
+	if err != nil {
+		return err
+	}
+	record, err := internal.CreateSubTaskPackage(root, req, subTaskID, role, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Created task '%s' for %s#%d\n", record.TaskID, req.Name, subTaskID)
+	fmt.Printf("  → Path: %s\n", record.TaskPath)
+
+	if !open {
+		fmt.Printf("  → Assign a worker with: agent-team task assign %s\n", record.TaskID)
+		return nil
+	}
+	if err := a.RunTaskAssign(record.TaskID, workerID, provider, model, "", newWindow); err != nil {
+		return err
+	}
+	record, _, err = internal.LoadTaskRecord(root, record.TaskID)
+	if err != nil {
+		return err
+	}
+	req, err = internal.LoadRequirement(root, name)
+	if err != nil {
+		return err
+	}
+	st, err := internal.FindSubTask(req, subTaskID)
+	if err != nil {
+		return err
+	}
+	st.AssignedTo = record.WorkerID
+	return internal.SaveRequirementAndIndex(root, req)
+}
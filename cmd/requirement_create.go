@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newRequirementCreateCmd() *cobra.Command {
+	var description string
+	var subTasks []string
+	cmd := &cobra.Command{
+		Use:   `create <name> [--description "<text>"] [--sub-task "<title>" ...]`,
+		Short: "Create a requirement",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunRequirementCreate(args[0], description, subTasks)
+		},
+	}
+	cmd.Flags().StringVar(&description, "description", "", "Requirement description")
+	cmd.Flags().StringArrayVar(&subTasks, "sub-task", nil, "Sub-task title (repeatable)")
+	return cmd
+}
+
+func (a *App) RunRequirementCreate(name, description string, subTaskTitles []string) error {
+	root := a.Git.Root()
+	if err := internal.ValidateRequirementName(name); err != nil {
+		return err
+	}
+	if _, err := os.Stat(internal.RequirementYAMLPath(root, name)); err == nil {
+		return fmt.Errorf("requirement '%s' already exists", name)
+	}
+
+	req := &internal.Requirement{Name: name}
+	subTasks, err := internal.SplitRequirement(req, subTaskTitles)
+	if err != nil {
+		return err
+	}
+	req, err = internal.CreateRequirement(root, name, description, subTasks)
+	if err != nil {
+		return err
+	}
+	if err := internal.UpdateIndexEntry(root, req); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Created requirement '%s'\n", req.Name)
+	fmt.Printf("  → Path: %s\n", internal.RequirementYAMLPath(root, req.Name))
+	fmt.Printf("  → Sub-tasks: %d\n", len(req.SubTasks))
+	return nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newRequirementDoneCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "done <name> <sub-task-id>",
+		Short: "Mark an assigned sub-task as done",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			subTaskID, err := parseSubTaskID(args[1])
+			if err != nil {
+				return err
+			}
+			return GetApp(cmd).RunRequirementDone(args[0], subTaskID)
+		},
+	}
+}
+
+func newRequirementSkipCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "skip <name> <sub-task-id>",
+		Short: "Skip a pending or assigned sub-task",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			subTaskID, err := parseSubTaskID(args[1])
+			if err != nil {
+				return err
+			}
+			return GetApp(cmd).RunRequirementSkip(args[0], subTaskID)
+		},
+	}
+}
+
+func (a *App) RunRequirementDone(name string, subTaskID int) error {
+	return a.updateSubTask(name, subTaskID, internal.SubTaskStatusDone, internal.MarkSubTaskDone)
+}
+
+func (a *App) RunRequirementSkip(name string, subTaskID int) error {
+	return a.updateSubTask(name, subTaskID, internal.SubTaskStatusSkipped, internal.SkipSubTask)
+}
+
+func (a *App) updateSubTask(name string, subTaskID int, to internal.SubTaskStatus, apply func(*internal.Requirement, int) error) error {
+	root := a.Git.Root()
+	req, err := internal.LoadRequirement(root, name)
+	if err != nil {
+		return err
+	}
+	before := req.Status
+	if err := apply(req, subTaskID); err != nil {
+		return err
+	}
+	if err := internal.SaveRequirementAndIndex(root, req); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Sub-task %s#%d moved to %s\n", req.Name, subTaskID, to)
+	if before != req.Status && req.Status == internal.RequirementStatusDone {
+		fmt.Printf("  → Requirement '%s' is done\n", req.Name)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newRequirementListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List requirements",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunRequirementList()
+		},
+	}
+}
+
+func (a *App) RunRequirementList() error {
+	reqs, err := internal.ListRequirements(a.Git.Root())
+	if err != nil {
+		return err
+	}
+	if len(reqs) == 0 {
+		fmt.Println("No requirements found.")
+		return nil
+	}
+
+	fmt.Printf("%-32s %-12s %-10s %s\n", "Requirement", "Status", "Progress", "Description")
+	fmt.Printf("%-32s %-12s %-10s %s\n", "────────────────────────────────", "────────────", "──────────", "────────────────────────")
+	for _, req := range reqs {
+		done := 0
+		for _, st := range req.SubTasks {
+			if st.Status == internal.SubTaskStatusDone || st.Status == internal.SubTaskStatusSkipped {
+				done++
+			}
+		}
+		progress := fmt.Sprintf("%d/%d", done, len(req.SubTasks))
+		fmt.Printf("%-32s %-12s %-10s %s\n", req.Name, req.Status, progress, req.Description)
+	}
+	return nil
+}
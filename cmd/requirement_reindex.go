@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newRequirementReindexCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reindex",
+		Short: "Rebuild the requirement index from requirement.yaml files",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunRequirementReindex()
+		},
+	}
+}
+
+func (a *App) RunRequirementReindex() error {
+	root := a.Git.Root()
+	idx, err := internal.RebuildRequirementIndex(root)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Rebuilt requirement index (%d requirement(s))\n", len(idx.Requirements))
+	fmt.Printf("  → Path: %s\n", internal.RequirementIndexPath(root))
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newRequirementShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a requirement and its sub-tasks",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunRequirementShow(args[0])
+		},
+	}
+}
+
+func (a *App) RunRequirementShow(name string) error {
+	root := a.Git.Root()
+	req, err := internal.LoadRequirement(root, name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Requirement: %s\n", req.Name)
+	fmt.Printf("Status: %s\n", req.Status)
+	fmt.Printf("Created At: %s\n", req.CreatedAt)
+	if req.Description != "" {
+		fmt.Printf("Description: %s\n", req.Description)
+	}
+	if len(req.SubTasks) == 0 {
+		fmt.Println("\nNo sub-tasks. Add some with: agent-team requirement split " + req.Name + " \"<title>\"")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("%-4s %-10s %-20s %-44s %s\n", "ID", "Status", "Assigned To", "Task", "Title")
+	fmt.Printf("%-4s %-10s %-20s %-44s %s\n", "────", "──────────", "────────────────────", "────────────────────────────────────────────", "────────────────────────")
+	for _, st := range req.SubTasks {
+		task := "-"
+		if st.TaskID != "" {
+			task = st.TaskID
+			if record, _, err := internal.LoadTaskRecord(root, st.TaskID); err == nil {
+				task = fmt.Sprintf("%s (%s)", st.TaskID, record.Status)
+			}
+		}
+		fmt.Printf("%-4d %-10s %-20s %-44s %s\n", st.ID, st.Status, dashIfEmpty(st.AssignedTo), task, st.Title)
+	}
+	return nil
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
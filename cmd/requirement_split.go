@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newRequirementSplitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   `split <name> "<sub-task title>" ["<sub-task title>" ...]`,
+		Short: "Add sub-tasks to a requirement",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunRequirementSplit(args[0], args[1:])
+		},
+	}
+}
+
+func (a *App) RunRequirementSplit(name string, titles []string) error {
+	root := a.Git.Root()
+	req, err := internal.LoadRequirement(root, name)
+	if err != nil {
+		return err
+	}
+	added, err := internal.SplitRequirement(req, titles)
+	if err != nil {
+		return err
+	}
+	if err := internal.SaveRequirementAndIndex(root, req); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Added %d sub-task(s) to '%s'\n", len(added), req.Name)
+	for _, st := range added {
+		fmt.Printf("  → #%d %s\n", st.ID, st.Title)
+	}
+	return nil
+}
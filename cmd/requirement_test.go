@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JsonLee12138/agent-team/internal"
+)
+
+func TestRequirementCommandsLifecycle(t *testing.T) {
+	app, dir := initTestApp(t)
+	roleDir := filepath.Join(dir, ".agent-team", "teams", "backend")
+	if err := os.MkdirAll(roleDir, 0755); err != nil {
+		t.Fatalf("mkdir role dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(roleDir, "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatalf("write skill: %v", err)
+	}
+
+	if err := app.RunRequirementCreate("Bad Name", "", nil); err == nil {
+		t.Fatal("non kebab-case name should be rejected")
+	}
+	if err := app.RunRequirementCreate("user-auth", "Login and signup", []string{"Login API"}); err != nil {
+		t.Fatalf("RunRequirementCreate: %v", err)
+	}
+	if err := app.RunRequirementCreate("user-auth", "", nil); err == nil {
+		t.Fatal("duplicate requirement should be rejected")
+	}
+	if err := app.RunRequirementSplit("user-auth", []string{"Signup UI"}); err != nil {
+		t.Fatalf("RunRequirementSplit: %v", err)
+	}
+	if err := app.RunRequirementAssign("user-auth", 1, "backend", false, "", "", "", false); err != nil {
+		t.Fatalf("RunRequirementAssign: %v", err)
+	}
+
+	req, err := internal.LoadRequirement(dir, "user-auth")
+	if err != nil {
+		t.Fatalf("LoadRequirement: %v", err)
+	}
+	taskID := req.SubTasks[0].TaskID
+	if taskID == "" || req.SubTasks[0].Status != internal.SubTaskStatusAssigned {
+		t.Fatalf("sub-task 1 not linked: %+v", req.SubTasks[0])
+	}
+	if _, err := os.Stat(internal.TaskYAMLPath(dir, taskID)); err != nil {
+		t.Fatalf("task package should exist: %v", err)
+	}
+
+	if err := app.RunRequirementDone("user-auth", 2); err == nil {
+		t.Fatal("pending sub-task cannot be marked done")
+	}
+	if err := app.RunRequirementDone("user-auth", 1); err != nil {
+		t.Fatalf("RunRequirementDone: %v", err)
+	}
+	if err := app.RunRequirementSkip("user-auth", 2); err != nil {
+		t.Fatalf("RunRequirementSkip: %v", err)
+	}
+
+	if err := os.Remove(internal.RequirementIndexPath(dir)); err != nil {
+		t.Fatalf("remove index: %v", err)
+	}
+	if err := app.RunRequirementReindex(); err != nil {
+		t.Fatalf("RunRequirementReindex: %v", err)
+	}
+	idx, err := internal.LoadRequirementIndex(dir)
+	if err != nil {
+		t.Fatalf("LoadRequirementIndex: %v", err)
+	}
+	if len(idx.Requirements) != 1 || idx.Requirements[0].Status != internal.RequirementStatusDone || idx.Requirements[0].DoneCount != 2 {
+		t.Fatalf("unexpected index: %+v", idx.Requirements)
+	}
+}
@@ -15,6 +15,10 @@ func newRoleCmd() *cobra.Command {
 	}
 	cmd.AddCommand(newRoleListCmd())
 	cmd.AddCommand(newRoleCreateCmd())
+	cmd.AddCommand(newRoleEditCmd())
+	cmd.AddCommand(newRoleShowCmd())
+	cmd.AddCommand(newRoleLintCmd())
+	cmd.AddCommand(newRolePublishCmd())
 	return cmd
 }
 
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+// roleEditOptions holds the role edit flags. Each list flag is repeatable
+// and accepts comma-separated values.
+type roleEditOptions struct {
+	Description      string
+	SetDescription   bool
+	AddSkills        []string
+	RemoveSkills     []string
+	AddInScope       []string
+	RemoveInScope    []string
+	AddOutOfScope    []string
+	RemoveOutOfScope []string
+	Detach           bool
+}
+
+func newRoleEditCmd() *cobra.Command {
+	var opts roleEditOptions
+	cmd := &cobra.Command{
+		Use:   "edit <role-name>",
+		Short: "Edit an existing role in place",
+		Long:  "Add or remove skills and scope items, or change the description, of an existing role. references/role.yaml is edited in place (comments are kept) and only the affected part of SKILL.md is re-rendered. Roles installed from a role repository are refused unless --detach removes them from roles-lock.json.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.SetDescription = cmd.Flags().Changed("description")
+			return GetApp(cmd).RunRoleEdit(cmd.OutOrStdout(), args[0], opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Description, "description", "", "Replace the role description")
+	cmd.Flags().StringArrayVar(&opts.AddSkills, "add-skill", nil, "Skill(s) to add (repeatable, comma-separated)")
+	cmd.Flags().StringArrayVar(&opts.RemoveSkills, "remove-skill", nil, "Skill(s) to remove (repeatable, comma-separated)")
+	cmd.Flags().StringArrayVar(&opts.AddInScope, "add-in-scope", nil, "In-scope item(s) to add (repeatable, comma-separated)")
+	cmd.Flags().StringArrayVar(&opts.RemoveInScope, "remove-in-scope", nil, "In-scope item(s) to remove (repeatable, comma-separated)")
+	cmd.Flags().StringArrayVar(&opts.AddOutOfScope, "add-out-of-scope", nil, "Out-of-scope item(s) to add (repeatable, comma-separated)")
+	cmd.Flags().StringArrayVar(&opts.RemoveOutOfScope, "remove-out-of-scope", nil, "Out-of-scope item(s) to remove (repeatable, comma-separated)")
+	cmd.Flags().BoolVar(&opts.Detach, "detach", false, "Stop tracking the role in roles-lock.json so it can be edited")
+	return cmd
+}
+
+func (a *App) RunRoleEdit(out io.Writer, roleName string, opts roleEditOptions) error {
+	root := a.Git.Root()
+	edit := internal.RoleEdit{
+		RemoveSkills:     splitRoleEditValues(opts.RemoveSkills),
+		AddInScope:       splitRoleEditValues(opts.AddInScope),
+		RemoveInScope:    splitRoleEditValues(opts.RemoveInScope),
+		AddOutOfScope:    splitRoleEditValues(opts.AddOutOfScope),
+		RemoveOutOfScope: splitRoleEditValues(opts.RemoveOutOfScope),
+	}
+	if opts.SetDescription {
+		edit.Description = &opts.Description
+	}
+	addSkills := splitRoleEditValues(opts.AddSkills)
+	if edit.Description == nil && len(addSkills) == 0 && len(edit.RemoveSkills) == 0 &&
+		len(edit.AddInScope) == 0 && len(edit.RemoveInScope) == 0 && len(edit.AddOutOfScope) == 0 && len(edit.RemoveOutOfScope) == 0 {
+		return fmt.Errorf("nothing to edit: pass --description, --add-skill, --remove-skill or a scope flag")
+	}
+
+	match, err := internal.ResolveRole(root, roleName)
+	if err != nil {
+		return err
+	}
+	scope := internal.RoleRepoScopeProject
+	if match.Scope == "global" {
+		scope = internal.RoleRepoScopeGlobal
+	}
+	lockPath, lock, warning, err := roleRepoLockForScope(root, scope)
+	if err != nil {
+		return err
+	}
+	printRoleRepoLockWarning(warning)
+	entry, tracked := internal.FindRoleRepoLockEntry(lock, match.RoleName)
+	if tracked && !opts.Detach {
+		return fmt.Errorf("role %q is installed from %s and tracked in roles-lock.json; edits would be lost on update. Pass --detach to stop tracking it", match.RoleName, entry.Source)
+	}
+
+	edit.AddSkills = internal.BuildRoleSkillSpecs(root, addSkills)
+	result, err := internal.EditRole(match.Path, edit)
+	if err != nil {
+		return err
+	}
+	if tracked {
+		if _, err := internal.UpdateRoleRepoLock(lockPath, func(lock *internal.RoleRepoLockFile) {
+			internal.RemoveRoleRepoLockEntries(lock, []string{match.RoleName})
+		}); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Detached %s from roles-lock.json\n", match.RoleName)
+	}
+
+	if len(result.Changed) == 0 {
+		fmt.Fprintf(out, "No changes to %s\n", match.RoleName)
+		return nil
+	}
+	fmt.Fprintf(out, "✓ Edited %s (%s)\n", match.RoleName, strings.Join(result.Changed, ", "))
+	return nil
+}
+
+func splitRoleEditValues(values []string) []string {
+	var out []string
+	for _, v := range values {
+		out = append(out, internal.ParseCSVList(v)...)
+	}
+	return internal.DedupeKeepOrder(out)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JsonLee12138/agent-team/internal"
+)
+
+func TestRunRoleEditRefusesLockedRoleUnlessDetached(t *testing.T) {
+	app, root := initTestApp(t)
+	t.Setenv("HOME", t.TempDir())
+	if _, err := internal.CreateOrUpdateRole(root, internal.RoleConfig{
+		RoleName:    "qa",
+		Description: "QA role",
+		SystemGoal:  "Verify changes",
+		InScope:     []string{"Tests"},
+		OutOfScope:  []string{"Deploys"},
+	}, "yes", nil, ".agent-team/teams"); err != nil {
+		t.Fatal(err)
+	}
+	lockPath, err := internal.ResolveRoleRepoLockPath(root, internal.RoleRepoScopeProject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock := internal.RoleRepoLockFile{Version: 1, Entries: []internal.RoleRepoLockEntry{{Name: "qa", Source: "acme/roles", RolePath: "skills/qa", FolderHash: "abc"}}}
+	if err := internal.WriteRoleRepoLock(lockPath, lock); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := roleEditOptions{AddInScope: []string{"E2E tests,Load tests"}}
+	if err := app.RunRoleEdit(&out, "qa", opts); err == nil || !strings.Contains(err.Error(), "--detach") {
+		t.Fatalf("expected locked role refusal, got %v", err)
+	}
+
+	opts.Detach = true
+	if err := app.RunRoleEdit(&out, "qa", opts); err != nil {
+		t.Fatalf("RunRoleEdit --detach: %v", err)
+	}
+	if !strings.Contains(out.String(), "Detached qa") || !strings.Contains(out.String(), "✓ Edited qa (references/role.yaml, SKILL.md)") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+	lock, err = internal.ReadRoleRepoLock(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := internal.FindRoleRepoLockEntry(lock, "qa"); ok {
+		t.Fatal("qa should no longer be tracked")
+	}
+	role, err := internal.ReadRoleDefinition(internal.RoleDir(root, "qa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(role.InScope, "|") != "Tests|E2E tests|Load tests" {
+		t.Fatalf("InScope = %v", role.InScope)
+	}
+
+	if err := app.RunRoleEdit(&out, "qa", roleEditOptions{}); err == nil || !strings.Contains(err.Error(), "nothing to edit") {
+		t.Fatalf("expected nothing-to-edit error, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newRoleLintCmd() *cobra.Command {
+	var all bool
+	var jsonOut bool
+	cmd := &cobra.Command{
+		Use:   "lint [<role-name>|<path>]",
+		Short: "Validate role packages against the role contract",
+		Long:  "Check SKILL.md frontmatter, the references/role.yaml schema, skill resolution, system prompt size and scope consistency. Pass a role name, a role directory path, or --all for every role in .agent-team/teams/. Exits non-zero when any error is found.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := ""
+			if len(args) == 1 {
+				target = args[0]
+			}
+			return GetApp(cmd).RunRoleLint(cmd.OutOrStdout(), target, all, jsonOut)
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "Lint every project role")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	return cmd
+}
+
+type roleLintOutput struct {
+	Roles    []internal.RoleLintResult `json:"roles"`
+	Errors   int                       `json:"errors"`
+	Warnings int                       `json:"warnings"`
+}
+
+func (a *App) RunRoleLint(out io.Writer, target string, all bool, jsonOut bool) error {
+	if (target == "") == !all {
+		return fmt.Errorf("specify a role name or path, or --all")
+	}
+	root := a.Git.Root()
+
+	var results []internal.RoleLintResult
+	if all {
+		results = internal.LintProjectRoles(root)
+	} else {
+		rolePath := target
+		if info, err := os.Stat(target); err != nil || !info.IsDir() {
+			match, err := internal.ResolveRole(root, target)
+			if err != nil {
+				return err
+			}
+			rolePath = match.Path
+		}
+		results = append(results, internal.LintRole(root, rolePath))
+	}
+
+	output := roleLintOutput{Roles: results}
+	if output.Roles == nil {
+		output.Roles = []internal.RoleLintResult{}
+	}
+	for _, r := range results {
+		output.Errors += r.Errors()
+		output.Warnings += r.Warnings()
+	}
+
+	if jsonOut {
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+	} else {
+		if len(results) == 0 {
+			fmt.Fprintln(out, "No roles found in .agent-team/teams/.")
+		}
+		for _, r := range results {
+			if len(r.Issues) == 0 {
+				fmt.Fprintf(out, "✓ %s\n", r.Role)
+				continue
+			}
+			fmt.Fprintf(out, "✗ %s (%s)\n", r.Role, r.Path)
+			for _, issue := range r.Issues {
+				location := issue.Path
+				if location == "" {
+					location = "."
+				}
+				fmt.Fprintf(out, "  %-7s %s: %s\n", issue.Severity, location, issue.Message)
+			}
+		}
+		fmt.Fprintf(out, "Summary: roles=%d errors=%d warnings=%d\n", len(results), output.Errors, output.Warnings)
+	}
+
+	if output.Errors > 0 {
+		return fmt.Errorf("role lint found %d error(s)", output.Errors)
+	}
+	return nil
+}
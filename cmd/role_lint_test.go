@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JsonLee12138/agent-team/internal"
+)
+
+func TestRunRoleLintJSON(t *testing.T) {
+	app, root := initTestApp(t)
+	t.Setenv("HOME", t.TempDir())
+	if _, err := internal.CreateOrUpdateRole(root, internal.RoleConfig{
+		RoleName:    "qa",
+		Description: "QA role",
+		SystemGoal:  "Verify changes",
+		InScope:     []string{"Tests"},
+		OutOfScope:  []string{"Deploys"},
+	}, "yes", nil, ".agent-team/teams"); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(internal.RoleDir(root, "broken"), "references")
+	if err := os.MkdirAll(broken, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(broken, "role.yaml"), []byte("name: broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := app.RunRoleLint(&out, "qa", false, false); err != nil {
+		t.Fatalf("lint qa: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "✓ qa") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	err := app.RunRoleLint(&out, "", true, true)
+	if err == nil {
+		t.Fatal("expected lint --all to fail")
+	}
+	var got roleLintOutput
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out.String())
+	}
+	if len(got.Roles) != 2 || got.Roles[0].Role != "broken" || got.Errors == 0 || len(got.Roles[1].Issues) != 0 {
+		t.Fatalf("unexpected JSON: %+v", got)
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+type rolePublishOptions struct {
+	To      string
+	Branch  string
+	Message string
+	Catalog bool
+}
+
+func newRolePublishCmd() *cobra.Command {
+	var opts rolePublishOptions
+	cmd := &cobra.Command{
+		Use:   "publish <role-name> --to <path-or-git-remote>",
+		Short: "Publish a local role into a role repository",
+		Long:  "Copy a role from .agent-team/teams/, the global roles or skills/ into skills/<role>/ of a role repository and commit it on a branch (publish/<role> by default). --to is either a local checkout or a git remote; remotes are cloned to a temporary directory and the branch is pushed. The role must pass role lint first. --catalog also records the role in .agent-team/catalog.json.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunRolePublish(cmd.OutOrStdout(), args[0], opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.To, "to", "", "Target repository checkout or git remote (required)")
+	cmd.Flags().StringVar(&opts.Branch, "branch", "", "Branch to commit on (default publish/<role>)")
+	cmd.Flags().StringVarP(&opts.Message, "message", "m", "", "Commit message (default \"Publish role <role>\")")
+	cmd.Flags().BoolVar(&opts.Catalog, "catalog", false, "Add or refresh the role in the local catalog")
+	_ = cmd.MarkFlagRequired("to")
+	return cmd
+}
+
+func (a *App) RunRolePublish(out io.Writer, roleName string, opts rolePublishOptions) error {
+	if opts.To == "" {
+		return fmt.Errorf("--to is required")
+	}
+	root := a.Git.Root()
+	rolePath, err := resolvePublishRolePath(root, roleName)
+	if err != nil {
+		return err
+	}
+
+	lint := internal.LintRole(root, rolePath)
+	for _, issue := range lint.Issues {
+		location := issue.Path
+		if location == "" {
+			location = "."
+		}
+		fmt.Fprintf(out, "  %-7s %s: %s\n", issue.Severity, location, issue.Message)
+	}
+	if lint.Errors() > 0 {
+		return fmt.Errorf("role %s has %d lint error(s); fix them before publishing", roleName, lint.Errors())
+	}
+
+	ctx := context.Background()
+	target, cleanup, err := internal.PrepareRolePublishTarget(ctx, opts.To)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	result, err := internal.PublishRole(ctx, rolePath, target, internal.RolePublishOptions{
+		Branch:  opts.Branch,
+		Message: opts.Message,
+	})
+	if err != nil {
+		return err
+	}
+	if result.Unchanged {
+		fmt.Fprintf(out, "✓ %s is already up to date in %s on %s\n", result.RoleName, result.RolePath, result.Branch)
+	} else {
+		fmt.Fprintf(out, "✓ Published %s to %s on %s (%d files)\n", result.RoleName, result.RolePath, result.Branch, len(result.Files))
+	}
+	fmt.Fprintf(out, "  commit:      %s\n", result.Commit)
+	fmt.Fprintf(out, "  folder hash: %s\n", result.FolderHash)
+	if result.Pushed {
+		fmt.Fprintf(out, "✓ Pushed %s to %s\n", result.Branch, target.Remote)
+	} else if target.Remote == "" {
+		fmt.Fprintf(out, "  repository:  %s\n", result.RepoDir)
+	}
+
+	if opts.Catalog {
+		catalogPath := internal.ResolveCatalogPath(root)
+		catalog, err := internal.ReadRoleRepoCatalog(catalogPath)
+		if err != nil {
+			return err
+		}
+		entry := internal.RolePublishCatalogEntry(catalog, result, time.Now)
+		internal.UpsertCatalogEntry(&catalog, entry)
+		if err := internal.WriteRoleRepoCatalog(catalogPath, catalog); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "✓ Catalog entry %s (%s) written to %s\n", entry.Name, entry.Source, catalogPath)
+	}
+	return nil
+}
+
+// resolvePublishRolePath finds a role by name in the usual role locations,
+// falling back to skills/<name> in the project.
+func resolvePublishRolePath(root, roleName string) (string, error) {
+	match, err := internal.ResolveRole(root, roleName)
+	if err == nil {
+		return match.Path, nil
+	}
+	skillPath := filepath.Join(root, "skills", roleName)
+	if _, statErr := os.Stat(filepath.Join(skillPath, "references", "role.yaml")); statErr == nil {
+		return skillPath, nil
+	}
+	return "", err
+}
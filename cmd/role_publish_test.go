@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JsonLee12138/agent-team/internal"
+)
+
+func TestRunRolePublishLintsAndWritesCatalog(t *testing.T) {
+	app, root := initTestApp(t)
+	t.Setenv("HOME", t.TempDir())
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+	created, err := internal.CreateOrUpdateRole(root, internal.RoleConfig{
+		RoleName:    "qa",
+		Description: "QA role",
+		SystemGoal:  "Verify changes",
+		InScope:     []string{"Tests"},
+		OutOfScope:  []string{"Deploys"},
+	}, "yes", nil, ".agent-team/teams")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet", "-b", "main"},
+		{"commit", "--quiet", "--allow-empty", "-m", "init"},
+		{"remote", "add", "origin", "https://github.com/acme/roles.git"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	yamlPath := filepath.Join(created.TargetDir, "references", "role.yaml")
+	original, _ := os.ReadFile(yamlPath)
+	if err := os.WriteFile(yamlPath, []byte(strings.Replace(string(original), "name: qa\n", "name: QA Role\n", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := app.RunRolePublish(&out, "qa", rolePublishOptions{To: repo}); err == nil || !strings.Contains(err.Error(), "lint error") {
+		t.Fatalf("expected lint failure, got %v\n%s", err, out.String())
+	}
+	if err := os.WriteFile(yamlPath, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	if err := app.RunRolePublish(&out, "qa", rolePublishOptions{To: repo, Catalog: true}); err != nil {
+		t.Fatalf("RunRolePublish: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "✓ Published qa to skills/qa on publish/qa") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+	catalog, err := internal.ReadRoleRepoCatalog(internal.ResolveCatalogPath(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := internal.FindCatalogEntry(catalog, "acme/roles", "qa")
+	if !ok || entry.RolePath != "skills/qa" || entry.SourceURL != "https://github.com/acme/roles" || entry.FolderHash == "" {
+		t.Fatalf("unexpected catalog: %+v", catalog.Entries)
+	}
+}
@@ -16,10 +16,12 @@ func newRoleRepoCmd() *cobra.Command {
 	}
 	cmd.AddCommand(newRoleRepoFindCmd())
 	cmd.AddCommand(newRoleRepoAddCmd())
+	cmd.AddCommand(newRoleRepoInstallCmd())
 	cmd.AddCommand(newRoleRepoListCmd())
 	cmd.AddCommand(newRoleRepoRemoveCmd())
 	cmd.AddCommand(newRoleRepoCheckCmd())
 	cmd.AddCommand(newRoleRepoUpdateCmd())
+	cmd.AddCommand(newRoleRepoDiffCmd())
 	return cmd
 }
 
@@ -43,6 +45,15 @@ func roleRepoLockForScope(root string, scope internal.RoleRepoScope) (path strin
 	return path, lock, nil, nil
 }
 
+// roleRepoProvidersForScope anchors relative local sources at the project
+// root for project locks; global locks record absolute paths.
+func roleRepoProvidersForScope(root string, scope internal.RoleRepoScope) *internal.RoleRepoProviderSet {
+	if scope == internal.RoleRepoScopeGlobal {
+		root = ""
+	}
+	return internal.NewRoleRepoProviderSet(root, nil)
+}
+
 func printRoleRepoLockWarning(err error) {
 	if err == nil {
 		return
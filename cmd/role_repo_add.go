@@ -20,7 +20,7 @@ func newRoleRepoAddCmd() *cobra.Command {
 	var yes bool
 
 	cmd := &cobra.Command{
-		Use:   "add <source>",
+		Use:   "add <source>[@<tag|branch|sha>]",
 		Short: "Install roles from a repository source",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -59,7 +59,7 @@ func (a *App) RunRoleRepoAdd(in io.Reader, out io.Writer, sourceArg string, role
 	if err := internal.EnsureRoleRepoInstallRoot(installRoot); err != nil {
 		return err
 	}
-	lockPath, lock, warning, err := roleRepoLockForScope(root, scope)
+	lockPath, _, warning, err := roleRepoLockForScope(root, scope)
 	if err != nil {
 		return err
 	}
@@ -70,8 +70,12 @@ func (a *App) RunRoleRepoAdd(in io.Reader, out io.Writer, sourceArg string, role
 		return err
 	}
 
-	client := internal.NewRoleRepoGitHubClient()
-	roles, err := client.DiscoverRemoteRoles(context.Background(), source)
+	providers := roleRepoProvidersForScope(root, scope)
+	provider, err := providers.ForSource(source)
+	if err != nil {
+		return err
+	}
+	roles, err := internal.ResolveRoleRepoRoles(context.Background(), provider, source)
 	if err != nil {
 		return err
 	}
@@ -113,6 +117,9 @@ func (a *App) RunRoleRepoAdd(in io.Reader, out io.Writer, sourceArg string, role
 		}
 		fmt.Fprintf(out, "\nInstall summary:\n")
 		fmt.Fprintf(out, "  Source: %s\n", source.FullName())
+		if source.Ref != "" {
+			fmt.Fprintf(out, "  Ref:    %s\n", source.Ref)
+		}
 		fmt.Fprintf(out, "  Scope:  %s (%s)\n", scopeLabel, installRoot)
 		fmt.Fprintf(out, "  Roles:  ")
 		names := make([]string, len(selected))
@@ -135,9 +142,10 @@ func (a *App) RunRoleRepoAdd(in io.Reader, out io.Writer, sourceArg string, role
 	success := 0
 	failed := 0
 	installed := make([]internal.RoleRepoRemoteRole, 0, len(selected))
+	entries := make([]internal.RoleRepoLockEntry, 0, len(selected))
 	overwritePolicy := &roleOverwritePolicy{}
 	for _, role := range selected {
-		_, installErr := internal.InstallRoleRepoRemoteRole(context.Background(), client, role, installRoot, overwrite)
+		_, installErr := internal.InstallRoleRepoRemoteRole(context.Background(), provider, role, installRoot, overwrite)
 		if installErr != nil {
 			if errors.Is(installErr, internal.ErrRoleRepoInstallConflict) {
 				shouldOverwrite, decideErr := decideRoleOverwriteOnConflict(
@@ -156,7 +164,7 @@ func (a *App) RunRoleRepoAdd(in io.Reader, out io.Writer, sourceArg string, role
 					fmt.Fprintf(out, "- skipped %s (already exists)\n", role.Candidate.Name)
 					continue
 				}
-				_, installErr = internal.InstallRoleRepoRemoteRole(context.Background(), client, role, installRoot, true)
+				_, installErr = internal.InstallRoleRepoRemoteRole(context.Background(), provider, role, installRoot, true)
 				if installErr != nil {
 					fmt.Fprintf(out, "- failed %s: %v\n", role.Candidate.Name, installErr)
 					failed++
@@ -170,24 +178,31 @@ func (a *App) RunRoleRepoAdd(in io.Reader, out io.Writer, sourceArg string, role
 		}
 		entry := internal.RoleRepoLockEntry{
 			Name:        role.Candidate.Name,
-			Source:      source.FullName(),
+			Source:      providers.LockSource(source),
 			SourceType:  role.Candidate.SourceType,
 			SourceURL:   role.Candidate.SourceURL,
 			RolePath:    role.Candidate.RolePath,
+			Ref:         source.Ref,
+			Commit:      role.Commit,
 			FolderHash:  role.FolderHash,
 			InstalledAt: now,
 			UpdatedAt:   now,
 		}
-		if existing, ok := internal.FindRoleRepoLockEntry(lock, role.Candidate.Name); ok {
-			entry.InstalledAt = existing.InstalledAt
-		}
-		internal.UpsertRoleRepoLockEntry(&lock, entry)
+		entries = append(entries, entry)
+		internal.EmitEvent(root, internal.Event{Type: internal.EventRoleInstalled, Subject: entry.Name, Role: entry.Name, Data: map[string]string{"source": entry.Source, "scope": string(scope), "folder_hash": entry.FolderHash}})
 		fmt.Fprintf(out, "+ installed %s\n", role.Candidate.Name)
 		success++
 		installed = append(installed, role)
 	}
 
-	if err := internal.WriteRoleRepoLock(lockPath, lock); err != nil {
+	if _, err := internal.UpdateRoleRepoLock(lockPath, func(lock *internal.RoleRepoLockFile) {
+		for _, entry := range entries {
+			if existing, ok := internal.FindRoleRepoLockEntry(*lock, entry.Name); ok {
+				entry.InstalledAt = existing.InstalledAt
+			}
+			internal.UpsertRoleRepoLockEntry(lock, entry)
+		}
+	}); err != nil {
 		return err
 	}
 	reportRoleRepoInstallIngest(source, installed)
@@ -35,8 +35,8 @@ func (a *App) RunRoleRepoCheck(global bool) error {
 	}
 	printRoleRepoLockWarning(warning)
 
-	client := internal.NewRoleRepoGitHubClient()
-	statuses, untracked := internal.CheckRoleRepoUpdates(context.Background(), client, installRoot, lock)
+	providers := roleRepoProvidersForScope(root, scope)
+	statuses, untracked := internal.CheckRoleRepoUpdates(context.Background(), providers, installRoot, lock)
 	fmt.Print(internal.FormatRoleRepoCheckSummary(statuses, untracked))
 
 	updates := 0
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newRoleRepoDiffCmd() *cobra.Command {
+	var global bool
+	cmd := &cobra.Command{
+		Use:   "diff <role>",
+		Short: "Show differences between an installed role and its remote source",
+		Long:  "Fetch the role's remote folder at its pinned ref and print a unified diff for every file that differs from the installed copy. Files edited since install are detected by re-hashing the installed folder against roles-lock.json.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunRoleRepoDiff(cmd.OutOrStdout(), args[0], global)
+		},
+	}
+	cmd.Flags().BoolVarP(&global, "global", "g", false, "Diff a global role install")
+	return cmd
+}
+
+func (a *App) RunRoleRepoDiff(out io.Writer, name string, global bool) error {
+	root := a.Git.Root()
+	scope := roleRepoScopeFromFlag(global)
+	installRoot, err := internal.ResolveRoleRepoInstallRoot(root, scope)
+	if err != nil {
+		return err
+	}
+	_, lock, warning, err := roleRepoLockForScope(root, scope)
+	if err != nil {
+		return err
+	}
+	printRoleRepoLockWarning(warning)
+	entry, ok := internal.FindRoleRepoLockEntry(lock, name)
+	if !ok {
+		return fmt.Errorf("role %q is not in the lock file", name)
+	}
+
+	providers := roleRepoProvidersForScope(root, scope)
+	diff, err := internal.DiffRoleRepoRole(context.Background(), providers, installRoot, entry)
+	if err != nil {
+		return fmt.Errorf("diff %s: %w", name, err)
+	}
+
+	source := entry.Source
+	if entry.Ref != "" {
+		source += "@" + entry.Ref
+	}
+	fmt.Fprintf(out, "Role: %s\n", name)
+	fmt.Fprintf(out, "Source: %s\n", source)
+	fmt.Fprintf(out, "Locked: %s\n", formatRoleRepoDiffVersion(entry.FolderHash, entry.Commit))
+	fmt.Fprintf(out, "Remote: %s\n", formatRoleRepoDiffVersion(diff.RemoteHash, diff.RemoteCommit))
+	switch {
+	case !diff.LocalModified:
+		fmt.Fprintln(out, "Local changes: none")
+	case diff.BaseKnown:
+		fmt.Fprintf(out, "Local changes: %s\n", strings.Join(diff.ModifiedFiles(), ", "))
+	default:
+		fmt.Fprintln(out, "Local changes: yes (locked version unavailable)")
+	}
+
+	changed := 0
+	for _, f := range diff.Files {
+		text := internal.UnifiedDiff("installed/"+f.Path, "remote/"+f.Path, f.Local, f.Remote, 3)
+		if text == "" {
+			continue
+		}
+		changed++
+		fmt.Fprintln(out)
+		fmt.Fprint(out, text)
+	}
+	if changed == 0 {
+		fmt.Fprintln(out, "No differences between the installed and remote role.")
+	}
+	return nil
+}
+
+func formatRoleRepoDiffVersion(hash, commit string) string {
+	if commit == "" {
+		return hash
+	}
+	return fmt.Sprintf("%s (%s)", hash, commit[:min(len(commit), 12)])
+}
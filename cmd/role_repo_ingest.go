@@ -29,7 +29,8 @@ var newRoleHubReporter = func() roleHubReporter {
 }
 
 func reportRoleRepoInstallIngest(source internal.RoleRepoSource, installed []internal.RoleRepoRemoteRole) {
-	if len(installed) == 0 {
+	// Only public GitHub roles are reported; other sources may be private.
+	if len(installed) == 0 || (source.Type != "" && source.Type != internal.RoleRepoSourceGitHub) {
 		return
 	}
 
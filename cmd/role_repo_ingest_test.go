@@ -56,6 +56,25 @@ func TestReportRoleRepoInstallIngestReportsInstalledRoles(t *testing.T) {
 	}
 }
 
+func TestReportRoleRepoInstallIngestSkipsNonGitHubSources(t *testing.T) {
+	origReporter := newRoleHubReporter
+	t.Cleanup(func() { newRoleHubReporter = origReporter })
+
+	reporter := &captureReporter{}
+	newRoleHubReporter = func() roleHubReporter { return reporter }
+
+	source := internal.RoleRepoSource{Type: internal.RoleRepoSourceGitea, Owner: "acme", Repo: "roles", BaseURL: "https://git.internal"}
+	installed := []internal.RoleRepoRemoteRole{
+		{Candidate: internal.RoleRepoCandidate{Name: "frontend", RolePath: "skills/frontend"}},
+	}
+
+	reportRoleRepoInstallIngest(source, installed)
+
+	if reporter.calls != 0 {
+		t.Fatalf("expected no ingest call for %s source, got %d", source.Type, reporter.calls)
+	}
+}
+
 func TestReportRoleRepoInstallIngestSkipsWhenNoInstalledRoles(t *testing.T) {
 	origReporter := newRoleHubReporter
 	t.Cleanup(func() { newRoleHubReporter = origReporter })
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newRoleRepoInstallCmd() *cobra.Command {
+	var global bool
+	cmd := &cobra.Command{
+		Use:   "install [role...]",
+		Short: "Install roles exactly as recorded in roles-lock.json",
+		Long:  "Restore every role in the lock file (or only the named ones) from its locked commit, replacing existing role directories. Fails when the source no longer matches the locked folder hash; the lock file is never changed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunRoleRepoInstall(cmd.OutOrStdout(), args, global)
+		},
+	}
+	cmd.Flags().BoolVarP(&global, "global", "g", false, "Install from the global lock file")
+	return cmd
+}
+
+func (a *App) RunRoleRepoInstall(out io.Writer, names []string, global bool) error {
+	root := a.Git.Root()
+	scope := roleRepoScopeFromFlag(global)
+	installRoot, err := internal.ResolveRoleRepoInstallRoot(root, scope)
+	if err != nil {
+		return err
+	}
+	if err := internal.EnsureRoleRepoInstallRoot(installRoot); err != nil {
+		return err
+	}
+	_, lock, warning, err := roleRepoLockForScope(root, scope)
+	if err != nil {
+		return err
+	}
+	if warning != nil {
+		return fmt.Errorf("cannot install from lock: %w", warning)
+	}
+	if len(lock.Entries) == 0 {
+		fmt.Fprintln(out, "No lock entries found.")
+		return nil
+	}
+
+	providers := roleRepoProvidersForScope(root, scope)
+	installed, failed := internal.InstallRoleRepoFromLock(context.Background(), providers, installRoot, lock, names)
+	for _, name := range installed {
+		entry, _ := internal.FindRoleRepoLockEntry(lock, name)
+		data := map[string]string{"source": entry.Source, "scope": string(scope), "folder_hash": entry.FolderHash}
+		if entry.Commit != "" {
+			data["commit"] = entry.Commit
+		}
+		internal.EmitEvent(root, internal.Event{Type: internal.EventRoleInstalled, Subject: name, Role: name, Data: data})
+		if entry.Commit != "" {
+			fmt.Fprintf(out, "+ installed %s (%s)\n", name, entry.Commit[:min(len(entry.Commit), 12)])
+		} else {
+			fmt.Fprintf(out, "+ installed %s\n", name)
+		}
+	}
+	failedNames := make([]string, 0, len(failed))
+	for name := range failed {
+		failedNames = append(failedNames, name)
+	}
+	sort.Strings(failedNames)
+	for _, name := range failedNames {
+		fmt.Fprintf(out, "- failed %s: %v\n", name, failed[name])
+	}
+	fmt.Fprintf(out, "Summary: installed=%d failed=%d\n", len(installed), len(failed))
+	if len(failed) > 0 {
+		return fmt.Errorf("one or more roles failed to install")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JsonLee12138/agent-team/internal"
+)
+
+func TestRunRoleRepoInstallRestoresLockedFiles(t *testing.T) {
+	app, root := initTestApp(t)
+	sourceDir := filepath.Join(root, "shared-roles", "skills", "frontend", "references")
+	if err := os.MkdirAll(sourceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sourceDir, "role.yaml"), []byte("name: frontend\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := app.RunRoleRepoAdd(strings.NewReader(""), &out, filepath.Join(root, "shared-roles"), nil, false, true, false, true); err != nil {
+		t.Fatalf("RunRoleRepoAdd: %v\n%s", err, out.String())
+	}
+	lockPath, err := internal.ResolveRoleRepoLockPath(root, internal.RoleRepoScopeProject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := internal.ReadRoleRepoLock(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := internal.FindRoleRepoLockEntry(lock, "frontend")
+	if !ok || entry.Source != "./shared-roles" || entry.SourceType != internal.RoleRepoSourceLocal {
+		t.Fatalf("unexpected lock entry: %+v", entry)
+	}
+
+	installRoot, err := internal.ResolveRoleRepoInstallRoot(root, internal.RoleRepoScopeProject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed := filepath.Join(installRoot, "frontend", "references", "role.yaml")
+	if err := os.WriteFile(installed, []byte("local edit\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	if err := app.RunRoleRepoInstall(&out, nil, false); err != nil {
+		t.Fatalf("RunRoleRepoInstall: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "+ installed frontend") || !strings.Contains(out.String(), "Summary: installed=1 failed=0") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+	if data, _ := os.ReadFile(installed); string(data) != "name: frontend\n" {
+		t.Fatalf("role.yaml not restored: %q", data)
+	}
+
+	// A source that drifted from the lock is refused rather than installed.
+	if err := os.WriteFile(filepath.Join(sourceDir, "role.yaml"), []byte("name: frontend\nversion: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := app.RunRoleRepoInstall(&out, nil, false); err == nil {
+		t.Fatalf("expected install to fail on folder hash mismatch:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "folder hash mismatch") {
+		t.Fatalf("expected mismatch in output:\n%s", out.String())
+	}
+}
@@ -29,6 +29,8 @@ type roleListEntry struct {
 	Name      string `json:"name"`
 	Source    string `json:"source"`
 	RolePath  string `json:"rolePath"`
+	Ref       string `json:"ref,omitempty"`
+	Commit    string `json:"commit,omitempty"`
 	UpdatedAt string `json:"updatedAt,omitempty"`
 }
 
@@ -121,6 +123,8 @@ func collectScopeEntries(root string, scope internal.RoleRepoScope) []roleListEn
 		if lockEntry, ok := internal.FindRoleRepoLockEntry(lock, name); ok {
 			entry.Source = lockEntry.Source
 			entry.RolePath = lockEntry.RolePath
+			entry.Ref = lockEntry.Ref
+			entry.Commit = lockEntry.Commit
 			if !lockEntry.UpdatedAt.IsZero() {
 				entry.UpdatedAt = lockEntry.UpdatedAt.Format("2006-01-02")
 			}
@@ -138,6 +142,10 @@ func printRoleTable(entries []roleListEntry) {
 		if updated == "" {
 			updated = "-"
 		}
-		fmt.Printf("  %-20s %-28s %-12s %s\n", e.Name, e.Source, updated, e.RolePath)
+		source := e.Source
+		if e.Ref != "" {
+			source += "@" + e.Ref
+		}
+		fmt.Printf("  %-20s %-28s %-12s %s\n", e.Name, source, updated, e.RolePath)
 	}
 }
@@ -32,7 +32,7 @@ func (a *App) RunRoleRepoRemove(in io.Reader, out io.Writer, roleNames []string,
 	if err != nil {
 		return err
 	}
-	lockPath, lock, warning, err := roleRepoLockForScope(root, scope)
+	lockPath, _, warning, err := roleRepoLockForScope(root, scope)
 	if err != nil {
 		return err
 	}
@@ -76,13 +76,15 @@ func (a *App) RunRoleRepoRemove(in io.Reader, out io.Writer, roleNames []string,
 
 	removed, missing, failed := internal.RemoveInstalledRoleRepo(installRoot, roleNames)
 	if len(removed) > 0 {
-		internal.RemoveRoleRepoLockEntries(&lock, removed)
-		if err := internal.WriteRoleRepoLock(lockPath, lock); err != nil {
+		if _, err := internal.UpdateRoleRepoLock(lockPath, func(lock *internal.RoleRepoLockFile) {
+			internal.RemoveRoleRepoLockEntries(lock, removed)
+		}); err != nil {
 			return err
 		}
 	}
 
 	for _, name := range removed {
+		internal.EmitEvent(root, internal.Event{Type: internal.EventRoleRemoved, Subject: name, Role: name, Data: map[string]string{"scope": string(scope)}})
 		fmt.Fprintf(out, "- removed %s\n", name)
 	}
 	for _, name := range missing {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/JsonLee12138/agent-team/internal"
@@ -13,20 +14,28 @@ import (
 func newRoleRepoUpdateCmd() *cobra.Command {
 	var global bool
 	var yes bool
+	var toRef string
+	var force bool
 	cmd := &cobra.Command{
-		Use:   "update",
+		Use:   "update [role...]",
 		Short: "Update installed roles from remote sources",
-		Args:  cobra.NoArgs,
+		Long:  "Update installed roles to the latest files at their pinned ref (the default branch when unpinned). Use --to to move the named roles to another tag, branch or commit. Local edits to an installed role are merged with the remote changes; the update fails on conflicts unless --force discards the edits.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return GetApp(cmd).RunRoleRepoUpdate(cmd.InOrStdin(), cmd.OutOrStdout(), global, yes)
+			return GetApp(cmd).RunRoleRepoUpdate(cmd.InOrStdin(), cmd.OutOrStdout(), args, global, yes, toRef, force)
 		},
 	}
 	cmd.Flags().BoolVarP(&global, "global", "g", false, "Update global role installs")
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Overwrite existing role directories during update")
+	cmd.Flags().StringVar(&toRef, "to", "", "Move the named roles to this tag, branch or commit")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite local edits instead of merging them")
 	return cmd
 }
 
-func (a *App) RunRoleRepoUpdate(in io.Reader, out io.Writer, global bool, yes bool) error {
+func (a *App) RunRoleRepoUpdate(in io.Reader, out io.Writer, names []string, global bool, yes bool, toRef string, force bool) error {
+	toRef = strings.TrimSpace(toRef)
+	if toRef != "" && len(names) == 0 {
+		return fmt.Errorf("--to requires at least one role name")
+	}
 	root := a.Git.Root()
 	scope := roleRepoScopeFromFlag(global)
 	installRoot, err := internal.ResolveRoleRepoInstallRoot(root, scope)
@@ -39,43 +48,99 @@ func (a *App) RunRoleRepoUpdate(in io.Reader, out io.Writer, global bool, yes bo
 	}
 	printRoleRepoLockWarning(warning)
 
-	client := internal.NewRoleRepoGitHubClient()
-	statuses, _ := internal.CheckRoleRepoUpdates(context.Background(), client, installRoot, lock)
-	candidates := make([]string, 0)
-	for _, st := range statuses {
-		if st.State == "update_available" {
-			candidates = append(candidates, st.Name)
+	for _, name := range names {
+		if _, ok := internal.FindRoleRepoLockEntry(lock, name); !ok {
+			return fmt.Errorf("role %q is not in the lock file", name)
 		}
 	}
 
-	selected := candidates
+	providers := roleRepoProvidersForScope(root, scope)
+	var selected []string
 	overwrite := true
-	if !yes {
-		if len(candidates) == 0 {
-			fmt.Fprintln(out, "No updates available.")
-			return nil
+	if toRef != "" {
+		// Moving a pin always reinstalls the named roles at the new ref.
+		selected = names
+		if !yes {
+			ok, confirmErr := promptConfirm(in, out, fmt.Sprintf("Move %d role(s) to %s?", len(selected), toRef))
+			if confirmErr != nil {
+				return confirmErr
+			}
+			if !ok {
+				return fmt.Errorf("update cancelled")
+			}
 		}
-		chosen, selectErr := promptSelectNames(in, out, "Select role(s) to update:", candidates)
-		if selectErr != nil {
-			return selectErr
+	} else {
+		statuses, _ := internal.CheckRoleRepoUpdates(context.Background(), providers, installRoot, lock)
+		wanted := map[string]bool{}
+		for _, name := range names {
+			wanted[name] = true
 		}
-		selected = chosen
-		ok, confirmErr := promptConfirm(in, out, fmt.Sprintf("Update %d role(s)?", len(selected)))
-		if confirmErr != nil {
-			return confirmErr
+		candidates := make([]string, 0)
+		for _, st := range statuses {
+			if st.State == "update_available" && (len(names) == 0 || wanted[st.Name]) {
+				candidates = append(candidates, st.Name)
+			}
 		}
-		if !ok {
-			return fmt.Errorf("update cancelled")
+
+		selected = candidates
+		if !yes {
+			if len(candidates) == 0 {
+				fmt.Fprintln(out, "No updates available.")
+				return nil
+			}
+			if len(names) == 0 {
+				chosen, selectErr := promptSelectNames(in, out, "Select role(s) to update:", candidates)
+				if selectErr != nil {
+					return selectErr
+				}
+				selected = chosen
+			}
+			ok, confirmErr := promptConfirm(in, out, fmt.Sprintf("Update %d role(s)?", len(selected)))
+			if confirmErr != nil {
+				return confirmErr
+			}
+			if !ok {
+				return fmt.Errorf("update cancelled")
+			}
+		} else if len(names) > 0 && len(candidates) == 0 {
+			// Named roles with nothing to update: keep them out of the
+			// "update all" path below.
+			selected = names
 		}
 	}
 
-	updated, skipped, failed := internal.UpdateRoleRepoFromLock(context.Background(), client, installRoot, &lock, overwrite, selected, time.Now)
-	if err := internal.WriteRoleRepoLock(lockPath, lock); err != nil {
+	loaded := make(map[string]internal.RoleRepoLockEntry, len(lock.Entries))
+	for _, entry := range lock.Entries {
+		loaded[entry.Name] = entry
+	}
+	updated, skipped, failed := internal.UpdateRoleRepoFromLock(context.Background(), providers, installRoot, &lock, overwrite, selected, toRef, force, time.Now)
+	// Write back only the entries this run changed; others may have been
+	// added or removed by a concurrent role-repo command meanwhile.
+	if _, err := internal.UpdateRoleRepoLock(lockPath, func(current *internal.RoleRepoLockFile) {
+		for _, entry := range lock.Entries {
+			if entry != loaded[entry.Name] {
+				internal.UpsertRoleRepoLockEntry(current, entry)
+			}
+		}
+	}); err != nil {
 		return err
 	}
 
 	for _, name := range updated {
-		fmt.Fprintf(out, "+ updated %s\n", name)
+		entry, _ := internal.FindRoleRepoLockEntry(lock, name)
+		data := map[string]string{"scope": string(scope)}
+		if entry.Ref != "" {
+			data["ref"] = entry.Ref
+		}
+		if entry.Commit != "" {
+			data["commit"] = entry.Commit
+		}
+		internal.EmitEvent(root, internal.Event{Type: internal.EventRoleUpdated, Subject: name, Role: name, Data: data})
+		if entry.Ref != "" {
+			fmt.Fprintf(out, "+ updated %s (%s)\n", name, entry.Ref)
+		} else {
+			fmt.Fprintf(out, "+ updated %s\n", name)
+		}
 	}
 	for _, name := range skipped {
 		if yes {
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newRoleShowCmd() *cobra.Command {
+	var resolved bool
+	var jsonOut bool
+	cmd := &cobra.Command{
+		Use:   "show <role-name>",
+		Short: "Show a role definition",
+		Long:  "Show a role's role.yaml fields and system prompt. With --resolved, the roles it extends and mixes in are merged in to show the effective role a worker receives.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunRoleShow(cmd.OutOrStdout(), args[0], resolved, jsonOut)
+		},
+	}
+	cmd.Flags().BoolVar(&resolved, "resolved", false, "Merge extends and mixins into the effective role")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	return cmd
+}
+
+type roleShowSkill struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type roleShowOutput struct {
+	Name         string          `json:"name"`
+	Path         string          `json:"path"`
+	Scope        string          `json:"scope"`
+	Resolved     bool            `json:"resolved"`
+	Extends      string          `json:"extends,omitempty"`
+	Mixins       []string        `json:"mixins,omitempty"`
+	Chain        []string        `json:"chain,omitempty"`
+	Description  string          `json:"description"`
+	InScope      []string        `json:"inScope"`
+	OutOfScope   []string        `json:"outOfScope"`
+	Skills       []roleShowSkill `json:"skills"`
+	Constraints  map[string]any  `json:"constraints,omitempty"`
+	SystemPrompt string          `json:"systemPrompt"`
+}
+
+func (a *App) RunRoleShow(out io.Writer, roleName string, resolved bool, jsonOut bool) error {
+	root := a.Git.Root()
+	match, err := internal.ResolveRole(root, roleName)
+	if err != nil {
+		return err
+	}
+	var def *internal.RoleDefinition
+	if resolved {
+		def, err = internal.ResolveRoleFromPath(match.Path)
+	} else {
+		def, err = internal.ReadRoleDefinition(match.Path)
+	}
+	if err != nil {
+		return fmt.Errorf("read role %s: %w", roleName, err)
+	}
+
+	if jsonOut {
+		output := roleShowOutput{
+			Name:         def.Name,
+			Path:         match.Path,
+			Scope:        match.Scope,
+			Resolved:     resolved,
+			Extends:      def.Extends,
+			Mixins:       def.Mixins,
+			Description:  def.Description,
+			InScope:      nonNilStrings(def.InScope),
+			OutOfScope:   nonNilStrings(def.OutOfScope),
+			Skills:       []roleShowSkill{},
+			Constraints:  def.Constraints,
+			SystemPrompt: def.SystemPrompt,
+		}
+		if resolved {
+			output.Chain = def.Chain
+		}
+		for _, skill := range def.Skills {
+			output.Skills = append(output.Skills, roleShowSkill{Name: skill.Name, Description: skill.Description})
+		}
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(out, "Role: %s (%s)\n", def.Name, match.Scope)
+	fmt.Fprintf(out, "Path: %s\n", match.Path)
+	if def.Extends != "" {
+		fmt.Fprintf(out, "Extends: %s\n", def.Extends)
+	}
+	if len(def.Mixins) > 0 {
+		fmt.Fprintf(out, "Mixins: %s\n", strings.Join(def.Mixins, ", "))
+	}
+	if resolved && len(def.Chain) > 1 {
+		fmt.Fprintf(out, "Resolved from: %s\n", strings.Join(def.Chain, " -> "))
+	}
+	if def.Description != "" {
+		fmt.Fprintf(out, "Description: %s\n", def.Description)
+	}
+	writeRoleShowList(out, "In scope", def.InScope)
+	writeRoleShowList(out, "Out of scope", def.OutOfScope)
+	fmt.Fprintln(out, "Skills:")
+	if len(def.Skills) == 0 {
+		fmt.Fprintln(out, "  (none)")
+	}
+	for _, skill := range def.Skills {
+		fmt.Fprintf(out, "  - %s: %s\n", skill.Name, skill.Description)
+	}
+	if len(def.Constraints) > 0 {
+		fmt.Fprintln(out, "Constraints:")
+		for _, key := range def.ConstraintKeys() {
+			fmt.Fprintf(out, "  %s: %v\n", key, def.Constraints[key])
+		}
+	}
+	if strings.TrimSpace(def.SystemPrompt) != "" {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "System prompt:")
+		fmt.Fprint(out, def.SystemPrompt)
+		if !strings.HasSuffix(def.SystemPrompt, "\n") {
+			fmt.Fprintln(out)
+		}
+	}
+	return nil
+}
+
+func writeRoleShowList(out io.Writer, title string, items []string) {
+	fmt.Fprintf(out, "%s:\n", title)
+	if len(items) == 0 {
+		fmt.Fprintln(out, "  (none)")
+	}
+	for _, item := range items {
+		fmt.Fprintf(out, "  - %s\n", item)
+	}
+}
+
+func nonNilStrings(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
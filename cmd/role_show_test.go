@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JsonLee12138/agent-team/internal"
+)
+
+func TestRunRoleShowResolved(t *testing.T) {
+	app, root := initTestApp(t)
+	t.Setenv("HOME", t.TempDir())
+	write := func(name, roleYAML, systemMD string) {
+		t.Helper()
+		dir := internal.RoleDir(root, name)
+		if err := os.MkdirAll(filepath.Join(dir, "references"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "references", "role.yaml"), []byte(roleYAML), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "system.md"), []byte(systemMD), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("react-dev", "name: react-dev\ndescription: React\nscope:\n  in_scope: [components]\nskills:\n  - name: react\n    description: React patterns\n", "# react-dev\n\n## Stack\n\nVite.\n")
+	write("vite-react-dev", "name: vite-react-dev\nextends: react-dev\nscope:\n  in_scope: [vite config]\n", "## Build\n\nvite build\n")
+
+	var out bytes.Buffer
+	if err := app.RunRoleShow(&out, "vite-react-dev", false, false); err != nil {
+		t.Fatalf("RunRoleShow: %v", err)
+	}
+	if !strings.Contains(out.String(), "Extends: react-dev") || strings.Contains(out.String(), "components") {
+		t.Fatalf("unresolved output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := app.RunRoleShow(&out, "vite-react-dev", true, false); err != nil {
+		t.Fatalf("RunRoleShow --resolved: %v", err)
+	}
+	for _, want := range []string{"Resolved from: react-dev -> vite-react-dev", "Description: React", "  - components", "  - vite config", "  - react: React patterns", "## Stack", "## Build"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("resolved output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := app.RunRoleShow(&out, "vite-react-dev", true, true); err != nil {
+		t.Fatalf("RunRoleShow --json: %v", err)
+	}
+	var got roleShowOutput
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out.String())
+	}
+	if !got.Resolved || len(got.Skills) != 1 || len(got.InScope) != 2 || got.Scope != "project" {
+		t.Fatalf("unexpected JSON: %+v", got)
+	}
+}
@@ -53,7 +53,8 @@ func NewRootCmd() *cobra.Command {
 		// Check if .agent-team/rules/ exists (initialization check)
 		if requiresInitialization && !internal.HasRulesDir(gc.Root()) {
 			// Check if running in non-interactive mode
-			nonInteractive := os.Getenv("AGENT_TEAM_NONINTERACTIVE") == "1"
+			// The MCP server owns stdin/stdout for the protocol and cannot prompt.
+			nonInteractive := os.Getenv("AGENT_TEAM_NONINTERACTIVE") == "1" || cmd.CommandPath() == "agent-team mcp"
 
 			if nonInteractive {
 				return fmt.Errorf(".agent-team/rules/ not found. Run 'agent-team init' first (or set AGENT_TEAM_NONINTERACTIVE=0 for interactive mode)")
@@ -120,4 +121,9 @@ func RegisterCommands(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(newWorkflowCmd())
 	rootCmd.AddCommand(newTaskCmd())
 	rootCmd.AddCommand(newPlanningCmd())
+	rootCmd.AddCommand(newEventsCmd())
+	rootCmd.AddCommand(newServeCmd())
+	rootCmd.AddCommand(newMCPCmd())
+	rootCmd.AddCommand(newRequirementCmd())
+	rootCmd.AddCommand(newSearchCmd())
 }
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newSearchCmd() *cobra.Command {
+	var kind, status, role, since string
+	var limit int
+	var rebuild bool
+	cmd := &cobra.Command{
+		Use:   "search <query> [--kind task|planning|rule] [--status <status>] [--role <role>] [--since <time|duration>]",
+		Short: "Full-text search over tasks, planning and rules",
+		Long: "Indexes task.yaml, context.md and verification.md of every task package, planning YAML and\n" +
+			"markdown, and .agent-team/rules/*.md into .agent-team/.cache/search-index.json. Only files whose\n" +
+			"size or mtime changed are re-indexed. Every query term must match; results are ranked with\n" +
+			"title matches first and printed as path:line plus the matching line.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sinceTime, err := parseSinceFlag(since, time.Now().UTC())
+			if err != nil {
+				return err
+			}
+			query := internal.SearchQuery{
+				Text:   strings.Join(args, " "),
+				Kind:   internal.SearchKind(kind),
+				Status: status,
+				Role:   role,
+				Since:  sinceTime,
+				Limit:  limit,
+			}
+			return GetApp(cmd).RunSearch(os.Stdout, query, rebuild)
+		},
+	}
+	cmd.Flags().StringVar(&kind, "kind", "", "Only search task, planning, or rule documents")
+	cmd.Flags().StringVar(&status, "status", "", "Only match tasks or planning records with this status")
+	cmd.Flags().StringVar(&role, "role", "", "Only match tasks bound to this role")
+	cmd.Flags().StringVar(&since, "since", "", "Only match files modified at or after this time")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of results")
+	cmd.Flags().BoolVar(&rebuild, "rebuild", false, "Discard the cached index and rebuild it")
+	return cmd
+}
+
+func (a *App) RunSearch(out io.Writer, query internal.SearchQuery, rebuild bool) error {
+	switch query.Kind {
+	case "", internal.SearchKindTask, internal.SearchKindPlanning, internal.SearchKindRule:
+	default:
+		return fmt.Errorf("invalid --kind %q: use task, planning, or rule", query.Kind)
+	}
+	root := a.Git.Root()
+	idx, _, err := internal.UpdateSearchIndex(root, rebuild)
+	if err != nil {
+		return err
+	}
+	results := idx.Search(root, query)
+	if len(results) == 0 {
+		fmt.Fprintf(out, "No matches for %q.\n", query.Text)
+		return nil
+	}
+	for _, result := range results {
+		doc := result.Doc
+		header := fmt.Sprintf("[%s] %s", doc.Kind, doc.ID)
+		if doc.Title != "" {
+			header += " — " + doc.Title
+		}
+		var meta []string
+		if doc.Status != "" {
+			meta = append(meta, doc.Status)
+		}
+		if doc.Role != "" {
+			meta = append(meta, "role: "+doc.Role)
+		}
+		if len(meta) > 0 {
+			header += " (" + strings.Join(meta, ", ") + ")"
+		}
+		fmt.Fprintln(out, header)
+		if result.Line > 0 {
+			fmt.Fprintf(out, "  %s:%d  %s\n", doc.Path, result.Line, result.Snippet)
+		} else {
+			fmt.Fprintf(out, "  %s\n", doc.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JsonLee12138/agent-team/internal"
+)
+
+func TestRunSearchPrintsPathsAndSnippets(t *testing.T) {
+	app, dir := initTestApp(t)
+	roleDir := filepath.Join(dir, ".agent-team", "teams", "backend")
+	if err := os.MkdirAll(roleDir, 0755); err != nil {
+		t.Fatalf("mkdir role dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(roleDir, "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatalf("write skill: %v", err)
+	}
+	if err := app.RunTaskCreate("Harden webhook retries", "backend", "", "", ""); err != nil {
+		t.Fatalf("RunTaskCreate: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := app.RunSearch(&out, internal.SearchQuery{Text: "webhook", Kind: internal.SearchKindTask}, false); err != nil {
+		t.Fatalf("RunSearch: %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "[task] ") || !strings.Contains(got, "Harden webhook retries") || !strings.Contains(got, "role: backend") {
+		t.Fatalf("output missing header:\n%s", got)
+	}
+	if !strings.Contains(got, ".agent-team/task/") || !strings.Contains(got, "task.yaml:") {
+		t.Fatalf("output missing path:line:\n%s", got)
+	}
+
+	out.Reset()
+	if err := app.RunSearch(&out, internal.SearchQuery{Text: "nothing-matches-this"}, false); err != nil {
+		t.Fatalf("RunSearch: %v", err)
+	}
+	if !strings.Contains(out.String(), "No matches") {
+		t.Fatalf("output = %q", out.String())
+	}
+	if err := app.RunSearch(&out, internal.SearchQuery{Text: "webhook", Kind: "worker"}, true); err == nil {
+		t.Fatal("expected error for invalid --kind")
+	}
+}
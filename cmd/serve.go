@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd() *cobra.Command {
+	var addr string
+	cmd := &cobra.Command{
+		Use:   "serve [--addr <host:port>]",
+		Short: "Serve the local controller HTTP API",
+		Long: "Serves task, worker, planning and event-stream endpoints under /api for local frontends.\n" +
+			"The listen address and bearer token are read from .agent-team/serve.yaml, which is created\n" +
+			"with a random token on first run. Binds to " + internal.DefaultServeAddr + " by default.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunServe(cmd.Context(), addr)
+		},
+	}
+	cmd.Flags().StringVar(&addr, "addr", "", "HTTP listen address (overrides serve.yaml)")
+	return cmd
+}
+
+func (a *App) RunServe(ctx context.Context, addr string) error {
+	root := a.Git.Root()
+	cfg, err := internal.EnsureServeConfig(root)
+	if err != nil {
+		return err
+	}
+	if addr != "" {
+		cfg.Addr = addr
+	}
+	if strings.TrimSpace(cfg.Token) == "" {
+		return fmt.Errorf("refusing to serve without a token: set token in %s, or delete the file to generate one", internal.ServeConfigPath(root))
+	}
+
+	handler := internal.NewControllerAPIHandler(internal.ControllerAPIOptions{
+		Root:           root,
+		WtBase:         a.WtBase,
+		Token:          cfg.Token,
+		Session:        a.Session,
+		Actions:        a.controllerActions(),
+		AllowedOrigins: cfg.AllowedOrigins,
+	})
+	server := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Controller API listening on %s (base: %s)\n", cfg.Addr, "/api")
+	fmt.Printf("  → Token: %s\n", internal.ServeConfigPath(root))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// controllerActions binds the API's side-effecting operations to the same
+// App methods the CLI commands use.
+func (a *App) controllerActions() internal.ControllerActions {
+	return internal.ControllerActions{
+		AssignTask: func(taskID, workerID, provider, model, reason string) error {
+			return a.RunTaskAssign(taskID, workerID, provider, model, reason, false)
+		},
+		ArchiveTask: func(taskID, mergedSHA, reason string, strict bool) error {
+			return a.RunTaskArchive(taskID, mergedSHA, reason, strict)
+		},
+		Reply: a.RunReply,
+	}
+}
@@ -6,10 +6,15 @@ import "github.com/spf13/cobra"
 func newSkillCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "skill",
-		Short: "Manage project skill cache",
+		Short: "Install, lock and maintain project skills",
 	}
+	cmd.AddCommand(newSkillInstallCmd())
 	cmd.AddCommand(newSkillCheckCmd())
 	cmd.AddCommand(newSkillUpdateCmd())
 	cmd.AddCommand(newSkillCleanCmd())
+	cmd.AddCommand(newSkillLockCmd())
+	cmd.AddCommand(newSkillVerifyCmd())
+	cmd.AddCommand(newSkillWhyCmd())
+	cmd.AddCommand(newSkillReportCmd())
 	return cmd
 }
@@ -2,25 +2,79 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
+	"io"
 
+	"github.com/JsonLee12138/agent-team/internal"
 	"github.com/spf13/cobra"
 )
 
 func newSkillCheckCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "check",
+	var jsonOut bool
+	var useNpx bool
+	cmd := &cobra.Command{
+		Use:   "check [<skill>...]",
 		Short: "Check installed skills for available updates",
+		Long:  "Compare installed skills with their source at the ref recorded in skills-lock.json without downloading them. Skills without a recorded source (project skills) are listed but not checked. --npx runs `npx skills check` instead.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			root := GetApp(cmd).Git.Root()
-			fmt.Println("Checking skills in project root:", root)
-			c := exec.Command("npx", "skills", "check")
-			c.Dir = root
-			c.Stdout = os.Stdout
-			c.Stderr = os.Stderr
-			return c.Run()
+			app := GetApp(cmd)
+			if useNpx {
+				return runNpxSkills(app.Git.Root(), "check")
+			}
+			return app.RunSkillCheck(cmd.OutOrStdout(), args, jsonOut)
 		},
 	}
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&useNpx, "npx", false, "Use `npx skills` instead of the built-in checker")
+	return cmd
+}
+
+func (a *App) RunSkillCheck(out io.Writer, names []string, jsonOut bool) error {
+	root := a.Git.Root()
+	lock, err := internal.ReadSkillsLock(root)
+	if err != nil {
+		return err
+	}
+	results := internal.CheckSkills(context.Background(), root, internal.NewRoleRepoProviderSet(root, nil), lock, names)
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	if jsonOut {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+	} else {
+		if len(results) == 0 {
+			fmt.Fprintln(out, "No skills in skills-lock.json. Install one with 'agent-team skill install'.")
+		}
+		for _, r := range results {
+			switch r.Status {
+			case internal.SkillCheckUpToDate:
+				fmt.Fprintf(out, "✓ %s is up to date\n", r.Name)
+			case internal.SkillCheckUpdateAvailable:
+				fmt.Fprintf(out, "↑ %s: update available from %s\n", r.Name, r.Source)
+			case internal.SkillCheckLocalChanges:
+				fmt.Fprintf(out, "! %s: edited since install (skill update skips it without --force)\n", r.Name)
+			case internal.SkillCheckNotInstalled:
+				fmt.Fprintf(out, "- %s: not installed, run 'agent-team skill update %s'\n", r.Name, r.Name)
+			case internal.SkillCheckNoSource:
+				fmt.Fprintf(out, "  %s: no source recorded\n", r.Name)
+			default:
+				fmt.Fprintf(out, "✗ %s: %s\n", r.Name, r.Error)
+			}
+		}
+		fmt.Fprintf(out, "Summary: up-to-date=%d updates=%d local-changes=%d not-installed=%d no-source=%d errors=%d\n",
+			counts[internal.SkillCheckUpToDate], counts[internal.SkillCheckUpdateAvailable], counts[internal.SkillCheckLocalChanges],
+			counts[internal.SkillCheckNotInstalled], counts[internal.SkillCheckNoSource], counts[internal.SkillCheckError])
+	}
+	if counts[internal.SkillCheckError] > 0 {
+		return fmt.Errorf("%d skill check(s) failed", counts[internal.SkillCheckError])
+	}
+	return nil
 }
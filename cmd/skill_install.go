@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newSkillInstallCmd() *cobra.Command {
+	var ref string
+	var useNpx bool
+	cmd := &cobra.Command{
+		Use:   "install <source>[@<skill>]...",
+		Short: "Install skills into the project skill cache",
+		Long:  "Fetch a skill package from GitHub (owner/repo[@skill]), any git remote, a GitLab/Gitea project or a local path into .agent-team/.cache/skills/<skill>, validate its SKILL.md and record the source, commit and content hash in skills-lock.json. --npx uses `npx skills add` instead.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app := GetApp(cmd)
+			if useNpx {
+				return runNpxSkills(app.Git.Root(), append([]string{"add"}, args...)...)
+			}
+			return app.RunSkillInstall(cmd.OutOrStdout(), args, ref)
+		},
+	}
+	cmd.Flags().StringVar(&ref, "ref", "", "Tag, branch or commit to install from")
+	cmd.Flags().BoolVar(&useNpx, "npx", false, "Use `npx skills` instead of the built-in installer")
+	return cmd
+}
+
+func (a *App) RunSkillInstall(out io.Writer, specs []string, ref string) error {
+	root := a.Git.Root()
+	providers := internal.NewRoleRepoProviderSet(root, nil)
+	ctx := context.Background()
+
+	var installed, failed int
+	for _, raw := range specs {
+		spec, err := internal.ParseSkillSpec(root, raw, ref)
+		if err == nil {
+			var result *internal.SkillInstallResult
+			if result, err = internal.InstallSkill(ctx, root, providers, spec, time.Now); err == nil {
+				installed++
+				fmt.Fprintf(out, "+ installed %s from %s\n", result.Name, describeSkillInstall(result))
+				continue
+			}
+		}
+		failed++
+		fmt.Fprintf(out, "- failed %s: %v\n", raw, err)
+	}
+	fmt.Fprintf(out, "Summary: installed=%d failed=%d\n", installed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d skill(s) failed to install", failed)
+	}
+	return nil
+}
+
+// describeSkillInstall renders where an installed skill came from, e.g.
+// "antfu/skills skills/vite @ 1a2b3c4d5e6f".
+func describeSkillInstall(result *internal.SkillInstallResult) string {
+	desc := result.Source
+	if result.SkillPath != "" {
+		desc += " " + result.SkillPath
+	}
+	if result.Ref != "" {
+		desc += " (" + result.Ref + ")"
+	}
+	if len(result.Commit) >= 12 {
+		desc += " @ " + result.Commit[:12]
+	}
+	return desc
+}
+
+// runNpxSkills runs `npx skills <args>` in the project root, the behaviour
+// before the built-in installer.
+func runNpxSkills(root string, args ...string) error {
+	if _, err := exec.LookPath("npx"); err != nil {
+		return fmt.Errorf("--npx requires npx on PATH: %w", err)
+	}
+	c := exec.Command("npx", append([]string{"skills"}, args...)...)
+	c.Dir = root
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
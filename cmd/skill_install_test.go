@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunSkillInstallCheckAndUpdate(t *testing.T) {
+	app, root := initTestApp(t)
+	t.Setenv("HOME", t.TempDir())
+	source := t.TempDir()
+	skillMD := filepath.Join(source, "vite", "SKILL.md")
+	if err := os.MkdirAll(filepath.Dir(skillMD), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(skillMD, []byte("---\nname: vite\ndescription: Vite support\n---\nv1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := app.RunSkillInstall(&out, []string{source + "@vite", source + "@missing"}, ""); err == nil {
+		t.Fatalf("expected failure for missing skill\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "+ installed vite from "+source+" vite") || !strings.Contains(out.String(), "Summary: installed=1 failed=1") {
+		t.Fatalf("unexpected install output:\n%s", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(root, ".agent-team", ".cache", "skills", "vite", "SKILL.md")); err != nil {
+		t.Fatalf("skill not in project cache: %v", err)
+	}
+
+	out.Reset()
+	if err := app.RunSkillCheck(&out, nil, false); err != nil {
+		t.Fatalf("RunSkillCheck: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "✓ vite is up to date") {
+		t.Fatalf("unexpected check output:\n%s", out.String())
+	}
+
+	if err := os.WriteFile(skillMD, []byte("---\nname: vite\ndescription: Vite support\n---\nv2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := app.RunSkillCheck(&out, nil, false); err != nil || !strings.Contains(out.String(), "↑ vite: update available") {
+		t.Fatalf("expected update available, got %v\n%s", err, out.String())
+	}
+	out.Reset()
+	if err := app.RunSkillUpdate(&out, nil, false); err != nil {
+		t.Fatalf("RunSkillUpdate: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "+ updated vite") || !strings.Contains(out.String(), "Summary: updated=1 up-to-date=0 skipped=0 failed=0") {
+		t.Fatalf("unexpected update output:\n%s", out.String())
+	}
+}
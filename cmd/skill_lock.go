@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newSkillLockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lock [<skill>...]",
+		Short: "Record where each skill resolves in skills-lock.json",
+		Long:  "Resolve skills the way worker open does and record, per skill, the source, the resolved path, the search layer and a content hash in skills-lock.json. Without arguments every skill referenced by a project role is locked. Fields written by `npx skills` are kept.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunSkillLock(cmd.OutOrStdout(), args)
+		},
+	}
+}
+
+func (a *App) RunSkillLock(out io.Writer, skills []string) error {
+	root := a.Git.Root()
+	if len(skills) == 0 {
+		var err error
+		skills, err = internal.ProjectRoleSkills(root)
+		if err != nil {
+			return err
+		}
+		if len(skills) == 0 {
+			fmt.Fprintln(out, "No skills referenced by project roles.")
+			return nil
+		}
+	}
+	lock, err := internal.ReadSkillsLock(root)
+	if err != nil {
+		return err
+	}
+
+	var locked, missing int
+	for _, name := range skills {
+		res := internal.ResolveSkill(root, name)
+		entry, err := internal.SkillsLockEntryFor(root, res, lock.Skills[res.ShortName])
+		if err != nil {
+			missing++
+			fmt.Fprintf(out, "- missing %s: %v\n", name, err)
+			continue
+		}
+		lock.Skills[res.ShortName] = entry
+		locked++
+		fmt.Fprintf(out, "+ locked %-24s %-15s %s %s\n", res.ShortName, entry.Layer, entry.Path, entry.ContentHash[:12])
+	}
+	if locked > 0 {
+		if err := internal.WriteSkillsLock(root, lock); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(out, "Summary: locked=%d missing=%d\n", locked, missing)
+	if missing > 0 {
+		return fmt.Errorf("%d skill(s) not found locally", missing)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JsonLee12138/agent-team/internal"
+)
+
+func TestRunSkillLockVerifyAndWhy(t *testing.T) {
+	app, root := initTestApp(t)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CLAUDE_PLUGIN_ROOT", "")
+	if _, err := internal.CreateOrUpdateRole(root, internal.RoleConfig{
+		RoleName:    "qa",
+		Description: "QA role",
+		SystemGoal:  "Verify changes",
+		InScope:     []string{"Tests"},
+		OutOfScope:  []string{"Deploys"},
+		Skills:      []internal.RoleSkillSpec{{Name: "vitest", Description: "Unit tests"}},
+	}, "yes", nil, ".agent-team/teams"); err != nil {
+		t.Fatal(err)
+	}
+	skillDir := filepath.Join(root, "skills", "vitest")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := app.RunSkillLock(&out, nil); err != nil {
+		t.Fatalf("RunSkillLock: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "+ locked vitest") || !strings.Contains(out.String(), "Summary: locked=1 missing=0") {
+		t.Fatalf("unexpected lock output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := app.RunSkillVerify(&out, false); err != nil {
+		t.Fatalf("RunSkillVerify: %v\n%s", err, out.String())
+	}
+
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("v2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := app.RunSkillVerify(&out, false); err == nil || !strings.Contains(out.String(), "✗ vitest: content changed") {
+		t.Fatalf("expected drift, got %v\n%s", err, out.String())
+	}
+
+	out.Reset()
+	if err := app.RunSkillWhy(&out, "vitest", false); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Resolved: " + skillDir + " (project-skills)", "✓ project-skills", "← used", "— drift"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("why output missing %q:\n%s", want, out.String())
+		}
+	}
+}
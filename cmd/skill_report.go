@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newSkillReportCmd() *cobra.Command {
+	var jsonOut bool
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Report skill usage and coverage across roles and workers",
+		Long:  "Show which skills each role declares and where they resolve (local, cached or missing), which skills are linked into each worker's worktree, broken skill symlinks, cached skills no role or worker uses, and skills present in more than one search location.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunSkillReport(cmd.OutOrStdout(), jsonOut)
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	return cmd
+}
+
+func (a *App) RunSkillReport(out io.Writer, jsonOut bool) error {
+	report := internal.BuildSkillReport(a.Git.Root(), a.WtBase)
+	if jsonOut {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	declared := 0
+	fmt.Fprintln(out, "Roles:")
+	if len(report.Roles) == 0 {
+		fmt.Fprintln(out, "  (none)")
+	} else {
+		fmt.Fprintf(out, "  %-24s %-28s %-8s %s\n", "Role", "Skill", "Status", "Location")
+		for _, r := range report.Roles {
+			if r.Error != "" {
+				fmt.Fprintf(out, "  %-24s %-28s %-8s %s\n", r.Role, "-", "error", r.Error)
+				continue
+			}
+			if len(r.Skills) == 0 {
+				fmt.Fprintf(out, "  %-24s %-28s %-8s %s\n", r.Role, "-", "-", "-")
+			}
+			for _, s := range r.Skills {
+				declared++
+				location := "-"
+				if s.Path != "" {
+					location = fmt.Sprintf("%s (%s)", s.Path, s.Layer)
+				}
+				fmt.Fprintf(out, "  %-24s %-28s %-8s %s\n", r.Role, s.Name, s.Status, location)
+			}
+		}
+	}
+
+	fmt.Fprintln(out, "\nWorkers:")
+	if len(report.Workers) == 0 {
+		fmt.Fprintln(out, "  (none)")
+	} else {
+		fmt.Fprintf(out, "  %-24s %-28s %-9s %s\n", "Worker", "Skill", "Provider", "Link")
+		for _, w := range report.Workers {
+			if len(w.Skills) == 0 {
+				fmt.Fprintf(out, "  %-24s %-28s %-9s %s\n", w.Worker, "-", "-", "no skills linked")
+			}
+			for _, s := range w.Skills {
+				link := "copy"
+				if s.Kind == "symlink" {
+					link = "→ " + s.Target
+					if s.Broken {
+						link = "✗ broken → " + s.Target
+					}
+				}
+				fmt.Fprintf(out, "  %-24s %-28s %-9s %s\n", w.Worker, s.Name, s.Provider, link)
+			}
+		}
+	}
+
+	if len(report.Missing) > 0 {
+		fmt.Fprintln(out, "\nMissing skills:")
+		for _, m := range report.Missing {
+			fmt.Fprintf(out, "  %s (declared by %s)\n", m.Name, strings.Join(m.Roles, ", "))
+		}
+	}
+	if len(report.BrokenLinks) > 0 {
+		fmt.Fprintln(out, "\nBroken links:")
+		for _, b := range report.BrokenLinks {
+			fmt.Fprintf(out, "  %s: %s → %s\n", b.Worker, b.Link, b.Target)
+		}
+	}
+	if len(report.Unused) > 0 {
+		fmt.Fprintln(out, "\nUnused cached skills:")
+		for _, name := range report.Unused {
+			fmt.Fprintf(out, "  %s\n", name)
+		}
+	}
+	if len(report.Duplicates) > 0 {
+		fmt.Fprintln(out, "\nDuplicated skills (first location wins):")
+		for _, d := range report.Duplicates {
+			var locations []string
+			for _, l := range d.Locations {
+				locations = append(locations, fmt.Sprintf("%s (%s)", l.Path, l.Layer))
+			}
+			fmt.Fprintf(out, "  %s: %s\n", d.Name, strings.Join(locations, ", "))
+		}
+	}
+
+	fmt.Fprintf(out, "\nSummary: roles=%d workers=%d declared=%d missing=%d broken=%d unused=%d duplicates=%d\n",
+		len(report.Roles), len(report.Workers), declared, len(report.Missing), len(report.BrokenLinks), len(report.Unused), len(report.Duplicates))
+	return nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JsonLee12138/agent-team/internal"
+)
+
+func TestRunSkillReport(t *testing.T) {
+	app, root := initTestApp(t)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CLAUDE_PLUGIN_ROOT", "")
+	if _, err := internal.CreateOrUpdateRole(root, internal.RoleConfig{
+		RoleName:    "qa",
+		Description: "QA role",
+		SystemGoal:  "Verify changes",
+		InScope:     []string{"Tests"},
+		OutOfScope:  []string{"Deploys"},
+		Skills: []internal.RoleSkillSpec{
+			{Name: "vitest", Description: "Unit tests"},
+			{Name: "playwright", Description: "E2E tests"},
+		},
+	}, "yes", nil, ".agent-team/teams"); err != nil {
+		t.Fatal(err)
+	}
+	skillDir := filepath.Join(root, "skills", "vitest")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wtPath := internal.WtPath(root, app.WtBase, "qa-001")
+	cfg := &internal.WorkerConfig{WorkerID: "qa-001", Role: "qa"}
+	if err := cfg.Save(internal.WorkerYAMLPath(wtPath)); err != nil {
+		t.Fatal(err)
+	}
+	linkDir := filepath.Join(wtPath, ".claude", "skills")
+	if err := os.MkdirAll(linkDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(skillDir, filepath.Join(linkDir, "vitest")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "skills", "removed"), filepath.Join(linkDir, "removed")); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := app.RunSkillReport(&out, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"vitest", "local", "skills/vitest (project-skills)", "playwright (declared by qa)", "✗ broken →", "Summary: roles=1 workers=1 declared=2 missing=1 broken=1"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("report missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := app.RunSkillReport(&out, true); err != nil {
+		t.Fatal(err)
+	}
+	var report internal.SkillReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(report.Workers) != 1 || len(report.Workers[0].Skills) != 2 || len(report.BrokenLinks) != 1 {
+		t.Fatalf("unexpected JSON report: %+v", report)
+	}
+}
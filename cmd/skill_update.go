@@ -2,25 +2,62 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
+	"io"
+	"time"
 
+	"github.com/JsonLee12138/agent-team/internal"
 	"github.com/spf13/cobra"
 )
 
 func newSkillUpdateCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "update",
-		Short: "Update all cached skills to latest versions",
+	var force bool
+	var useNpx bool
+	cmd := &cobra.Command{
+		Use:   "update [<skill>...]",
+		Short: "Update cached skills from their recorded sources",
+		Long:  "Reinstall skills from the source and ref recorded in skills-lock.json (every skill with a source when none are named). Skills edited since install are skipped unless --force. --npx runs `npx skills update` instead.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			root := GetApp(cmd).Git.Root()
-			fmt.Println("Updating skills in project root:", root)
-			c := exec.Command("npx", "skills", "update")
-			c.Dir = root
-			c.Stdout = os.Stdout
-			c.Stderr = os.Stderr
-			return c.Run()
+			app := GetApp(cmd)
+			if useNpx {
+				return runNpxSkills(app.Git.Root(), "update")
+			}
+			return app.RunSkillUpdate(cmd.OutOrStdout(), args, force)
 		},
 	}
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite local changes to installed skills")
+	cmd.Flags().BoolVar(&useNpx, "npx", false, "Use `npx skills` instead of the built-in installer")
+	return cmd
+}
+
+func (a *App) RunSkillUpdate(out io.Writer, names []string, force bool) error {
+	root := a.Git.Root()
+	results, err := internal.UpdateSkills(context.Background(), root, internal.NewRoleRepoProviderSet(root, nil), names, force, time.Now)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(out, "No skills with a recorded source in skills-lock.json.")
+		return nil
+	}
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+		switch r.Status {
+		case "updated":
+			fmt.Fprintf(out, "+ updated %s from %s\n", r.Name, describeSkillInstall(r.Install))
+		case "up-to-date":
+			fmt.Fprintf(out, "✓ %s is up to date\n", r.Name)
+		case "skipped":
+			fmt.Fprintf(out, "! skipped %s: %s\n", r.Name, r.Reason)
+		default:
+			fmt.Fprintf(out, "- failed %s: %s\n", r.Name, r.Reason)
+		}
+	}
+	fmt.Fprintf(out, "Summary: updated=%d up-to-date=%d skipped=%d failed=%d\n", counts["updated"], counts["up-to-date"], counts["skipped"], counts["failed"])
+	if counts["failed"] > 0 {
+		return fmt.Errorf("%d skill(s) failed to update", counts["failed"])
+	}
+	return nil
 }
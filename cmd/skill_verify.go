@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newSkillVerifyCmd() *cobra.Command {
+	var jsonOut bool
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check that skills still match skills-lock.json",
+		Long:  "Resolve every skill in skills-lock.json and compare its content hash and path with the lock. Exits non-zero when a skill is missing or its content drifted; a skill that moved to another search path with the same content only warns.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunSkillVerify(cmd.OutOrStdout(), jsonOut)
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	return cmd
+}
+
+func (a *App) RunSkillVerify(out io.Writer, jsonOut bool) error {
+	root := a.Git.Root()
+	lock, err := internal.ReadSkillsLock(root)
+	if err != nil {
+		return err
+	}
+	results := internal.VerifySkillsLock(root, lock)
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	if jsonOut {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+	} else {
+		if len(results) == 0 {
+			fmt.Fprintln(out, "No skills in skills-lock.json. Run 'agent-team skill lock' first.")
+		}
+		for _, r := range results {
+			switch r.Status {
+			case internal.SkillVerifyOK:
+				fmt.Fprintf(out, "✓ %s (%s)\n", r.Name, r.ResolvedPath)
+			case internal.SkillVerifyDrift:
+				fmt.Fprintf(out, "✗ %s: content changed (locked %s at %s, now %s at %s)\n", r.Name, r.LockedHash[:12], r.LockedPath, r.ResolvedHash[:12], r.ResolvedPath)
+			case internal.SkillVerifyMoved:
+				fmt.Fprintf(out, "! %s: resolved from %s instead of %s (same content)\n", r.Name, r.ResolvedPath, r.LockedPath)
+			case internal.SkillVerifyMissing:
+				fmt.Fprintf(out, "✗ %s: not found locally (locked at %s)\n", r.Name, r.LockedPath)
+			case internal.SkillVerifyUnlocked:
+				fmt.Fprintf(out, "  %s: no content hash recorded, run 'agent-team skill lock %s'\n", r.Name, r.Name)
+			}
+		}
+		fmt.Fprintf(out, "Summary: ok=%d drift=%d moved=%d missing=%d unlocked=%d\n",
+			counts[internal.SkillVerifyOK], counts[internal.SkillVerifyDrift], counts[internal.SkillVerifyMoved],
+			counts[internal.SkillVerifyMissing], counts[internal.SkillVerifyUnlocked])
+	}
+
+	if failed := counts[internal.SkillVerifyDrift] + counts[internal.SkillVerifyMissing]; failed > 0 {
+		return fmt.Errorf("%d skill(s) do not match skills-lock.json", failed)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newSkillWhyCmd() *cobra.Command {
+	var jsonOut bool
+	cmd := &cobra.Command{
+		Use:   "why <skill>",
+		Short: "Explain which search path a skill resolves from",
+		Long:  "List every location checked for a skill, in the order worker open checks them, mark the one that wins, and compare it with skills-lock.json.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunSkillWhy(cmd.OutOrStdout(), args[0], jsonOut)
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output as JSON")
+	return cmd
+}
+
+type skillWhyOutput struct {
+	internal.SkillResolution
+	Lock   *internal.SkillsLockEntry   `json:"lock,omitempty"`
+	Verify *internal.SkillVerifyResult `json:"verify,omitempty"`
+}
+
+func (a *App) RunSkillWhy(out io.Writer, skillName string, jsonOut bool) error {
+	root := a.Git.Root()
+	lock, err := internal.ReadSkillsLock(root)
+	if err != nil {
+		return err
+	}
+	output := skillWhyOutput{SkillResolution: internal.ResolveSkill(root, skillName)}
+	if entry, ok := lock.Skills[output.ShortName]; ok {
+		output.Lock = &entry
+		if entry.ContentHash != "" {
+			verify := internal.VerifySkill(root, output.ShortName, entry)
+			output.Verify = &verify
+		}
+	}
+
+	if jsonOut {
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(out, "Skill: %s\n", skillName)
+	if output.Path == "" {
+		fmt.Fprintln(out, "Resolved: not found locally")
+	} else {
+		fmt.Fprintf(out, "Resolved: %s (%s)\n", output.Path, output.Layer)
+	}
+	fmt.Fprintln(out, "Search order:")
+	used := false
+	for _, c := range output.Candidates {
+		mark := "✗"
+		if c.Exists {
+			mark = "✓"
+		}
+		note := ""
+		if c.Exists && !used {
+			note = "  ← used"
+			used = true
+		} else if c.Exists {
+			note = "  (shadowed)"
+		}
+		fmt.Fprintf(out, "  %s %-15s %s%s\n", mark, c.Layer, c.Path, note)
+	}
+	switch {
+	case output.Lock == nil:
+		fmt.Fprintln(out, "Lock: not in skills-lock.json")
+	case output.Verify == nil:
+		fmt.Fprintf(out, "Lock: source %s, no content hash recorded\n", output.Lock.Source)
+	default:
+		fmt.Fprintf(out, "Lock: %s (%s) %s — %s\n", output.Lock.Path, output.Lock.Layer, output.Lock.ContentHash[:12], output.Verify.Status)
+	}
+	return nil
+}
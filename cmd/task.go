@@ -12,8 +12,12 @@ func newTaskCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(newTaskCreateCmd())
+	cmd.AddCommand(newTaskImportCmd())
+	cmd.AddCommand(newTaskExportCmd())
 	cmd.AddCommand(newTaskListCmd())
 	cmd.AddCommand(newTaskShowCmd())
+	cmd.AddCommand(newTaskLogCmd())
+	cmd.AddCommand(newTaskCommentCmd())
 	cmd.AddCommand(newTaskAssignCmd())
 	cmd.AddCommand(newTaskDoneCmd())
 	cmd.AddCommand(newTaskArchiveCmd())
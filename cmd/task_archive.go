@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/JsonLee12138/agent-team/internal"
@@ -10,23 +11,25 @@ import (
 
 func newTaskArchiveCmd() *cobra.Command {
 	var mergedSHA string
+	var reason string
 	var strict bool
 	cmd := &cobra.Command{
 		Use:   "archive <task-id> --merged-sha <sha>",
 		Short: "Archive a verifying task after merge",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return GetApp(cmd).RunTaskArchive(args[0], mergedSHA, strict)
+			return GetApp(cmd).RunTaskArchive(args[0], mergedSHA, reason, strict)
 		},
 	}
 	cmd.Flags().StringVar(&mergedSHA, "merged-sha", "", "Merged commit SHA")
+	cmd.Flags().StringVar(&reason, "reason", "", "Note recorded with the transition in history.jsonl")
 	cmd.Flags().BoolVar(&strict, "strict", false, "Require verification result 'pass' before archive")
 	_ = cmd.MarkFlagRequired("merged-sha")
 	return cmd
 }
 
-func (a *App) RunTaskArchive(taskID, mergedSHA string, strict bool) error {
-	record, err := internal.ArchiveTask(a.Git.Root(), taskID, mergedSHA, strict, time.Now().UTC())
+func (a *App) RunTaskArchive(taskID, mergedSHA, reason string, strict bool) error {
+	record, err := internal.ArchiveTask(a.Git.Root(), taskID, mergedSHA, reason, strict, time.Now().UTC())
 	if err != nil {
 		return err
 	}
@@ -36,5 +39,12 @@ func (a *App) RunTaskArchive(taskID, mergedSHA string, strict bool) error {
 		}
 	}
 	fmt.Printf("✓ Archived task '%s'\n", record.TaskID)
+	promoted, err := internal.AutoPromotePlanning(a.Git.Root(), time.Now().UTC())
+	for _, planning := range promoted {
+		fmt.Printf("  → %s '%s' promoted to %s\n", planning.Kind, planning.ID, planning.Status)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: planning auto-promotion failed: %v\n", err)
+	}
 	return nil
 }
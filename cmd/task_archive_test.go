@@ -26,16 +26,16 @@ func TestRunTaskArchiveMovesTaskAndCleansWorker(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTaskPackage: %v", err)
 	}
-	if _, err := internal.BindTaskToWorker(dir, record.TaskID, workerID, time.Now().UTC()); err != nil {
+	if _, err := internal.BindTaskToWorker(dir, record.TaskID, workerID, "", time.Now().UTC()); err != nil {
 		t.Fatalf("BindTaskToWorker: %v", err)
 	}
-	if _, err := internal.MarkTaskDone(dir, record.TaskID, time.Now().UTC()); err != nil {
+	if _, err := internal.MarkTaskDone(dir, record.TaskID, "", time.Now().UTC()); err != nil {
 		t.Fatalf("MarkTaskDone: %v", err)
 	}
 	if err := os.WriteFile(internal.TaskVerificationPath(dir, record.TaskID), []byte("# Verification\n\n## Result\n- pass\n"), 0644); err != nil {
 		t.Fatalf("WriteFile verification: %v", err)
 	}
-	if err := app.RunTaskArchive(record.TaskID, "deadbeef", false); err != nil {
+	if err := app.RunTaskArchive(record.TaskID, "deadbeef", "", false); err != nil {
 		t.Fatalf("RunTaskArchive: %v", err)
 	}
 	if _, err := os.Stat(internal.TaskArchiveDir(dir, record.TaskID)); err != nil {
@@ -55,13 +55,13 @@ func TestRunTaskArchiveBlocksPendingVerification(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTaskPackage: %v", err)
 	}
-	if _, err := internal.BindTaskToWorker(dir, record.TaskID, "backend-001", time.Now().UTC()); err != nil {
+	if _, err := internal.BindTaskToWorker(dir, record.TaskID, "backend-001", "", time.Now().UTC()); err != nil {
 		t.Fatalf("BindTaskToWorker: %v", err)
 	}
-	if _, err := internal.MarkTaskDone(dir, record.TaskID, time.Now().UTC()); err != nil {
+	if _, err := internal.MarkTaskDone(dir, record.TaskID, "", time.Now().UTC()); err != nil {
 		t.Fatalf("MarkTaskDone: %v", err)
 	}
-	if err := app.RunTaskArchive(record.TaskID, "deadbeef", false); err == nil {
+	if err := app.RunTaskArchive(record.TaskID, "deadbeef", "", false); err == nil {
 		t.Fatal("expected archive to fail for pending verification")
 	}
 }
@@ -13,23 +13,25 @@ func newTaskAssignCmd() *cobra.Command {
 	var provider string
 	var model string
 	var workerID string
+	var reason string
 	var newWindow bool
 	cmd := &cobra.Command{
 		Use:   "assign <task-id>",
 		Short: "Assign a task and open its worker session",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return GetApp(cmd).RunTaskAssign(args[0], workerID, provider, model, newWindow)
+			return GetApp(cmd).RunTaskAssign(args[0], workerID, provider, model, reason, newWindow)
 		},
 	}
 	cmd.Flags().StringVar(&workerID, "worker", "", "Existing worker ID for same-role reassignment")
 	cmd.Flags().StringVarP(&provider, "provider", "p", "", workerProviderFlagHelp)
 	cmd.Flags().StringVarP(&model, "model", "m", "", "AI model identifier")
+	cmd.Flags().StringVar(&reason, "reason", "", "Why the task is (re)assigned; recorded in history.jsonl")
 	cmd.Flags().BoolVarP(&newWindow, "new-window", "w", false, "Open in a new window instead of a tab")
 	return cmd
 }
 
-func (a *App) RunTaskAssign(taskID, requestedWorkerID, provider, model string, newWindow bool) error {
+func (a *App) RunTaskAssign(taskID, requestedWorkerID, provider, model, reason string, newWindow bool) error {
 	root := a.Git.Root()
 	record, location, err := internal.LoadTaskRecord(root, taskID)
 	if err != nil {
@@ -103,7 +105,7 @@ func (a *App) RunTaskAssign(taskID, requestedWorkerID, provider, model string, n
 		return fmt.Errorf("save worker config: %w", err)
 	}
 
-	record, err = internal.BindTaskToWorker(root, taskID, workerID, time.Now().UTC())
+	record, err = internal.BindTaskToWorker(root, taskID, workerID, reason, time.Now().UTC())
 	if err != nil {
 		return err
 	}
@@ -33,7 +33,7 @@ func TestRunTaskAssignCreatesWorkerAndSendsLightReminder(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTaskPackage: %v", err)
 	}
-	if err := app.RunTaskAssign(record.TaskID, "", "", "", false); err != nil {
+	if err := app.RunTaskAssign(record.TaskID, "", "", "", "", false); err != nil {
 		t.Fatalf("RunTaskAssign: %v", err)
 	}
 	workers := internal.ListWorkers(dir, app.WtBase)
@@ -73,7 +73,7 @@ func TestRunTaskAssignRebindRequiresSameRole(t *testing.T) {
 	if err := cfg.Save(internal.WorkerConfigPath(dir, "frontend-001")); err != nil {
 		t.Fatalf("save worker config: %v", err)
 	}
-	err = app.RunTaskAssign(record.TaskID, "frontend-001", "", "", false)
+	err = app.RunTaskAssign(record.TaskID, "frontend-001", "", "", "", false)
 	if err == nil || !strings.Contains(err.Error(), "role mismatch") {
 		t.Fatalf("err = %v, want role mismatch", err)
 	}
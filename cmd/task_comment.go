@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newTaskCommentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   `comment <task-id> "<text>"`,
+		Short: "Add a comment to a task's history",
+		Long: "Appends a comment to the task package's history.jsonl. The actor is taken from AGENT_TEAM_ACTOR,\n" +
+			"falling back to the OS user.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunTaskComment(args[0], args[1])
+		},
+	}
+	return cmd
+}
+
+func (a *App) RunTaskComment(taskID, text string) error {
+	if err := internal.AddTaskComment(a.Git.Root(), taskID, text, time.Now().UTC()); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Commented on task '%s'\n", taskID)
+	return nil
+}
@@ -12,25 +12,38 @@ import (
 func newTaskCreateCmd() *cobra.Command {
 	var role string
 	var design string
+	var phase string
+	var template string
 	cmd := &cobra.Command{
 		Use:   `create --role <role> "<title>"`,
 		Short: "Create a task package",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return GetApp(cmd).RunTaskCreate(args[0], role, design)
+			return GetApp(cmd).RunTaskCreate(args[0], role, design, phase, template)
 		},
 	}
 	cmd.Flags().StringVar(&role, "role", "", "Role bound to this task")
 	cmd.Flags().StringVar(&design, "design", "", "Path to design/brainstorming file")
+	cmd.Flags().StringVar(&phase, "phase", "", "Phase to link the new task to")
+	cmd.Flags().StringVar(&template, "template", "", "Task template name (default: the role's template, if any)")
 	_ = cmd.MarkFlagRequired("role")
 	return cmd
 }
 
-func (a *App) RunTaskCreate(title, role, designPath string) error {
+func (a *App) RunTaskCreate(title, role, designPath, phase, template string) error {
 	root := a.Git.Root()
 	if _, err := internal.ResolveRole(root, role); err != nil {
 		return err
 	}
+	if phase != "" {
+		record, err := internal.LoadPlanningRecord(root, phase)
+		if err != nil {
+			return err
+		}
+		if record.Kind != internal.PlanningKindPhase {
+			return fmt.Errorf("'%s' is a %s, not a phase", phase, record.Kind)
+		}
+	}
 
 	design := ""
 	if designPath != "" {
@@ -41,7 +54,14 @@ func (a *App) RunTaskCreate(title, role, designPath string) error {
 		design = string(data)
 	}
 
-	record, err := internal.CreateTaskPackage(root, title, role, design, time.Now().UTC())
+	record, err := internal.CreateTaskPackageWithOptions(root, internal.TaskCreateOptions{
+		Title:      title,
+		Role:       role,
+		Design:     design,
+		DesignPath: designPath,
+		Phase:      phase,
+		Template:   template,
+	}, time.Now().UTC())
 	if err != nil {
 		return err
 	}
@@ -50,5 +70,11 @@ func (a *App) RunTaskCreate(title, role, designPath string) error {
 	fmt.Printf("  → Role: %s\n", record.Role)
 	fmt.Printf("  → Path: %s\n", record.TaskPath)
 	fmt.Printf("  → Status: %s\n", record.Status)
+	if phase != "" {
+		if _, err := internal.LinkPlanning(root, phase, record.TaskID, time.Now().UTC()); err != nil {
+			return fmt.Errorf("link task to phase: %w", err)
+		}
+		fmt.Printf("  → Phase: %s\n", phase)
+	}
 	return nil
 }
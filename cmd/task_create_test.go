@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/JsonLee12138/agent-team/internal"
 )
@@ -17,7 +18,7 @@ func TestRunTaskCreateCreatesTaskPackage(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(roleDir, "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
 		t.Fatalf("write skill: %v", err)
 	}
-	if err := app.RunTaskCreate("Implement lifecycle", "backend", ""); err != nil {
+	if err := app.RunTaskCreate("Implement lifecycle", "backend", "", "", ""); err != nil {
 		t.Fatalf("RunTaskCreate: %v", err)
 	}
 	entries, err := os.ReadDir(filepath.Join(dir, ".agent-team", "task"))
@@ -32,3 +33,77 @@ func TestRunTaskCreateCreatesTaskPackage(t *testing.T) {
 		t.Fatalf("verification.md should exist: %v", err)
 	}
 }
+
+func TestRunTaskCreateLinksPhase(t *testing.T) {
+	app, dir := initTestApp(t)
+	roleDir := filepath.Join(dir, ".agent-team", "teams", "backend")
+	if err := os.MkdirAll(roleDir, 0755); err != nil {
+		t.Fatalf("mkdir role dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(roleDir, "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatalf("write skill: %v", err)
+	}
+	milestone, err := internal.CreatePlanningRecord(dir, internal.PlanningKindMilestone, "Milestone", time.Now().UTC())
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord milestone: %v", err)
+	}
+	if err := app.RunTaskCreate("Wrong parent", "backend", "", milestone.ID, ""); err == nil {
+		t.Fatal("expected error for non-phase --phase")
+	}
+	phase, err := internal.CreatePlanningRecord(dir, internal.PlanningKindPhase, "Phase", time.Now().UTC())
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord phase: %v", err)
+	}
+	if err := app.RunTaskCreate("Linked task", "backend", "", phase.ID, ""); err != nil {
+		t.Fatalf("RunTaskCreate: %v", err)
+	}
+	loaded, err := internal.LoadPlanningRecord(dir, phase.ID)
+	if err != nil {
+		t.Fatalf("LoadPlanningRecord: %v", err)
+	}
+	if len(loaded.TaskIDs) != 1 {
+		t.Fatalf("phase TaskIDs = %v", loaded.TaskIDs)
+	}
+	task, _, err := internal.LoadTaskRecord(dir, loaded.TaskIDs[0])
+	if err != nil {
+		t.Fatalf("LoadTaskRecord: %v", err)
+	}
+	if len(task.ParentIDs) != 1 || task.ParentIDs[0] != phase.ID {
+		t.Fatalf("task ParentIDs = %v", task.ParentIDs)
+	}
+}
+
+func TestRunTaskCreateWithTemplate(t *testing.T) {
+	app, dir := initTestApp(t)
+	roleDir := filepath.Join(dir, ".agent-team", "teams", "backend")
+	if err := os.MkdirAll(roleDir, 0755); err != nil {
+		t.Fatalf("mkdir role dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(roleDir, "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatalf("write skill: %v", err)
+	}
+	tmplDir := filepath.Join(internal.ProjectTaskTemplatesDir(dir), "spike")
+	if err := os.MkdirAll(tmplDir, 0755); err != nil {
+		t.Fatalf("mkdir template dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmplDir, "context.md"), []byte("# Spike: {{.Title}}\n"), 0644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	if err := app.RunTaskCreate("Try caching", "backend", "", "", "missing"); err == nil {
+		t.Fatal("expected error for unknown template")
+	}
+	if err := app.RunTaskCreate("Try caching", "backend", "", "", "spike"); err != nil {
+		t.Fatalf("RunTaskCreate: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, ".agent-team", "task"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("task entries = %v, %v", entries, err)
+	}
+	data, err := os.ReadFile(internal.TaskContextPath(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatalf("read context.md: %v", err)
+	}
+	if string(data) != "# Spike: Try caching\n" {
+		t.Fatalf("context.md = %q", data)
+	}
+}
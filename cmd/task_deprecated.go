@@ -9,19 +9,21 @@ import (
 )
 
 func newTaskDeprecatedCmd() *cobra.Command {
+	var reason string
 	cmd := &cobra.Command{
 		Use:   "deprecated <task-id>",
 		Short: "Move a task to deprecated state",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return GetApp(cmd).RunTaskDeprecated(args[0])
+			return GetApp(cmd).RunTaskDeprecated(args[0], reason)
 		},
 	}
+	cmd.Flags().StringVar(&reason, "reason", "", "Why the task is abandoned; recorded in history.jsonl")
 	return cmd
 }
 
-func (a *App) RunTaskDeprecated(taskID string) error {
-	record, err := internal.DeprecateTask(a.Git.Root(), taskID, time.Now().UTC())
+func (a *App) RunTaskDeprecated(taskID, reason string) error {
+	record, err := internal.DeprecateTask(a.Git.Root(), taskID, reason, time.Now().UTC())
 	if err != nil {
 		return err
 	}
@@ -14,7 +14,7 @@ func TestRunTaskDeprecatedMovesTask(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTaskPackage: %v", err)
 	}
-	if err := app.RunTaskDeprecated(record.TaskID); err != nil {
+	if err := app.RunTaskDeprecated(record.TaskID, ""); err != nil {
 		t.Fatalf("RunTaskDeprecated: %v", err)
 	}
 	loaded, location, err := internal.LoadTaskRecord(dir, record.TaskID)
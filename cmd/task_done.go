@@ -9,19 +9,21 @@ import (
 )
 
 func newTaskDoneCmd() *cobra.Command {
+	var reason string
 	cmd := &cobra.Command{
 		Use:   "done <task-id>",
 		Short: "Mark an assigned task as verifying",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return GetApp(cmd).RunTaskDone(args[0])
+			return GetApp(cmd).RunTaskDone(args[0], reason)
 		},
 	}
+	cmd.Flags().StringVar(&reason, "reason", "", "Note recorded with the transition in history.jsonl")
 	return cmd
 }
 
-func (a *App) RunTaskDone(taskID string) error {
-	record, err := internal.MarkTaskDone(a.Git.Root(), taskID, time.Now().UTC())
+func (a *App) RunTaskDone(taskID, reason string) error {
+	record, err := internal.MarkTaskDone(a.Git.Root(), taskID, reason, time.Now().UTC())
 	if err != nil {
 		return err
 	}
@@ -13,10 +13,10 @@ func TestRunTaskDoneMarksAssignedTaskVerifying(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTaskPackage: %v", err)
 	}
-	if _, err := internal.BindTaskToWorker(dir, record.TaskID, "backend-001", time.Now().UTC()); err != nil {
+	if _, err := internal.BindTaskToWorker(dir, record.TaskID, "backend-001", "", time.Now().UTC()); err != nil {
 		t.Fatalf("BindTaskToWorker: %v", err)
 	}
-	if err := app.RunTaskDone(record.TaskID); err != nil {
+	if err := app.RunTaskDone(record.TaskID, ""); err != nil {
 		t.Fatalf("RunTaskDone: %v", err)
 	}
 	loaded, _, err := internal.LoadTaskRecord(dir, record.TaskID)
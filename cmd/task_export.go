@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newTaskExportCmd() *cobra.Command {
+	var format string
+	var output string
+	var archived bool
+	cmd := &cobra.Command{
+		Use:   "export --format github-issues|jira-csv|markdown [--output <file>] [--archived]",
+		Short: "Export task packages for an issue tracker",
+		Long: "github-issues: JSON in the shape of `gh issue list --json number,title,body,state,labels,url`;\n" +
+			"each body carries an agent-team marker so `task import --format github-issues-json` maps it back.\n" +
+			"jira-csv: a CSV for Jira's importer. markdown: a checklist that `task import` reads back.\n" +
+			"Bodies come from context.md; existing external_refs fill the issue number/key.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunTaskExport(format, output, archived)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "Export format: github-issues, jira-csv, or markdown")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to a file instead of stdout")
+	cmd.Flags().BoolVar(&archived, "archived", false, "Include archived and deprecated tasks")
+	_ = cmd.MarkFlagRequired("format")
+	return cmd
+}
+
+func (a *App) RunTaskExport(format, output string, includeArchived bool) error {
+	root := a.Git.Root()
+	tasks, err := internal.ListTasks(root, !includeArchived)
+	if err != nil {
+		return err
+	}
+	data, err := internal.ExportTasks(root, tasks, format)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	fmt.Printf("✓ Exported %d task(s) to %s\n", len(tasks), output)
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newTaskImportCmd() *cobra.Command {
+	var format string
+	var role string
+	var phase string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "import <file> [--format markdown|yaml|github-issues-json] [--role <role>] [--phase <id>] [--dry-run]",
+		Short: "Create task packages from a markdown checklist, YAML plan, or GitHub issues export",
+		Long: "Markdown: every unchecked top-level \"- [ ] <title>\" item is a task; indented \"- key: value\" bullets\n" +
+			"set key, role, design, phase, template and depends_on (comma separated).\n" +
+			"YAML: a list of {key, title, role, design, phase, template, depends_on}, or a mapping with\n" +
+			"a \"tasks\" list and optional \"role\"/\"phase\" defaults.\n" +
+			"Each task is identified by its key (default: the slugified title); tasks whose key was already\n" +
+			"imported are skipped, so re-importing a file is safe. Design paths are relative to the file.\n" +
+			"github-issues-json: output of `gh issue list --json number,title,body,state,labels,url`. Issues are\n" +
+			"matched through external_refs in task.yaml, so re-syncing updates titles instead of duplicating;\n" +
+			"the role comes from a role:<name> label or --role.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunTaskImport(args[0], format, role, phase, dryRun)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "Input format: markdown, yaml, or github-issues-json (default: from the file extension)")
+	cmd.Flags().StringVar(&role, "role", "", "Default role for tasks that do not set one")
+	cmd.Flags().StringVar(&phase, "phase", "", "Default phase to link imported tasks to")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be created without writing")
+	return cmd
+}
+
+func (a *App) RunTaskImport(path, format, role, phase string, dryRun bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read import file: %w", err)
+	}
+	if format == "" {
+		format = taskImportFormatFromPath(path)
+	}
+	opts := internal.TaskImportOptions{
+		BaseDir: filepath.Dir(path),
+		Role:    role,
+		Phase:   phase,
+		DryRun:  dryRun,
+	}
+	if format == "github-issues-json" {
+		issues, err := internal.ParseGitHubIssuesJSON(data)
+		if err != nil {
+			return err
+		}
+		results, err := internal.ImportExternalIssues(a.Git.Root(), issues, opts, time.Now().UTC())
+		return finishTaskImport(results, err, dryRun)
+	}
+
+	var items []internal.TaskImportItem
+	switch format {
+	case "markdown", "md":
+		items, err = internal.ParseTaskImportMarkdown(data)
+	case "yaml", "yml":
+		items, err = internal.ParseTaskImportYAML(data)
+	default:
+		return fmt.Errorf("invalid --format %q: use markdown, yaml, or github-issues-json", format)
+	}
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Println("No tasks found in import file.")
+		return nil
+	}
+
+	results, err := internal.ImportTasks(a.Git.Root(), items, opts, time.Now().UTC())
+	return finishTaskImport(results, err, dryRun)
+}
+
+func finishTaskImport(results []internal.TaskImportResult, err error, dryRun bool) error {
+	if err != nil {
+		for _, result := range results {
+			if result.Action == internal.TaskImportCreate && result.TaskID != "" {
+				fmt.Printf("✓ Created task '%s' before the failure\n", result.TaskID)
+			}
+		}
+		return err
+	}
+	printTaskImportResults(results, dryRun)
+	return nil
+}
+
+func printTaskImportResults(results []internal.TaskImportResult, dryRun bool) {
+	created, updated, existing := 0, 0, 0
+	for _, result := range results {
+		switch result.Action {
+		case internal.TaskImportCreate:
+			created++
+		case internal.TaskImportUpdate:
+			updated++
+		default:
+			existing++
+		}
+	}
+	fmt.Printf("%-8s %-28s %-14s %-20s %s\n", "Action", "Key", "Role", "Phase", "Task")
+	fmt.Printf("%-8s %-28s %-14s %-20s %s\n", "────────", "────────────────────────────", "──────────────", "────────────────────", "────────────────────────────────")
+	for _, result := range results {
+		task := result.TaskID
+		if task == "" {
+			task = result.Item.Title
+		}
+		if len(result.DependsOn) > 0 {
+			task += " (after " + strings.Join(result.DependsOn, ", ") + ")"
+		}
+		fmt.Printf("%-8s %-28s %-14s %-20s %s\n", result.Action, result.Item.Key, dashIfEmpty(result.Item.Role), dashIfEmpty(result.Item.Phase), task)
+	}
+	summary := fmt.Sprintf("%d already imported", existing)
+	if updated > 0 {
+		summary = fmt.Sprintf("%d updated, %s", updated, summary)
+	}
+	if dryRun {
+		fmt.Printf("Dry run: %d task(s) would be created, %s.\n", created, summary)
+		return
+	}
+	fmt.Printf("✓ Imported %d task(s), %s\n", created, summary)
+}
+
+func taskImportFormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "github-issues-json"
+	default:
+		return "markdown"
+	}
+}
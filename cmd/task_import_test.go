@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+)
+
+func TestRunTaskImportMarkdown(t *testing.T) {
+	app, dir := initTestApp(t)
+	roleDir := filepath.Join(dir, ".agent-team", "teams", "backend")
+	if err := os.MkdirAll(roleDir, 0755); err != nil {
+		t.Fatalf("mkdir role dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(roleDir, "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatalf("write skill: %v", err)
+	}
+	planDir := filepath.Join(dir, "docs")
+	if err := os.MkdirAll(planDir, 0755); err != nil {
+		t.Fatalf("mkdir docs: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(planDir, "api.md"), []byte("# API design\n"), 0644); err != nil {
+		t.Fatalf("write design: %v", err)
+	}
+	plan := filepath.Join(planDir, "plan.md")
+	if err := os.WriteFile(plan, []byte("- [ ] Build API\n  - design: api.md\n- [ ] Write docs\n  - depends_on: build-api\n"), 0644); err != nil {
+		t.Fatalf("write plan: %v", err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := app.RunTaskImport(plan, "", "backend", "", true); err != nil {
+			t.Fatalf("RunTaskImport dry-run: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Dry run: 2 task(s) would be created") {
+		t.Fatalf("dry-run output:\n%s", out)
+	}
+
+	out = captureStdout(t, func() {
+		if err := app.RunTaskImport(plan, "", "backend", "", false); err != nil {
+			t.Fatalf("RunTaskImport: %v", err)
+		}
+	})
+	if !strings.Contains(out, "✓ Imported 2 task(s), 0 already imported") {
+		t.Fatalf("import output:\n%s", out)
+	}
+	tasks, err := internal.ListTasks(dir, true)
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("tasks = %v, %v", tasks, err)
+	}
+	for _, task := range tasks {
+		if task.ImportKey != "build-api" {
+			continue
+		}
+		data, _ := os.ReadFile(internal.TaskContextPath(dir, task.TaskID))
+		if !strings.Contains(string(data), "# API design") {
+			t.Fatalf("context.md missing design:\n%s", data)
+		}
+	}
+
+	out = captureStdout(t, func() {
+		if err := app.RunTaskImport(plan, "", "backend", "", false); err != nil {
+			t.Fatalf("RunTaskImport again: %v", err)
+		}
+	})
+	if !strings.Contains(out, "✓ Imported 0 task(s), 2 already imported") {
+		t.Fatalf("re-import output:\n%s", out)
+	}
+}
+
+func TestRunTaskExportWritesFile(t *testing.T) {
+	app, dir := initTestApp(t)
+	if _, err := internal.CreateTaskPackage(dir, "Export me", "backend", "", time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	output := filepath.Join(t.TempDir(), "issues.json")
+	out := captureStdout(t, func() {
+		if err := app.RunTaskExport("github-issues", output, false); err != nil {
+			t.Fatalf("RunTaskExport: %v", err)
+		}
+	})
+	if !strings.Contains(out, "✓ Exported 1 task(s)") {
+		t.Fatalf("output = %s", out)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if !strings.Contains(string(data), `"title": "Export me"`) || !strings.Contains(string(data), "role:backend") {
+		t.Fatalf("export = %s", data)
+	}
+	if err := app.RunTaskExport("yaml", "", false); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
@@ -15,17 +15,17 @@ func TestRunTaskListShowsActiveByDefault(t *testing.T) {
 	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
 	active, _ := internal.CreateTaskPackage(dir, "Active", "backend", "", now)
 	archived, _ := internal.CreateTaskPackage(dir, "Archived", "backend", "", now.Add(time.Minute))
-	if _, err := internal.BindTaskToWorker(dir, archived.TaskID, "backend-001", now.Add(2*time.Minute)); err != nil {
+	if _, err := internal.BindTaskToWorker(dir, archived.TaskID, "backend-001", "", now.Add(2*time.Minute)); err != nil {
 		t.Fatalf("BindTaskToWorker: %v", err)
 	}
-	if _, err := internal.MarkTaskDone(dir, archived.TaskID, now.Add(3*time.Minute)); err != nil {
+	if _, err := internal.MarkTaskDone(dir, archived.TaskID, "", now.Add(3*time.Minute)); err != nil {
 		t.Fatalf("MarkTaskDone: %v", err)
 	}
 	verificationPath := internal.TaskVerificationPath(dir, archived.TaskID)
 	if err := os.WriteFile(verificationPath, []byte("# Verification\n\n## Result\n- pass\n"), 0644); err != nil {
 		t.Fatalf("WriteFile verification: %v", err)
 	}
-	if _, err := internal.ArchiveTask(dir, archived.TaskID, "abc123", false, now.Add(4*time.Minute)); err != nil {
+	if _, err := internal.ArchiveTask(dir, archived.TaskID, "abc123", "", false, now.Add(4*time.Minute)); err != nil {
 		t.Fatalf("ArchiveTask: %v", err)
 	}
 	list, err := internal.ListTasks(dir, true)
@@ -46,10 +46,10 @@ func TestRunTaskListPrintsVerificationColumns(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTaskPackage: %v", err)
 	}
-	if _, err := internal.BindTaskToWorker(dir, record.TaskID, "backend-001", time.Now().UTC()); err != nil {
+	if _, err := internal.BindTaskToWorker(dir, record.TaskID, "backend-001", "", time.Now().UTC()); err != nil {
 		t.Fatalf("BindTaskToWorker: %v", err)
 	}
-	if _, err := internal.MarkTaskDone(dir, record.TaskID, time.Now().UTC()); err != nil {
+	if _, err := internal.MarkTaskDone(dir, record.TaskID, "", time.Now().UTC()); err != nil {
 		t.Fatalf("MarkTaskDone: %v", err)
 	}
 	if err := os.WriteFile(internal.TaskVerificationPath(dir, record.TaskID), []byte("# Verification\n\n## Result\n- partial\n"), 0644); err != nil {
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/JsonLee12138/agent-team/internal"
+	"github.com/spf13/cobra"
+)
+
+func newTaskLogCmd() *cobra.Command {
+	var jsonOutput bool
+	cmd := &cobra.Command{
+		Use:   "log <task-id> [--json]",
+		Short: "Show a task's history timeline",
+		Long: "Reads history.jsonl from the task package: creation, every status transition with actor and\n" +
+			"reason (including reassignments and reopenings), and comments added with `task comment`.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return GetApp(cmd).RunTaskLog(os.Stdout, args[0], jsonOutput)
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print raw history entries as JSON lines")
+	return cmd
+}
+
+func (a *App) RunTaskLog(out io.Writer, taskID string, jsonOutput bool) error {
+	entries, err := internal.ReadTaskHistory(a.Git.Root(), taskID)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		encoder := json.NewEncoder(out)
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(out, "No history recorded for task '%s'.\n", taskID)
+		return nil
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(out, "%-20s  %-26s  by %s\n", entry.Time, describeTaskHistoryEntry(entry), entry.Actor)
+		if entry.WorkerID != "" && entry.Action == internal.TaskHistoryTransition && entry.To == internal.TaskStatusAssigned {
+			fmt.Fprintf(out, "  worker: %s\n", entry.WorkerID)
+		}
+		if entry.Reason != "" {
+			fmt.Fprintf(out, "  reason: %s\n", entry.Reason)
+		}
+		for _, key := range slices.Sorted(maps.Keys(entry.Data)) {
+			fmt.Fprintf(out, "  %s: %s\n", strings.ReplaceAll(key, "_", " "), entry.Data[key])
+		}
+		if entry.Text != "" {
+			for _, line := range strings.Split(entry.Text, "\n") {
+				fmt.Fprintf(out, "  │ %s\n", line)
+			}
+		}
+	}
+	return nil
+}
+
+// describeTaskHistoryEntry renders the action column of a history line.
+func describeTaskHistoryEntry(entry internal.TaskHistoryEntry) string {
+	switch entry.Action {
+	case internal.TaskHistoryTransition:
+		if entry.From == entry.To && entry.To == internal.TaskStatusAssigned {
+			return "reassigned"
+		}
+		return fmt.Sprintf("%s → %s", entry.From, entry.To)
+	case internal.TaskHistoryCreated:
+		return "created (" + string(entry.To) + ")"
+	default:
+		return string(entry.Action)
+	}
+}
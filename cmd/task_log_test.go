@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JsonLee12138/agent-team/internal"
+)
+
+func TestRunTaskCommentAndLog(t *testing.T) {
+	t.Setenv("AGENT_TEAM_ACTOR", "reviewer")
+	app, dir := initTestApp(t)
+	record, err := internal.CreateTaskPackage(dir, "Log Task", "backend", "", time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	if _, err := internal.BindTaskToWorker(dir, record.TaskID, "backend-001", "first pass", time.Now().UTC()); err != nil {
+		t.Fatalf("BindTaskToWorker: %v", err)
+	}
+	captureStdout(t, func() {
+		if err := app.RunTaskComment(record.TaskID, "please add retries"); err != nil {
+			t.Fatalf("RunTaskComment: %v", err)
+		}
+	})
+
+	var out bytes.Buffer
+	if err := app.RunTaskLog(&out, record.TaskID, false); err != nil {
+		t.Fatalf("RunTaskLog: %v", err)
+	}
+	for _, needle := range []string{"created (draft)", "draft → assigned", "worker: backend-001", "reason: first pass", "comment", "│ please add retries", "by reviewer"} {
+		if !strings.Contains(out.String(), needle) {
+			t.Fatalf("log should include %q, got:\n%s", needle, out.String())
+		}
+	}
+
+	show := captureStdout(t, func() {
+		if err := app.RunTaskShow(record.TaskID); err != nil {
+			t.Fatalf("RunTaskShow: %v", err)
+		}
+	})
+	for _, needle := range []string{"History: 3 entries (1 transitions, 1 comments)", "Workers: backend-001", "Last Activity: ", "comment by reviewer"} {
+		if !strings.Contains(show, needle) {
+			t.Fatalf("show should include %q, got:\n%s", needle, show)
+		}
+	}
+}
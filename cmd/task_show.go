@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/JsonLee12138/agent-team/internal"
@@ -47,6 +49,21 @@ func (a *App) RunTaskShow(taskID string) error {
 	if record.WorkerID != "" {
 		fmt.Printf("Worker: %s\n", record.WorkerID)
 	}
+	if record.Requirement != "" {
+		fmt.Printf("Requirement: %s#%d\n", record.Requirement, record.SubTaskID)
+	}
+	if len(record.ParentIDs) > 0 {
+		fmt.Printf("Planning: %s\n", strings.Join(record.ParentIDs, ", "))
+	}
+	if len(record.DependsOn) > 0 {
+		fmt.Printf("Depends On: %s\n", strings.Join(record.DependsOn, ", "))
+	}
+	if record.ImportKey != "" {
+		fmt.Printf("Import Key: %s\n", record.ImportKey)
+	}
+	for _, system := range slices.Sorted(maps.Keys(record.ExternalRefs)) {
+		fmt.Printf("External (%s): %s\n", system, record.ExternalRefs[system])
+	}
 	fmt.Printf("Created At: %s\n", record.CreatedAt)
 	if record.AssignedAt != "" {
 		fmt.Printf("Assigned At: %s\n", record.AssignedAt)
@@ -63,6 +80,17 @@ func (a *App) RunTaskShow(taskID string) error {
 	if record.MergedSHA != "" {
 		fmt.Printf("Merged SHA: %s\n", record.MergedSHA)
 	}
+	history, err := internal.ReadTaskHistory(root, taskID)
+	if err != nil {
+		return err
+	}
+	if summary := internal.SummarizeTaskHistory(history); summary.Last != nil {
+		fmt.Printf("History: %d entries (%d transitions, %d comments)\n", summary.Entries, summary.Transitions, summary.Comments)
+		if len(summary.Workers) > 0 {
+			fmt.Printf("Workers: %s\n", strings.Join(summary.Workers, ", "))
+		}
+		fmt.Printf("Last Activity: %s %s by %s\n", summary.Last.Time, describeTaskHistoryEntry(*summary.Last), summary.Last.Actor)
+	}
 	fmt.Printf("Verification Exists: yes\n")
 	fmt.Printf("Verification Result: %s\n", verificationResult)
 	fmt.Printf("Archive Ready (default): %s\n", yesNo(canArchive(verificationResult, false)))
@@ -29,5 +29,5 @@ func newWorkerAssignCmd() *cobra.Command {
 
 func (a *App) RunWorkerAssign(workerID, taskID, provider, model string, newWindow bool) error {
 	fmt.Println("worker assign is deprecated; delegating to 'agent-team task assign'.")
-	return a.RunTaskAssign(taskID, workerID, provider, model, newWindow)
+	return a.RunTaskAssign(taskID, workerID, provider, model, "", newWindow)
 }
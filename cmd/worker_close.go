@@ -26,7 +26,7 @@ func (a *App) RunWorkerClose(workerID string) error {
 		return fmt.Errorf("worker '%s' not found: %w", workerID, err)
 	}
 
-	if err := closeWorkerSession(a.Session, cfg, configPath); err != nil {
+	if err := closeWorkerSession(root, a.Session, workerID, cfg, configPath); err != nil {
 		return err
 	}
 
@@ -37,8 +37,9 @@ func (a *App) RunWorkerClose(workerID string) error {
 // closeWorkerSession is a shared helper that shuts down the terminal pane
 // for a worker and clears PaneID. It is idempotent: it succeeds if the pane
 // is already gone or PaneID is already empty. It preserves ControllerPaneID,
-// Provider, and DefaultModel.
-func closeWorkerSession(session internal.SessionBackend, cfg *internal.WorkerConfig, configPath string) error {
+// Provider, and DefaultModel. worker_close hooks run only when a live pane is
+// actually shut down.
+func closeWorkerSession(root string, session internal.SessionBackend, workerID string, cfg *internal.WorkerConfig, configPath string) error {
 	// Already closed — nothing to do.
 	if cfg.PaneID == "" {
 		return nil
@@ -50,11 +51,21 @@ func closeWorkerSession(session internal.SessionBackend, cfg *internal.WorkerCon
 		return cfg.Save(configPath)
 	}
 
+	payload := internal.NewWorkerHookPayload(internal.HookEventWorkerClose, workerID, cfg)
+	if err := internal.RunPreHooks(root, payload); err != nil {
+		return err
+	}
+
 	// Pane is alive — kill it.
 	if err := session.KillPane(cfg.PaneID); err != nil {
 		return fmt.Errorf("failed to close pane %s: %w", cfg.PaneID, err)
 	}
 
 	cfg.PaneID = ""
-	return cfg.Save(configPath)
+	if err := cfg.Save(configPath); err != nil {
+		return err
+	}
+	internal.RunPostHooks(root, payload)
+	internal.EmitEvent(root, internal.Event{Type: internal.EventWorkerClosed, WorkerID: workerID, TaskID: cfg.TaskID, Role: cfg.Role})
+	return nil
 }
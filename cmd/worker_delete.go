@@ -35,7 +35,7 @@ func (a *App) RunWorkerDelete(workerID string) error {
 
 	cfg, loadedConfigPath, err := internal.LoadWorkerConfigByID(root, a.WtBase, workerID)
 	if err == nil {
-		if err := closeWorkerSession(a.Session, cfg, loadedConfigPath); err != nil {
+		if err := closeWorkerSession(root, a.Session, workerID, cfg, loadedConfigPath); err != nil {
 			return fmt.Errorf("failed to close session before delete: %w", err)
 		}
 	}
@@ -48,6 +48,7 @@ func (a *App) RunWorkerDelete(workerID string) error {
 
 	a.Git.DeleteBranch("team/" + workerID)
 	_ = os.Remove(configPath)
+	internal.EmitEvent(root, internal.Event{Type: internal.EventWorkerDeleted, WorkerID: workerID})
 
 	fmt.Printf("✓ Deleted worker '%s'\n", workerID)
 	return nil
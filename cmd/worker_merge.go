@@ -30,12 +30,22 @@ func (a *App) RunWorkerMerge(workerID string) error {
 	}
 
 	mainBranch, _ := a.Git.CurrentBranch()
+	cfg, _, _ := internal.LoadWorkerConfigByID(root, a.WtBase, workerID)
+	payload := internal.NewWorkerHookPayload(internal.HookEventWorkerMerge, workerID, cfg)
+	if err := internal.RunPreHooks(root, payload); err != nil {
+		return err
+	}
 
 	fmt.Printf("Merging branch '%s' into '%s'...\n", branch, mainBranch)
 	msg := fmt.Sprintf("merge: integrate work from worker '%s'", workerID)
 	if err := a.Git.Merge(branch, msg); err != nil {
 		return err
 	}
+	if sha, err := a.Git.HeadSHA(); err == nil {
+		payload.MergedSHA = sha
+	}
+	internal.RunPostHooks(root, payload)
+	internal.EmitEvent(root, internal.Event{Type: internal.EventWorkerMerged, WorkerID: workerID, TaskID: payload.TaskID, Role: payload.Role, Data: map[string]string{"merged_sha": payload.MergedSHA, "into": mainBranch}})
 
 	fmt.Printf("✓ Merged '%s' into %s\n", workerID, mainBranch)
 	fmt.Printf("  → Run 'agent-team worker delete %s' to remove the worktree when done\n", workerID)
@@ -93,6 +93,11 @@ func (a *App) RunWorkerOpen(workerID, provider, model string, newWindow, persist
 		return nil
 	}
 
+	hookPayload := internal.NewWorkerHookPayload(internal.HookEventWorkerOpen, workerID, cfg)
+	if err := internal.RunPreHooks(root, hookPayload); err != nil {
+		return err
+	}
+
 	if usedCompatProviderFallback {
 		fmt.Println("  worker.yaml has no provider; using claude for this launch only")
 	}
@@ -115,6 +120,7 @@ func (a *App) RunWorkerOpen(workerID, provider, model string, newWindow, persist
 	}
 
 	a.Session.SetTitle(paneID, workerID)
+	internal.EmitEvent(root, internal.Event{Type: internal.EventWorkerSpawned, WorkerID: workerID, TaskID: cfg.TaskID, Role: cfg.Role, Data: map[string]string{"provider": sessionProvider, "pane_id": paneID}})
 
 	// Return focus (wezterm tab mode only)
 	if !newWindow {
@@ -141,6 +147,7 @@ func (a *App) RunWorkerOpen(workerID, provider, model string, newWindow, persist
 
 	launchCmd := internal.BuildLaunchCmd(sessionProvider, sessionModel)
 	a.Session.PaneSend(paneID, launchCmd)
+	internal.RunPostHooks(root, hookPayload)
 
 	fmt.Printf("✓ Opened worker '%s' (role: %s, provider: %s) [pane %s]\n", workerID, cfg.Role, sessionProvider, paneID)
 	return nil
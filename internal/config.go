@@ -5,16 +5,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-
-	"gopkg.in/yaml.v3"
 )
 
 // WorkerConfig represents an employee instance of a role.
 type WorkerConfig struct {
+	SchemaVersion    int        `yaml:"schema_version"`
 	WorkerID         string     `yaml:"worker_id"`
 	Role             string     `yaml:"role"`
-	RoleScope        string     `yaml:"role_scope,omitempty"`      // "project" | "global"
-	RolePath         string     `yaml:"role_path,omitempty"`       // absolute path for global roles
+	RoleScope        string     `yaml:"role_scope,omitempty"` // "project" | "global"
+	RolePath         string     `yaml:"role_path,omitempty"`  // absolute path for global roles
 	Provider         string     `yaml:"provider"`
 	DefaultModel     string     `yaml:"default_model,omitempty"`
 	MainSessionID    string     `yaml:"main_session_id,omitempty"`
@@ -26,6 +25,7 @@ type WorkerConfig struct {
 	CreatedAt        string     `yaml:"created_at"`
 	UpdatedAt        string     `yaml:"updated_at,omitempty"`
 	WorktreeCreated  *bool      `yaml:"worktree_created,omitempty"`
+	Revision         int        `yaml:"revision,omitempty"`
 }
 
 // WorkerYAMLPath returns the path to worker.yaml in the worktree root.
@@ -49,7 +49,7 @@ func LoadWorkerConfig(path string) (*WorkerConfig, error) {
 		return nil, fmt.Errorf("read worker config %s: %w", path, err)
 	}
 	var cfg WorkerConfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if err := decodeMigratedYAML(SchemaKindWorker, data, &cfg); err != nil {
 		return nil, fmt.Errorf("parse worker config %s: %w", path, err)
 	}
 	return &cfg, nil
@@ -80,9 +80,6 @@ func (c *WorkerConfig) Save(path string) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("create config directory: %w", err)
 	}
-	data, err := yaml.Marshal(c)
-	if err != nil {
-		return fmt.Errorf("marshal worker config: %w", err)
-	}
-	return os.WriteFile(path, data, 0644)
+	c.SchemaVersion = CurrentSchemaVersion(SchemaKindWorker)
+	return saveYAMLState(path, &c.Revision, c)
 }
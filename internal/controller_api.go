@@ -0,0 +1,516 @@
+package internal
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ControllerActions performs operations that need the CLI's git and session
+// wiring. Each field mirrors the matching agent-team command so the API and
+// the CLI share one implementation.
+type ControllerActions struct {
+	AssignTask  func(taskID, workerID, provider, model, reason string) error
+	ArchiveTask func(taskID, mergedSHA, reason string, strict bool) error
+	Reply       func(workerID, message string) error
+}
+
+// ControllerAPIOptions configures the controller HTTP handler.
+type ControllerAPIOptions struct {
+	Root    string
+	WtBase  string
+	Token   string
+	Session SessionBackend
+	Actions ControllerActions
+	// AllowedOrigins lists browser origins that get CORS headers. Requests
+	// from them still need the token; only preflights are answered without it.
+	AllowedOrigins []string
+	// EventPollInterval controls how often the SSE stream polls events.jsonl.
+	EventPollInterval time.Duration
+}
+
+// NewControllerAPIHandler builds the HTTP handler for task, worker and
+// planning operations. All endpoints require the configured token, passed as
+// "Authorization: Bearer <token>" or, for EventSource clients, ?token=.
+// Browser clients on AllowedOrigins are served with CORS headers.
+func NewControllerAPIHandler(opts ControllerAPIOptions) http.Handler {
+	if opts.EventPollInterval <= 0 {
+		opts.EventPollInterval = 500 * time.Millisecond
+	}
+	api := &controllerAPI{opts: opts}
+	mux := http.NewServeMux()
+	mux.HandleFunc(catalogAPIBasePath+"/tasks/", api.handleTaskItem)
+	mux.HandleFunc(catalogAPIBasePath+"/tasks", api.handleTasks)
+	mux.HandleFunc(catalogAPIBasePath+"/workers/", api.handleWorkerItem)
+	mux.HandleFunc(catalogAPIBasePath+"/workers", api.handleWorkers)
+	mux.HandleFunc(catalogAPIBasePath+"/planning", api.handlePlanning)
+	mux.HandleFunc(catalogAPIBasePath+"/events", api.handleEvents)
+	return api.allowOrigins(api.requireToken(mux))
+}
+
+type controllerAPI struct {
+	opts ControllerAPIOptions
+}
+
+type taskDTO struct {
+	TaskID       string     `json:"task_id"`
+	Title        string     `json:"title"`
+	Role         string     `json:"role"`
+	Status       TaskStatus `json:"status"`
+	WorkerID     string     `json:"worker_id,omitempty"`
+	TaskPath     string     `json:"task_path"`
+	CreatedAt    string     `json:"created_at"`
+	AssignedAt   string     `json:"assigned_at,omitempty"`
+	VerifyingAt  string     `json:"verifying_at,omitempty"`
+	ArchivedAt   string     `json:"archived_at,omitempty"`
+	DeprecatedAt string     `json:"deprecated_at,omitempty"`
+	MergedSHA    string     `json:"merged_sha,omitempty"`
+	Revision     int        `json:"revision"`
+}
+
+type taskListResponse struct {
+	Items []taskDTO `json:"items"`
+	Total int       `json:"total"`
+}
+
+type taskDetailResponse struct {
+	Item     taskDTO            `json:"item"`
+	Location TaskRecordLocation `json:"location"`
+}
+
+type workerDTO struct {
+	WorkerID string     `json:"worker_id"`
+	Role     string     `json:"role"`
+	Provider string     `json:"provider,omitempty"`
+	TaskID   string     `json:"task_id,omitempty"`
+	Status   TaskStatus `json:"status,omitempty"`
+	PaneID   string     `json:"pane_id,omitempty"`
+	Alive    bool       `json:"alive"`
+}
+
+type workerListResponse struct {
+	Items []workerDTO `json:"items"`
+	Total int         `json:"total"`
+}
+
+type planningDTO struct {
+	ID           string            `json:"id"`
+	Kind         PlanningKind      `json:"kind"`
+	Title        string            `json:"title"`
+	Status       PlanningStatus    `json:"status,omitempty"`
+	Goal         string            `json:"goal,omitempty"`
+	Lifecycle    PlanningLifecycle `json:"lifecycle"`
+	Path         string            `json:"path"`
+	RoadmapIDs   []string          `json:"roadmap_ids,omitempty"`
+	MilestoneIDs []string          `json:"milestone_ids,omitempty"`
+	PhaseIDs     []string          `json:"phase_ids,omitempty"`
+	TaskIDs      []string          `json:"task_ids,omitempty"`
+	CreatedAt    string            `json:"created_at"`
+	UpdatedAt    string            `json:"updated_at"`
+}
+
+type planningListResponse struct {
+	Items []planningDTO `json:"items"`
+	Total int           `json:"total"`
+}
+
+type createTaskRequest struct {
+	Title  string `json:"title"`
+	Role   string `json:"role"`
+	Design string `json:"design"`
+}
+
+type assignTaskRequest struct {
+	WorkerID string `json:"worker_id"`
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Reason   string `json:"reason"`
+}
+
+type doneTaskRequest struct {
+	Reason string `json:"reason"`
+}
+
+type archiveTaskRequest struct {
+	MergedSHA string `json:"merged_sha"`
+	Reason    string `json:"reason"`
+	Strict    bool   `json:"strict"`
+}
+
+type replyRequest struct {
+	Message string `json:"message"`
+}
+
+// allowOrigins adds CORS headers for configured origins and answers their
+// preflight requests, which browsers send without credentials.
+func (api *controllerAPI) allowOrigins(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !api.originAllowed(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (api *controllerAPI) originAllowed(origin string) bool {
+	for _, allowed := range api.opts.AllowedOrigins {
+		if strings.TrimSuffix(strings.TrimSpace(allowed), "/") == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func (api *controllerAPI) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Without a configured token nothing is served: an empty token would
+		// otherwise match an empty ?token= and admit any local page.
+		provided := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			provided = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if api.opts.Token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(api.opts.Token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "missing or invalid token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (api *controllerAPI) handleTasks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		includeArchived := r.URL.Query().Get("archived") == "true"
+		tasks, err := ListTasks(api.opts.Root, !includeArchived)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "task_read_failed", err.Error())
+			return
+		}
+		items := make([]taskDTO, 0, len(tasks))
+		for _, task := range tasks {
+			items = append(items, taskToDTO(task))
+		}
+		writeData(w, http.StatusOK, taskListResponse{Items: items, Total: len(items)})
+	case http.MethodPost:
+		var req createTaskRequest
+		if !decodeRequestBody(w, r, &req) {
+			return
+		}
+		req.Title = strings.TrimSpace(req.Title)
+		req.Role = strings.TrimSpace(req.Role)
+		if req.Title == "" || req.Role == "" {
+			writeError(w, http.StatusBadRequest, "invalid_request", "title and role are required")
+			return
+		}
+		if _, err := ResolveRole(api.opts.Root, req.Role); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_role", err.Error())
+			return
+		}
+		record, err := CreateTaskPackage(api.opts.Root, req.Title, req.Role, req.Design, time.Now().UTC())
+		if err != nil {
+			writeOperationError(w, err)
+			return
+		}
+		writeData(w, http.StatusCreated, taskDetailResponse{Item: taskToDTO(record), Location: TaskRecordLocationActive})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+// handleTaskItem serves /api/tasks/{id} and /api/tasks/{id}/{assign|done|archive}.
+func (api *controllerAPI) handleTaskItem(w http.ResponseWriter, r *http.Request) {
+	taskID, action, ok := parseItemPath(r.URL.Path, catalogAPIBasePath+"/tasks/")
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_task", "path must be /api/tasks/{id}[/{action}]")
+		return
+	}
+
+	if action == "" {
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		api.writeTask(w, http.StatusOK, taskID)
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w)
+		return
+	}
+
+	var err error
+	switch action {
+	case "assign":
+		var req assignTaskRequest
+		if !decodeRequestBody(w, r, &req) {
+			return
+		}
+		err = api.opts.Actions.AssignTask(taskID, req.WorkerID, req.Provider, req.Model, req.Reason)
+	case "done":
+		var req doneTaskRequest
+		if !decodeRequestBody(w, r, &req) {
+			return
+		}
+		_, err = MarkTaskDone(api.opts.Root, taskID, req.Reason, time.Now().UTC())
+	case "archive":
+		var req archiveTaskRequest
+		if !decodeRequestBody(w, r, &req) {
+			return
+		}
+		if strings.TrimSpace(req.MergedSHA) == "" {
+			writeError(w, http.StatusBadRequest, "invalid_request", "merged_sha is required")
+			return
+		}
+		err = api.opts.Actions.ArchiveTask(taskID, req.MergedSHA, req.Reason, req.Strict)
+	default:
+		writeError(w, http.StatusNotFound, "unknown_action", fmt.Sprintf("unknown task action: %s", action))
+		return
+	}
+	if err != nil {
+		writeOperationError(w, err)
+		return
+	}
+	api.writeTask(w, http.StatusOK, taskID)
+}
+
+func (api *controllerAPI) writeTask(w http.ResponseWriter, status int, taskID string) {
+	record, location, err := LoadTaskRecord(api.opts.Root, taskID)
+	if err != nil {
+		writeOperationError(w, err)
+		return
+	}
+	writeData(w, status, taskDetailResponse{Item: taskToDTO(record), Location: location})
+}
+
+func (api *controllerAPI) handleWorkers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+	workers := ListWorkers(api.opts.Root, api.opts.WtBase)
+	items := make([]workerDTO, 0, len(workers))
+	for _, worker := range workers {
+		dto := workerDTO{WorkerID: worker.WorkerID, Role: worker.Role}
+		if cfg := worker.Config; cfg != nil {
+			dto.Provider = cfg.Provider
+			dto.TaskID = cfg.TaskID
+			dto.Status = cfg.Status
+			dto.PaneID = cfg.PaneID
+			dto.Alive = cfg.PaneID != "" && api.opts.Session != nil && api.opts.Session.PaneAlive(cfg.PaneID)
+		}
+		items = append(items, dto)
+	}
+	writeData(w, http.StatusOK, workerListResponse{Items: items, Total: len(items)})
+}
+
+// handleWorkerItem serves POST /api/workers/{id}/reply.
+func (api *controllerAPI) handleWorkerItem(w http.ResponseWriter, r *http.Request) {
+	workerID, action, ok := parseItemPath(r.URL.Path, catalogAPIBasePath+"/workers/")
+	if !ok || action != "reply" {
+		writeError(w, http.StatusNotFound, "unknown_action", "path must be /api/workers/{id}/reply")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w)
+		return
+	}
+	var req replyRequest
+	if !decodeRequestBody(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Message) == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "message is required")
+		return
+	}
+	if err := api.opts.Actions.Reply(workerID, req.Message); err != nil {
+		writeOperationError(w, err)
+		return
+	}
+	writeData(w, http.StatusOK, map[string]string{"worker_id": workerID, "status": "sent"})
+}
+
+func (api *controllerAPI) handlePlanning(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+	var kind PlanningKind
+	var lifecycle PlanningLifecycle
+	var err error
+	if raw := r.URL.Query().Get("kind"); raw != "" {
+		if kind, err = ParsePlanningKind(raw); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_kind", err.Error())
+			return
+		}
+	}
+	if raw := r.URL.Query().Get("lifecycle"); raw != "" {
+		if lifecycle, err = ParsePlanningLifecycle(raw); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_lifecycle", err.Error())
+			return
+		}
+	}
+	records, err := ListPlanningRecords(api.opts.Root, kind, lifecycle)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "planning_read_failed", err.Error())
+		return
+	}
+	items := make([]planningDTO, 0, len(records))
+	for _, record := range records {
+		items = append(items, planningToDTO(record))
+	}
+	writeData(w, http.StatusOK, planningListResponse{Items: items, Total: len(items)})
+}
+
+// handleEvents streams new events.jsonl entries as Server-Sent Events. The
+// optional type and task query parameters narrow the stream.
+func (api *controllerAPI) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming_unsupported", "response does not support streaming")
+		return
+	}
+	filter := EventFilter{TaskID: r.URL.Query().Get("task")}
+	for _, t := range r.URL.Query()["type"] {
+		filter.Types = append(filter.Types, EventType(t))
+	}
+	offset, err := eventsLogSize(api.opts.Root)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "event_read_failed", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	_ = FollowEvents(r.Context(), api.opts.Root, filter, offset, api.opts.EventPollInterval, func(evt Event) error {
+		data, err := json.Marshal(evt)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", evt.Type, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+}
+
+// eventsLogSize returns the current end of events.jsonl so streams only
+// deliver events published after the client connected.
+func eventsLogSize(root string) (int64, error) {
+	info, err := os.Stat(EventsPath(root))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("stat events.jsonl: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// parseItemPath splits "<prefix>{id}[/{action}]" and rejects ids that could
+// escape the state directories.
+func parseItemPath(path, prefix string) (string, string, bool) {
+	rest := strings.Trim(strings.TrimPrefix(path, prefix), "/")
+	if rest == "" {
+		return "", "", false
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) > 2 {
+		return "", "", false
+	}
+	id := parts[0]
+	if id == "." || id == ".." {
+		return "", "", false
+	}
+	action := ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+	return id, action, true
+}
+
+func decodeRequestBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if r.Body == nil || r.ContentLength == 0 {
+		return true
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", fmt.Sprintf("decode request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// writeOperationError maps state-layer errors onto HTTP statuses.
+func writeOperationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		writeError(w, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, ErrStateConflict):
+		writeError(w, http.StatusConflict, "state_conflict", err.Error())
+	default:
+		writeError(w, http.StatusUnprocessableEntity, "operation_failed", err.Error())
+	}
+}
+
+func taskToDTO(record *TaskRecord) taskDTO {
+	return taskDTO{
+		TaskID:       record.TaskID,
+		Title:        record.Title,
+		Role:         record.Role,
+		Status:       record.Status,
+		WorkerID:     record.WorkerID,
+		TaskPath:     record.TaskPath,
+		CreatedAt:    record.CreatedAt,
+		AssignedAt:   record.AssignedAt,
+		VerifyingAt:  record.VerifyingAt,
+		ArchivedAt:   record.ArchivedAt,
+		DeprecatedAt: record.DeprecatedAt,
+		MergedSHA:    record.MergedSHA,
+		Revision:     record.Revision,
+	}
+}
+
+func planningToDTO(record *PlanningRecord) planningDTO {
+	return planningDTO{
+		ID:           record.ID,
+		Kind:         record.Kind,
+		Title:        record.Title,
+		Status:       record.Status,
+		Goal:         record.Goal,
+		Lifecycle:    record.Lifecycle,
+		Path:         record.Path,
+		RoadmapIDs:   record.RoadmapIDs,
+		MilestoneIDs: record.MilestoneIDs,
+		PhaseIDs:     record.PhaseIDs,
+		TaskIDs:      record.TaskIDs,
+		CreatedAt:    record.CreatedAt,
+		UpdatedAt:    record.UpdatedAt,
+	}
+}
@@ -0,0 +1,406 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const controllerTestToken = "secret-token"
+
+type controllerTestSession struct {
+	alive map[string]bool
+}
+
+func (s controllerTestSession) PaneAlive(paneID string) bool           { return s.alive[paneID] }
+func (s controllerTestSession) PaneSend(paneID, text string) error     { return nil }
+func (s controllerTestSession) SpawnPane(string, bool) (string, error) { return "", nil }
+func (s controllerTestSession) KillPane(string) error                  { return nil }
+func (s controllerTestSession) SetTitle(string, string) error          { return nil }
+func (s controllerTestSession) ActivatePane(string) error              { return nil }
+
+func newControllerTestServer(t *testing.T, actions ControllerActions) (*httptest.Server, string) {
+	t.Helper()
+	root := t.TempDir()
+	roleDir := RoleDir(root, "backend")
+	if err := os.MkdirAll(roleDir, 0755); err != nil {
+		t.Fatalf("mkdir role: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(roleDir, "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatalf("write SKILL.md: %v", err)
+	}
+	srv := httptest.NewServer(NewControllerAPIHandler(ControllerAPIOptions{
+		Root:              root,
+		WtBase:            ".worktrees",
+		Token:             controllerTestToken,
+		Session:           controllerTestSession{alive: map[string]bool{"42": true}},
+		Actions:           actions,
+		EventPollInterval: 10 * time.Millisecond,
+	}))
+	t.Cleanup(srv.Close)
+	return srv, root
+}
+
+func controllerRequest(t *testing.T, method, url string, body any) (int, apiTestEnvelope) {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+controllerTestToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	var envelope apiTestEnvelope
+	if err := json.Unmarshal(readBody(t, resp), &envelope); err != nil {
+		t.Fatalf("unmarshal envelope: %v", err)
+	}
+	return resp.StatusCode, envelope
+}
+
+func TestControllerAPIRequiresToken(t *testing.T) {
+	srv, _ := newControllerTestServer(t, ControllerActions{})
+
+	resp := mustRequest(t, srv.URL+"/api/tasks")
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", resp.StatusCode)
+	}
+	var envelope apiTestEnvelope
+	if err := json.Unmarshal(readBody(t, resp), &envelope); err != nil {
+		t.Fatalf("unmarshal envelope: %v", err)
+	}
+	if envelope.Error == nil || envelope.Error.Code != "unauthorized" {
+		t.Fatalf("unexpected error: %+v", envelope.Error)
+	}
+
+	resp = mustRequest(t, srv.URL+"/api/tasks?token="+controllerTestToken)
+	readBody(t, resp)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("query token status = %d", resp.StatusCode)
+	}
+}
+
+func TestControllerAPITaskLifecycle(t *testing.T) {
+	var root string
+	srv, dir := newControllerTestServer(t, ControllerActions{
+		AssignTask: func(taskID, workerID, provider, model, reason string) error {
+			_, err := BindTaskToWorker(root, taskID, workerID, reason, time.Now().UTC())
+			return err
+		},
+	})
+	root = dir
+
+	status, envelope := controllerRequest(t, http.MethodPost, srv.URL+"/api/tasks", map[string]string{"title": "Add API", "role": "backend"})
+	if status != http.StatusCreated {
+		t.Fatalf("create status = %d (%+v)", status, envelope.Error)
+	}
+	var created struct {
+		Item taskDTO `json:"item"`
+	}
+	if err := json.Unmarshal(envelope.Data, &created); err != nil {
+		t.Fatalf("unmarshal created: %v", err)
+	}
+	taskID := created.Item.TaskID
+	if created.Item.Status != TaskStatusDraft {
+		t.Fatalf("status = %s, want draft", created.Item.Status)
+	}
+
+	status, envelope = controllerRequest(t, http.MethodPost, srv.URL+"/api/tasks/"+taskID+"/done", nil)
+	if status != http.StatusUnprocessableEntity || envelope.Error == nil {
+		t.Fatalf("done on draft status = %d, want 422", status)
+	}
+
+	status, envelope = controllerRequest(t, http.MethodPost, srv.URL+"/api/tasks/"+taskID+"/assign", map[string]string{"worker_id": "backend-001"})
+	if status != http.StatusOK {
+		t.Fatalf("assign status = %d (%+v)", status, envelope.Error)
+	}
+
+	status, envelope = controllerRequest(t, http.MethodPost, srv.URL+"/api/tasks/"+taskID+"/done", nil)
+	if status != http.StatusOK {
+		t.Fatalf("done status = %d (%+v)", status, envelope.Error)
+	}
+	var detail struct {
+		Item     taskDTO            `json:"item"`
+		Location TaskRecordLocation `json:"location"`
+	}
+	if err := json.Unmarshal(envelope.Data, &detail); err != nil {
+		t.Fatalf("unmarshal detail: %v", err)
+	}
+	if detail.Item.Status != TaskStatusVerifying || detail.Item.WorkerID != "backend-001" {
+		t.Fatalf("unexpected task after done: %+v", detail.Item)
+	}
+
+	status, envelope = controllerRequest(t, http.MethodGet, srv.URL+"/api/tasks", nil)
+	if status != http.StatusOK {
+		t.Fatalf("list status = %d", status)
+	}
+	var list struct {
+		Items []taskDTO `json:"items"`
+		Total int       `json:"total"`
+	}
+	if err := json.Unmarshal(envelope.Data, &list); err != nil {
+		t.Fatalf("unmarshal list: %v", err)
+	}
+	if list.Total != 1 || list.Items[0].TaskID != taskID {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+
+	status, envelope = controllerRequest(t, http.MethodGet, srv.URL+"/api/tasks/missing-task", nil)
+	if status != http.StatusNotFound || envelope.Error.Code != "not_found" {
+		t.Fatalf("missing task status = %d (%+v)", status, envelope.Error)
+	}
+}
+
+func TestControllerAPIWorkersAndReply(t *testing.T) {
+	var replied string
+	srv, root := newControllerTestServer(t, ControllerActions{
+		Reply: func(workerID, message string) error {
+			replied = workerID + ":" + message
+			return nil
+		},
+	})
+	for id, pane := range map[string]string{"backend-001": "42", "backend-002": "7"} {
+		wtPath := filepath.Join(root, ".worktrees", id)
+		if err := os.MkdirAll(wtPath, 0755); err != nil {
+			t.Fatalf("mkdir worktree: %v", err)
+		}
+		cfg := &WorkerConfig{WorkerID: id, Role: "backend", Provider: "claude", PaneID: pane, CreatedAt: "2026-03-21T10:00:00Z"}
+		if err := cfg.Save(WorkerYAMLPath(wtPath)); err != nil {
+			t.Fatalf("save worker: %v", err)
+		}
+	}
+
+	status, envelope := controllerRequest(t, http.MethodGet, srv.URL+"/api/workers", nil)
+	if status != http.StatusOK {
+		t.Fatalf("workers status = %d", status)
+	}
+	var list struct {
+		Items []workerDTO `json:"items"`
+	}
+	if err := json.Unmarshal(envelope.Data, &list); err != nil {
+		t.Fatalf("unmarshal workers: %v", err)
+	}
+	alive := map[string]bool{}
+	for _, item := range list.Items {
+		alive[item.WorkerID] = item.Alive
+	}
+	if len(alive) != 2 || !alive["backend-001"] || alive["backend-002"] {
+		t.Fatalf("unexpected liveness: %+v", alive)
+	}
+
+	status, envelope = controllerRequest(t, http.MethodPost, srv.URL+"/api/workers/backend-001/reply", map[string]string{"message": "use the v2 schema"})
+	if status != http.StatusOK {
+		t.Fatalf("reply status = %d (%+v)", status, envelope.Error)
+	}
+	if replied != "backend-001:use the v2 schema" {
+		t.Fatalf("reply not delivered: %q", replied)
+	}
+
+	status, _ = controllerRequest(t, http.MethodPost, srv.URL+"/api/workers/backend-001/reply", map[string]string{})
+	if status != http.StatusBadRequest {
+		t.Fatalf("empty reply status = %d, want 400", status)
+	}
+}
+
+func TestControllerAPIPlanningList(t *testing.T) {
+	srv, root := newControllerTestServer(t, ControllerActions{})
+	if _, err := CreatePlanningRecord(root, PlanningKindRoadmap, "Q3 Roadmap", time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("create planning: %v", err)
+	}
+
+	status, envelope := controllerRequest(t, http.MethodGet, srv.URL+"/api/planning?kind=roadmap", nil)
+	if status != http.StatusOK {
+		t.Fatalf("planning status = %d (%+v)", status, envelope.Error)
+	}
+	var list struct {
+		Items []planningDTO `json:"items"`
+		Total int           `json:"total"`
+	}
+	if err := json.Unmarshal(envelope.Data, &list); err != nil {
+		t.Fatalf("unmarshal planning: %v", err)
+	}
+	if list.Total != 1 || list.Items[0].Title != "Q3 Roadmap" {
+		t.Fatalf("unexpected planning list: %+v", list)
+	}
+
+	status, _ = controllerRequest(t, http.MethodGet, srv.URL+"/api/planning?kind=epic", nil)
+	if status != http.StatusBadRequest {
+		t.Fatalf("invalid kind status = %d, want 400", status)
+	}
+}
+
+func TestControllerAPIEventStream(t *testing.T) {
+	srv, root := newControllerTestServer(t, ControllerActions{})
+	EmitEvent(root, Event{Type: EventTaskCreated, TaskID: "old-task"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/events?token="+controllerTestToken+"&task=new-task", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type = %q", ct)
+	}
+
+	EmitEvent(root, Event{Type: EventTaskCreated, TaskID: "other-task"})
+	EmitEvent(root, Event{Type: EventTaskTransition, TaskID: "new-task", From: "draft", To: "assigned"})
+
+	scanner := bufio.NewScanner(resp.Body)
+	var lines []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data: ") {
+			lines = append(lines, line)
+			break
+		}
+		if strings.HasPrefix(line, "event: ") {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) != 2 || lines[0] != "event: task.transition" {
+		t.Fatalf("unexpected stream: %v", lines)
+	}
+	var evt Event
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &evt); err != nil {
+		t.Fatalf("unmarshal event: %v", err)
+	}
+	if evt.TaskID != "new-task" || evt.To != "assigned" {
+		t.Fatalf("unexpected event: %+v", evt)
+	}
+}
+
+func TestControllerAPIAllowsConfiguredOrigins(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(AgentTeamDir(root), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "version: 1\ntoken: " + controllerTestToken + "\nallowed_origins:\n  - http://localhost:5173/\n"
+	if err := os.WriteFile(ServeConfigPath(root), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadServeConfig(root)
+	if err != nil {
+		t.Fatalf("LoadServeConfig: %v", err)
+	}
+	srv := httptest.NewServer(NewControllerAPIHandler(ControllerAPIOptions{
+		Root:           root,
+		WtBase:         ".worktrees",
+		Token:          cfg.Token,
+		Session:        controllerTestSession{},
+		AllowedOrigins: cfg.AllowedOrigins,
+	}))
+	t.Cleanup(srv.Close)
+	do := func(method, origin, token string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+"/api/tasks", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			req.Header.Set("Access-Control-Request-Headers", "authorization")
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readBody(t, resp)
+		return resp
+	}
+
+	resp := do(http.MethodOptions, "http://localhost:5173", "")
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != "http://localhost:5173" ||
+		!strings.Contains(resp.Header.Get("Access-Control-Allow-Headers"), "Authorization") {
+		t.Fatalf("preflight: status=%d headers=%v", resp.StatusCode, resp.Header)
+	}
+	if resp := do(http.MethodGet, "http://localhost:5173", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("cross-origin request without token: status = %d, want 401", resp.StatusCode)
+	}
+	resp = do(http.MethodGet, "http://localhost:5173", controllerTestToken)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != "http://localhost:5173" {
+		t.Fatalf("cross-origin request: status=%d headers=%v", resp.StatusCode, resp.Header)
+	}
+
+	resp = do(http.MethodOptions, "http://evil.example", "")
+	if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("unlisted origin preflight: status=%d headers=%v", resp.StatusCode, resp.Header)
+	}
+}
+
+func TestControllerAPIRejectsEverythingWithoutToken(t *testing.T) {
+	srv := httptest.NewServer(NewControllerAPIHandler(ControllerAPIOptions{Root: t.TempDir()}))
+	defer srv.Close()
+
+	for _, url := range []string{srv.URL + "/api/tasks", srv.URL + "/api/tasks?token="} {
+		resp := mustRequest(t, url)
+		readBody(t, resp)
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("%s: status = %d, want 401", url, resp.StatusCode)
+		}
+	}
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api/tasks", strings.NewReader("title=x"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer ")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	readBody(t, resp)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("POST status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestEnsureServeConfigGeneratesTokenAndIgnoresFile(t *testing.T) {
+	root := t.TempDir()
+	cfg, err := EnsureServeConfig(root)
+	if err != nil {
+		t.Fatalf("EnsureServeConfig: %v", err)
+	}
+	if cfg.Addr != DefaultServeAddr || len(cfg.Token) != 48 {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	again, err := EnsureServeConfig(root)
+	if err != nil {
+		t.Fatalf("EnsureServeConfig again: %v", err)
+	}
+	if again.Token != cfg.Token {
+		t.Fatal("token should be stable across runs")
+	}
+	data, err := os.ReadFile(filepath.Join(AgentTeamDir(root), ".gitignore"))
+	if err != nil {
+		t.Fatalf("read .gitignore: %v", err)
+	}
+	if strings.Count(string(data), "serve.yaml") != 1 {
+		t.Fatalf("expected serve.yaml ignored once, got %q", data)
+	}
+}
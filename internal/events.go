@@ -0,0 +1,203 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// EventType names a state change recorded in events.jsonl.
+type EventType string
+
+const (
+	EventTaskCreated            EventType = "task.created"
+	EventTaskTransition         EventType = "task.transition"
+	EventPlanningCreated        EventType = "planning.created"
+	EventPlanningMoved          EventType = "planning.moved"
+	EventPlanningTransition     EventType = "planning.transition"
+	EventPlanningLinked         EventType = "planning.linked"
+	EventPlanningUnlinked       EventType = "planning.unlinked"
+	EventWorkerSpawned          EventType = "worker.spawned"
+	EventWorkerClosed           EventType = "worker.closed"
+	EventWorkerMerged           EventType = "worker.merged"
+	EventWorkerDeleted          EventType = "worker.deleted"
+	EventMessageSent            EventType = "message.sent"
+	EventWorkflowPlanTransition EventType = "workflow_plan.transition"
+	EventRoleInstalled          EventType = "role.installed"
+	EventRoleUpdated            EventType = "role.updated"
+	EventRoleRemoved            EventType = "role.removed"
+)
+
+// Event is one line of .agent-team/events.jsonl.
+type Event struct {
+	Type     EventType         `json:"type"`
+	Time     string            `json:"time"`
+	TaskID   string            `json:"task_id,omitempty"`
+	WorkerID string            `json:"worker_id,omitempty"`
+	Role     string            `json:"role,omitempty"`
+	Subject  string            `json:"subject,omitempty"`
+	From     string            `json:"from,omitempty"`
+	To       string            `json:"to,omitempty"`
+	Message  string            `json:"message,omitempty"`
+	Data     map[string]string `json:"data,omitempty"`
+}
+
+// EventsPath returns the path to .agent-team/events.jsonl.
+func EventsPath(root string) string {
+	return filepath.Join(AgentTeamDir(root), "events.jsonl")
+}
+
+// EventBus appends typed events to the project event log.
+type EventBus struct {
+	path  string
+	nowFn func() time.Time
+}
+
+// NewEventBus creates a bus writing to the project's events.jsonl.
+func NewEventBus(root string) *EventBus {
+	return &EventBus{path: EventsPath(root), nowFn: time.Now}
+}
+
+// Publish appends evt as a single JSON line. Writers are serialized through
+// the events.jsonl lock so concurrent processes never interleave lines.
+func (b *EventBus) Publish(evt Event) error {
+	if evt.Time == "" {
+		evt.Time = b.nowFn().UTC().Format(time.RFC3339Nano)
+	}
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	data = append(data, '\n')
+
+	unlock, err := LockState(b.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(b.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("open events.jsonl: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("append event: %w", err)
+	}
+	return nil
+}
+
+// EmitEvent publishes evt on the project bus. Event logging never blocks a
+// state change, so failures are reported as warnings.
+func EmitEvent(root string, evt Event) {
+	if err := NewEventBus(root).Publish(evt); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: record %s event: %v\n", evt.Type, err)
+	}
+}
+
+// EventFilter selects events when reading the log.
+type EventFilter struct {
+	Types  []EventType
+	TaskID string
+	Since  time.Time
+}
+
+// Match reports whether evt passes the filter.
+func (f EventFilter) Match(evt Event) bool {
+	if len(f.Types) > 0 {
+		matched := false
+		for _, t := range f.Types {
+			if evt.Type == t {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if f.TaskID != "" && evt.TaskID != f.TaskID {
+		return false
+	}
+	if !f.Since.IsZero() {
+		ts, err := time.Parse(time.RFC3339Nano, evt.Time)
+		if err != nil || ts.Before(f.Since) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadEvents returns all matching events and the byte offset the log was
+// read up to, which can be passed to FollowEvents.
+func ReadEvents(root string, filter EventFilter) ([]Event, int64, error) {
+	var events []Event
+	offset, err := readEventsFrom(EventsPath(root), 0, filter, func(evt Event) error {
+		events = append(events, evt)
+		return nil
+	})
+	return events, offset, err
+}
+
+// FollowEvents polls the log from offset and calls fn for each new matching
+// event until ctx is cancelled.
+func FollowEvents(ctx context.Context, root string, filter EventFilter, offset int64, interval time.Duration, fn func(Event) error) error {
+	path := EventsPath(root)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		next, err := readEventsFrom(path, offset, filter, fn)
+		if err != nil {
+			return err
+		}
+		offset = next
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// readEventsFrom streams complete lines starting at offset and returns the
+// offset just past the last complete line consumed.
+func readEventsFrom(path string, offset int64, filter EventFilter, fn func(Event) error) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return offset, nil
+		}
+		return offset, fmt.Errorf("open events.jsonl: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return offset, fmt.Errorf("seek events.jsonl: %w", err)
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// A trailing partial line is left for the next read.
+			return offset, nil
+		}
+		if err != nil {
+			return offset, fmt.Errorf("read events.jsonl: %w", err)
+		}
+		offset += int64(len(line))
+		var evt Event
+		if json.Unmarshal(line, &evt) != nil {
+			continue
+		}
+		if !filter.Match(evt) {
+			continue
+		}
+		if err := fn(evt); err != nil {
+			return offset, err
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTaskLifecycleEmitsEvents(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	record, err := CreateTaskPackage(root, "Evented Task", "backend", "", now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	if _, err := BindTaskToWorker(root, record.TaskID, "backend-001", "", now); err != nil {
+		t.Fatalf("BindTaskToWorker: %v", err)
+	}
+
+	events, _, err := ReadEvents(root, EventFilter{TaskID: record.TaskID})
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("events = %+v", events)
+	}
+	if events[0].Type != EventTaskCreated || events[1].Type != EventTaskTransition {
+		t.Fatalf("event types = %s, %s", events[0].Type, events[1].Type)
+	}
+	if events[1].From != string(TaskStatusDraft) || events[1].To != string(TaskStatusAssigned) || events[1].WorkerID != "backend-001" {
+		t.Fatalf("transition event = %+v", events[1])
+	}
+
+	transitions, _, err := ReadEvents(root, EventFilter{Types: []EventType{EventTaskTransition}})
+	if err != nil {
+		t.Fatalf("ReadEvents by type: %v", err)
+	}
+	if len(transitions) != 1 {
+		t.Fatalf("transitions = %+v", transitions)
+	}
+}
+
+func TestEventFilterSince(t *testing.T) {
+	since := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	filter := EventFilter{Since: since}
+	if filter.Match(Event{Time: since.Add(-time.Second).Format(time.RFC3339Nano)}) {
+		t.Fatal("expected event before since to be filtered")
+	}
+	if !filter.Match(Event{Time: since.Format(time.RFC3339Nano)}) {
+		t.Fatal("expected event at since to match")
+	}
+}
+
+func TestFollowEventsStreamsNewEvents(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(AgentTeamDir(root), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	bus := NewEventBus(root)
+	if err := bus.Publish(Event{Type: EventWorkerSpawned, WorkerID: "backend-001"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	_, offset, err := ReadEvents(root, EventFilter{})
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	got := make(chan Event, 1)
+	go func() {
+		_ = FollowEvents(ctx, root, EventFilter{}, offset, 10*time.Millisecond, func(evt Event) error {
+			got <- evt
+			return nil
+		})
+	}()
+	if err := bus.Publish(Event{Type: EventWorkerClosed, WorkerID: "backend-001"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	select {
+	case evt := <-got:
+		if evt.Type != EventWorkerClosed {
+			t.Fatalf("followed event = %+v", evt)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for followed event")
+	}
+	cancel()
+}
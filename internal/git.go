@@ -93,6 +93,17 @@ func (g *GitClient) Merge(branch, message string) error {
 	return nil
 }
 
+// HeadSHA returns the commit SHA checked out at the repository root.
+func (g *GitClient) HeadSHA() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = g.root
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("rev-parse HEAD: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 func (g *GitClient) RebaseWorktree(wtPath, onto string) error {
 	cmd := exec.Command("git", "-C", wtPath, "rebase", onto)
 	cmd.Dir = g.root
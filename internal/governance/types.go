@@ -62,14 +62,15 @@ func PassGateResult() GateResult {
 
 // WorkflowPlan is the governance-owned orchestration object.
 type WorkflowPlan struct {
-	ID        string    `yaml:"id"`
-	TaskID    string    `yaml:"task_id"`
-	Owner     string    `yaml:"owner"`
-	Status    string    `yaml:"status"`
-	InputRefs []string  `yaml:"input_refs,omitempty"`
-	Reasons   []string  `yaml:"reasons,omitempty"`
-	CreatedAt time.Time `yaml:"created_at"`
-	UpdatedAt time.Time `yaml:"updated_at"`
+	SchemaVersion int       `yaml:"schema_version"`
+	ID            string    `yaml:"id"`
+	TaskID        string    `yaml:"task_id"`
+	Owner         string    `yaml:"owner"`
+	Status        string    `yaml:"status"`
+	InputRefs     []string  `yaml:"input_refs,omitempty"`
+	Reasons       []string  `yaml:"reasons,omitempty"`
+	CreatedAt     time.Time `yaml:"created_at"`
+	UpdatedAt     time.Time `yaml:"updated_at"`
 }
 
 // Rule models a single text rule item.
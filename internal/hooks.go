@@ -0,0 +1,219 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HookEvent names a lifecycle transition that hooks can observe.
+type HookEvent string
+
+const (
+	HookEventTaskAssign    HookEvent = "task_assign"
+	HookEventTaskDone      HookEvent = "task_done"
+	HookEventTaskArchive   HookEvent = "task_archive"
+	HookEventTaskDeprecate HookEvent = "task_deprecate"
+	HookEventWorkerMerge   HookEvent = "worker_merge"
+	HookEventWorkerOpen    HookEvent = "worker_open"
+	HookEventWorkerClose   HookEvent = "worker_close"
+)
+
+// HookPhase identifies whether a hook runs before or after the transition.
+type HookPhase string
+
+const (
+	HookPhasePre  HookPhase = "pre"
+	HookPhasePost HookPhase = "post"
+)
+
+const defaultHookTimeout = 60 * time.Second
+
+// HookSpec is one user command bound to a lifecycle event.
+type HookSpec struct {
+	Command string `yaml:"command"`
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// HooksConfig is the structure of .agent-team/hooks.yaml. Keys in Hooks are
+// "<phase>_<event>", for example "pre_task_done" or "post_worker_open".
+type HooksConfig struct {
+	Hooks map[string][]HookSpec `yaml:"hooks"`
+}
+
+// HookPayload is the JSON document written to a hook's stdin.
+type HookPayload struct {
+	Event      HookEvent  `json:"event"`
+	Phase      HookPhase  `json:"phase"`
+	Root       string     `json:"root"`
+	TaskID     string     `json:"task_id,omitempty"`
+	WorkerID   string     `json:"worker_id,omitempty"`
+	Role       string     `json:"role,omitempty"`
+	FromStatus TaskStatus `json:"from_status,omitempty"`
+	ToStatus   TaskStatus `json:"to_status,omitempty"`
+	MergedSHA  string     `json:"merged_sha,omitempty"`
+	Timestamp  string     `json:"timestamp"`
+}
+
+// HooksConfigPath returns the path to .agent-team/hooks.yaml.
+func HooksConfigPath(root string) string {
+	return filepath.Join(AgentTeamDir(root), "hooks.yaml")
+}
+
+// LoadHooksConfig reads hooks.yaml. A missing file yields an empty config.
+func LoadHooksConfig(root string) (*HooksConfig, error) {
+	data, err := os.ReadFile(HooksConfigPath(root))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &HooksConfig{}, nil
+		}
+		return nil, fmt.Errorf("read hooks.yaml: %w", err)
+	}
+	var cfg HooksConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse hooks.yaml: %w", err)
+	}
+	for key, specs := range cfg.Hooks {
+		if _, _, err := parseHookKey(key); err != nil {
+			return nil, fmt.Errorf("parse hooks.yaml: %w", err)
+		}
+		for i, spec := range specs {
+			if strings.TrimSpace(spec.Command) == "" {
+				return nil, fmt.Errorf("parse hooks.yaml: %s[%d].command is required", key, i)
+			}
+			if spec.Timeout != "" {
+				if _, err := time.ParseDuration(spec.Timeout); err != nil {
+					return nil, fmt.Errorf("parse hooks.yaml: %s[%d].timeout: %w", key, i, err)
+				}
+			}
+		}
+	}
+	return &cfg, nil
+}
+
+func parseHookKey(key string) (HookPhase, HookEvent, error) {
+	phase, event, ok := strings.Cut(key, "_")
+	if !ok {
+		return "", "", fmt.Errorf("invalid hook key: %s", key)
+	}
+	switch HookPhase(phase) {
+	case HookPhasePre, HookPhasePost:
+	default:
+		return "", "", fmt.Errorf("invalid hook phase in %s (want pre_ or post_)", key)
+	}
+	switch HookEvent(event) {
+	case HookEventTaskAssign, HookEventTaskDone, HookEventTaskArchive, HookEventTaskDeprecate,
+		HookEventWorkerMerge, HookEventWorkerOpen, HookEventWorkerClose:
+	default:
+		return "", "", fmt.Errorf("unknown hook event in %s", key)
+	}
+	return HookPhase(phase), HookEvent(event), nil
+}
+
+// RunPreHooks runs the pre hooks for payload.Event. A non-zero exit from any
+// hook aborts and is returned so the caller can cancel the transition.
+func RunPreHooks(root string, payload HookPayload) error {
+	payload.Phase = HookPhasePre
+	return runHooks(root, payload)
+}
+
+// RunPostHooks runs the post hooks for payload.Event. The transition has
+// already happened, so failures are reported as warnings only.
+func RunPostHooks(root string, payload HookPayload) {
+	payload.Phase = HookPhasePost
+	if err := runHooks(root, payload); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+func runHooks(root string, payload HookPayload) error {
+	cfg, err := LoadHooksConfig(root)
+	if err != nil {
+		return err
+	}
+	key := string(payload.Phase) + "_" + string(payload.Event)
+	specs := cfg.Hooks[key]
+	if len(specs) == 0 {
+		return nil
+	}
+	payload.Root = root
+	if payload.Timestamp == "" {
+		payload.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal hook payload: %w", err)
+	}
+	for _, spec := range specs {
+		if err := runHookCommand(root, spec, payload, input); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", key, spec.Command, err)
+		}
+	}
+	return nil
+}
+
+func runHookCommand(root string, spec HookSpec, payload HookPayload, input []byte) error {
+	timeout := defaultHookTimeout
+	if spec.Timeout != "" {
+		if d, err := time.ParseDuration(spec.Timeout); err == nil {
+			timeout = d
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", spec.Command)
+	cmd.Dir = root
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"AGENT_TEAM_ROOT="+root,
+		"AGENT_TEAM_EVENT="+string(payload.Event),
+		"AGENT_TEAM_HOOK_PHASE="+string(payload.Phase),
+		"AGENT_TEAM_TASK_ID="+payload.TaskID,
+		"AGENT_TEAM_WORKER_ID="+payload.WorkerID,
+		"AGENT_TEAM_ROLE="+payload.Role,
+		"AGENT_TEAM_FROM_STATUS="+string(payload.FromStatus),
+		"AGENT_TEAM_TO_STATUS="+string(payload.ToStatus),
+	)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %s", timeout)
+		}
+		return err
+	}
+	return nil
+}
+
+// NewWorkerHookPayload builds the payload for a worker transition. cfg may be
+// nil when the worker config could not be loaded.
+func NewWorkerHookPayload(event HookEvent, workerID string, cfg *WorkerConfig) HookPayload {
+	payload := HookPayload{Event: event, WorkerID: workerID}
+	if cfg != nil {
+		payload.TaskID = cfg.TaskID
+		payload.Role = cfg.Role
+	}
+	return payload
+}
+
+// taskHookPayload builds the payload for a task transition.
+func taskHookPayload(event HookEvent, record *TaskRecord, from, to TaskStatus) HookPayload {
+	return HookPayload{
+		Event:      event,
+		TaskID:     record.TaskID,
+		WorkerID:   record.WorkerID,
+		Role:       record.Role,
+		FromStatus: from,
+		ToStatus:   to,
+		MergedSHA:  record.MergedSHA,
+	}
+}
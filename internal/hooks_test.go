@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeHooksConfig(t *testing.T, root, content string) {
+	t.Helper()
+	if err := os.MkdirAll(AgentTeamDir(root), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(HooksConfigPath(root), []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile hooks.yaml: %v", err)
+	}
+}
+
+func TestPreHookFailureAbortsTaskDone(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	record, err := CreateTaskPackage(root, "Hooked Task", "backend", "", now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	if _, err := BindTaskToWorker(root, record.TaskID, "backend-001", "", now); err != nil {
+		t.Fatalf("BindTaskToWorker: %v", err)
+	}
+	writeHooksConfig(t, root, "hooks:\n  pre_task_done:\n    - command: exit 3\n")
+
+	if _, err := MarkTaskDone(root, record.TaskID, "", now); err == nil || !strings.Contains(err.Error(), "pre_task_done") {
+		t.Fatalf("MarkTaskDone err = %v", err)
+	}
+	loaded, _, err := LoadTaskRecord(root, record.TaskID)
+	if err != nil {
+		t.Fatalf("LoadTaskRecord: %v", err)
+	}
+	if loaded.Status != TaskStatusAssigned {
+		t.Fatalf("Status = %s, want assigned", loaded.Status)
+	}
+}
+
+func TestPostHookReceivesPayloadAndEnv(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	record, err := CreateTaskPackage(root, "Hooked Task", "backend", "", now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	writeHooksConfig(t, root, "hooks:\n  post_task_assign:\n    - command: cat > payload.json && echo \"$AGENT_TEAM_TASK_ID $AGENT_TEAM_WORKER_ID\" > env.txt\n")
+
+	if _, err := BindTaskToWorker(root, record.TaskID, "backend-001", "", now); err != nil {
+		t.Fatalf("BindTaskToWorker: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "payload.json"))
+	if err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	var payload HookPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	if payload.Event != HookEventTaskAssign || payload.Phase != HookPhasePost || payload.FromStatus != TaskStatusDraft || payload.ToStatus != TaskStatusAssigned {
+		t.Fatalf("payload = %+v", payload)
+	}
+	env, err := os.ReadFile(filepath.Join(root, "env.txt"))
+	if err != nil {
+		t.Fatalf("read env: %v", err)
+	}
+	if strings.TrimSpace(string(env)) != record.TaskID+" backend-001" {
+		t.Fatalf("env = %q", string(env))
+	}
+}
+
+func TestLoadHooksConfigRejectsUnknownEvent(t *testing.T) {
+	root := t.TempDir()
+	writeHooksConfig(t, root, "hooks:\n  pre_task_explode:\n    - command: true\n")
+	if _, err := LoadHooksConfig(root); err == nil || !strings.Contains(err.Error(), "unknown hook event") {
+		t.Fatalf("err = %v", err)
+	}
+}
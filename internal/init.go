@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -27,7 +28,7 @@ func InitProject(root string) error {
 	if err := EnsurePlanningDirs(root); err != nil {
 		return err
 	}
-	return nil
+	return EnsureStateLocksIgnored(root)
 }
 
 
@@ -337,6 +338,9 @@ func InitProviderFiles(root string) error {
 	if err := InitClaudeLocalSettings(root); err != nil {
 		return err
 	}
+	if err := InitMCPServerSettings(root); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -379,6 +383,22 @@ func InitClaudeLocalSettings(root string) error {
 		cfg["hooks"] = hooksData
 	}
 
+	// Approve the project-scoped agent-team MCP server declared in .mcp.json.
+	var enabled []string
+	if raw, ok := cfg["enabledMcpjsonServers"]; ok && len(raw) > 0 {
+		if err := json.Unmarshal(raw, &enabled); err != nil {
+			return fmt.Errorf("parse enabledMcpjsonServers in %s: %w", settingsPath, err)
+		}
+	}
+	if !slices.Contains(enabled, MCPServerName) {
+		enabled = append(enabled, MCPServerName)
+	}
+	enabledData, err := json.Marshal(enabled)
+	if err != nil {
+		return fmt.Errorf("marshal enabledMcpjsonServers for %s: %w", settingsPath, err)
+	}
+	cfg["enabledMcpjsonServers"] = enabledData
+
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal %s: %w", settingsPath, err)
@@ -40,6 +40,32 @@ func TestInitProject(t *testing.T) {
 	}
 }
 
+func TestInitProjectIgnoresLocksInExistingGitignore(t *testing.T) {
+	dir := t.TempDir()
+	ignore := filepath.Join(dir, ".agent-team", ".gitignore")
+	if err := os.MkdirAll(filepath.Dir(ignore), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ignore, []byte(".cache/"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if StateLocksIgnored(dir) {
+		t.Fatal("lock files should not be ignored yet")
+	}
+	for i := 0; i < 2; i++ {
+		if err := InitProject(dir); err != nil {
+			t.Fatalf("InitProject: %v", err)
+		}
+	}
+	data, err := os.ReadFile(ignore)
+	if err != nil || string(data) != ".cache/\n*.lock\n" {
+		t.Fatalf(".gitignore = %q, %v", data, err)
+	}
+	if !StateLocksIgnored(dir) {
+		t.Fatal("lock files should be ignored")
+	}
+}
+
 func TestDetectProjectBuildScripts(t *testing.T) {
 	dir := t.TempDir()
 
@@ -0,0 +1,283 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// lineEdit is one step of a line diff: ' ' keeps, '-' deletes and '+'
+// inserts Line. Lines keep their trailing newline.
+type lineEdit struct {
+	Op   byte
+	Line string
+}
+
+// lineHunk replaces base[Start:End] with Lines.
+type lineHunk struct {
+	Start, End int
+	Lines      []string
+}
+
+// splitDiffLines splits data into lines, keeping each line's newline so a
+// missing final newline shows up as a change.
+func splitDiffLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns a minimal edit script from a to b using the classic LCS
+// table. Role files are small, so the quadratic cost is acceptable.
+func diffLines(a, b []string) []lineEdit {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	edits := make([]lineEdit, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, lineEdit{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, lineEdit{'-', a[i]})
+			i++
+		default:
+			edits = append(edits, lineEdit{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		edits = append(edits, lineEdit{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		edits = append(edits, lineEdit{'+', b[j]})
+	}
+	return edits
+}
+
+// diffHunks groups the changes between base and other into replacements of
+// base line ranges.
+func diffHunks(base, other []string) []lineHunk {
+	var hunks []lineHunk
+	var cur *lineHunk
+	pos := 0
+	for _, e := range diffLines(base, other) {
+		if e.Op == ' ' {
+			if cur != nil {
+				hunks = append(hunks, *cur)
+				cur = nil
+			}
+			pos++
+			continue
+		}
+		if cur == nil {
+			cur = &lineHunk{Start: pos, End: pos}
+		}
+		if e.Op == '-' {
+			pos++
+			cur.End = pos
+		} else {
+			cur.Lines = append(cur.Lines, e.Line)
+		}
+	}
+	if cur != nil {
+		hunks = append(hunks, *cur)
+	}
+	return hunks
+}
+
+// UnifiedDiff renders a unified diff from a to b with the given number of
+// context lines. It returns "" when the contents are equal.
+func UnifiedDiff(fromName, toName string, a, b []byte, context int) string {
+	edits := diffLines(splitDiffLines(a), splitDiffLines(b))
+	changed := false
+	for _, e := range edits {
+		if e.Op != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	// aLine/bLine are the 1-based line numbers each edit starts at.
+	aLine := make([]int, len(edits)+1)
+	bLine := make([]int, len(edits)+1)
+	aLine[0], bLine[0] = 1, 1
+	for i, e := range edits {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if e.Op != '+' {
+			aLine[i+1]++
+		}
+		if e.Op != '-' {
+			bLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		if edits[i].Op == ' ' {
+			i++
+			continue
+		}
+		start := max(i-context, 0)
+		end := i
+		// Extend the hunk while the next change is within 2*context lines.
+		for end < len(edits) {
+			if edits[end].Op != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(edits) && edits[next].Op == ' ' {
+				next++
+			}
+			if next == len(edits) || next-end > 2*context {
+				end = min(end+context, len(edits))
+				break
+			}
+			end = next
+		}
+		aCount, bCount := 0, 0
+		for _, e := range edits[start:end] {
+			if e.Op != '+' {
+				aCount++
+			}
+			if e.Op != '-' {
+				bCount++
+			}
+		}
+		aStart, bStart := aLine[start], bLine[start]
+		if aCount == 0 {
+			aStart--
+		}
+		if bCount == 0 {
+			bStart--
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+		for _, e := range edits[start:end] {
+			out.WriteByte(e.Op)
+			out.WriteString(e.Line)
+			if !strings.HasSuffix(e.Line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// MergeLines3 merges the changes local and remote made to base. Changes to
+// the same or touching base lines conflict unless both sides made the same
+// edit; conflicts are written with git-style markers and counted.
+func MergeLines3(base, local, remote []byte) ([]byte, int) {
+	baseLines := splitDiffLines(base)
+	lh := diffHunks(baseLines, splitDiffLines(local))
+	rh := diffHunks(baseLines, splitDiffLines(remote))
+
+	var out strings.Builder
+	conflicts := 0
+	pos, li, ri := 0, 0, 0
+	for li < len(lh) || ri < len(rh) {
+		start := len(baseLines) + 1
+		if li < len(lh) {
+			start = lh[li].Start
+		}
+		if ri < len(rh) && rh[ri].Start < start {
+			start = rh[ri].Start
+		}
+		for _, line := range baseLines[pos:start] {
+			out.WriteString(line)
+		}
+
+		end := start
+		var lg, rg []lineHunk
+		for grew := true; grew; {
+			grew = false
+			if li < len(lh) && lh[li].Start <= end {
+				lg = append(lg, lh[li])
+				end = max(end, lh[li].End)
+				li++
+				grew = true
+			}
+			if ri < len(rh) && rh[ri].Start <= end {
+				rg = append(rg, rh[ri])
+				end = max(end, rh[ri].End)
+				ri++
+				grew = true
+			}
+		}
+
+		switch {
+		case len(rg) == 0:
+			out.WriteString(applyLineHunks(baseLines, start, end, lg))
+		case len(lg) == 0:
+			out.WriteString(applyLineHunks(baseLines, start, end, rg))
+		default:
+			l := applyLineHunks(baseLines, start, end, lg)
+			r := applyLineHunks(baseLines, start, end, rg)
+			if l == r {
+				out.WriteString(l)
+				break
+			}
+			conflicts++
+			out.WriteString("<<<<<<< installed\n")
+			writeConflictSide(&out, l)
+			out.WriteString("=======\n")
+			writeConflictSide(&out, r)
+			out.WriteString(">>>>>>> remote\n")
+		}
+		pos = end
+	}
+	for _, line := range baseLines[pos:] {
+		out.WriteString(line)
+	}
+	return []byte(out.String()), conflicts
+}
+
+// applyLineHunks returns base[start:end] with hunks (all inside the range)
+// applied.
+func applyLineHunks(base []string, start, end int, hunks []lineHunk) string {
+	var b strings.Builder
+	pos := start
+	for _, h := range hunks {
+		for _, line := range base[pos:h.Start] {
+			b.WriteString(line)
+		}
+		for _, line := range h.Lines {
+			b.WriteString(line)
+		}
+		pos = h.End
+	}
+	for _, line := range base[pos:end] {
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+func writeConflictSide(out *strings.Builder, text string) {
+	out.WriteString(text)
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		out.WriteByte('\n')
+	}
+}
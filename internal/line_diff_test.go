@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := []byte("one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n")
+	b := []byte("one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n")
+	got := UnifiedDiff("installed/role.yaml", "remote/role.yaml", a, b, 1)
+	want := "--- installed/role.yaml\n+++ remote/role.yaml\n" +
+		"@@ -2,3 +2,3 @@\n two\n-three\n+THREE\n four\n" +
+		"@@ -10,1 +10,2 @@\n ten\n+eleven\n"
+	if got != want {
+		t.Fatalf("UnifiedDiff =\n%s\nwant\n%s", got, want)
+	}
+	if UnifiedDiff("a", "b", a, a, 3) != "" {
+		t.Fatal("equal inputs should produce no diff")
+	}
+	if got := UnifiedDiff("a", "b", nil, []byte("new"), 3); !strings.Contains(got, "@@ -0,0 +1,1 @@\n+new\n\\ No newline at end of file\n") {
+		t.Fatalf("unexpected diff for new file:\n%s", got)
+	}
+}
+
+func TestMergeLines3(t *testing.T) {
+	base := []byte("name: frontend\ndescription: ui\nskills:\n  - react\n")
+
+	local := []byte("name: frontend\ndescription: ui work\nskills:\n  - react\n")
+	remote := []byte("name: frontend\ndescription: ui\nskills:\n  - react\n  - vite\n")
+	merged, conflicts := MergeLines3(base, local, remote)
+	if conflicts != 0 || string(merged) != "name: frontend\ndescription: ui work\nskills:\n  - react\n  - vite\n" {
+		t.Fatalf("clean merge = %q (%d conflicts)", merged, conflicts)
+	}
+
+	same := []byte("name: frontend\ndescription: shared\nskills:\n  - react\n")
+	if merged, conflicts := MergeLines3(base, same, same); conflicts != 0 || string(merged) != string(same) {
+		t.Fatalf("identical edits = %q (%d conflicts)", merged, conflicts)
+	}
+
+	theirs := []byte("name: frontend\ndescription: web\nskills:\n  - react\n")
+	merged, conflicts = MergeLines3(base, local, theirs)
+	if conflicts != 1 || !strings.Contains(string(merged), "<<<<<<< installed\ndescription: ui work\n=======\ndescription: web\n>>>>>>> remote\n") {
+		t.Fatalf("conflicting merge = %q (%d conflicts)", merged, conflicts)
+	}
+}
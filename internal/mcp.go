@@ -0,0 +1,370 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MCPProtocolVersion is the Model Context Protocol revision this server speaks.
+const MCPProtocolVersion = "2024-11-05"
+
+// JSON-RPC 2.0 error codes used by the MCP server.
+const (
+	mcpErrParse          = -32700
+	mcpErrInvalidRequest = -32600
+	mcpErrMethodNotFound = -32601
+	mcpErrInvalidParams  = -32602
+)
+
+// MCPTool is a callable tool. Handler receives the raw "arguments" object and
+// returns the text shown to the agent; a returned error is reported as a tool
+// result with isError set rather than as a protocol error.
+type MCPTool struct {
+	Name        string
+	Description string
+	InputSchema map[string]any
+	Handler     func(args json.RawMessage) (string, error)
+}
+
+// MCPResource is a readable resource with a fixed URI.
+type MCPResource struct {
+	URI         string
+	Name        string
+	Description string
+	MimeType    string
+	Read        func() (string, error)
+}
+
+// MCPResourceTemplate is a family of resources addressed by a URI template.
+// Resolve returns a reader for uri, or false when the uri does not match.
+type MCPResourceTemplate struct {
+	URITemplate string
+	Name        string
+	Description string
+	MimeType    string
+	Resolve     func(uri string) (func() (string, error), bool)
+}
+
+// MCPServer serves tools and resources over newline-delimited JSON-RPC on stdio.
+type MCPServer struct {
+	name      string
+	version   string
+	tools     []MCPTool
+	resources []MCPResource
+	templates []MCPResourceTemplate
+}
+
+// NewMCPServer creates an empty server identified by name and version.
+func NewMCPServer(name, version string) *MCPServer {
+	return &MCPServer{name: name, version: version}
+}
+
+// AddTool registers a tool.
+func (s *MCPServer) AddTool(tool MCPTool) {
+	s.tools = append(s.tools, tool)
+}
+
+// AddResource registers a fixed resource.
+func (s *MCPServer) AddResource(resource MCPResource) {
+	s.resources = append(s.resources, resource)
+}
+
+// AddResourceTemplate registers a templated resource family.
+func (s *MCPServer) AddResourceTemplate(tmpl MCPResourceTemplate) {
+	s.templates = append(s.templates, tmpl)
+}
+
+type mcpRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type mcpResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *mcpError       `json:"error,omitempty"`
+}
+
+type mcpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+type mcpResourceContent struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// Serve reads requests from in and writes responses to out until in is
+// exhausted or ctx is cancelled. Requests are handled one at a time.
+func (s *MCPServer) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	encoder := json.NewEncoder(out)
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		line, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			if resp := s.handleLine(line); resp != nil {
+				if encErr := encoder.Encode(resp); encErr != nil {
+					return fmt.Errorf("write mcp response: %w", encErr)
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read mcp request: %w", err)
+		}
+	}
+}
+
+// handleLine returns nil for notifications, which never get a response.
+func (s *MCPServer) handleLine(line []byte) *mcpResponse {
+	var req mcpRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &mcpResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &mcpError{Code: mcpErrParse, Message: err.Error()}}
+	}
+	if len(req.ID) == 0 {
+		return nil
+	}
+	resp := &mcpResponse{JSONRPC: "2.0", ID: req.ID}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &mcpError{Code: mcpErrInvalidRequest, Message: "invalid JSON-RPC 2.0 request"}
+		return resp
+	}
+	result, rpcErr := s.dispatch(req.Method, req.Params)
+	if rpcErr != nil {
+		resp.Error = rpcErr
+		return resp
+	}
+	resp.Result = result
+	return resp
+}
+
+func (s *MCPServer) dispatch(method string, params json.RawMessage) (any, *mcpError) {
+	switch method {
+	case "initialize":
+		return map[string]any{
+			"protocolVersion": MCPProtocolVersion,
+			"capabilities": map[string]any{
+				"tools":     map[string]any{},
+				"resources": map[string]any{},
+			},
+			"serverInfo": map[string]string{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		tools := make([]map[string]any, 0, len(s.tools))
+		for _, tool := range s.tools {
+			tools = append(tools, map[string]any{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"inputSchema": tool.InputSchema,
+			})
+		}
+		return map[string]any{"tools": tools}, nil
+	case "tools/call":
+		return s.callTool(params)
+	case "resources/list":
+		resources := make([]map[string]string, 0, len(s.resources))
+		for _, res := range s.resources {
+			resources = append(resources, map[string]string{
+				"uri":         res.URI,
+				"name":        res.Name,
+				"description": res.Description,
+				"mimeType":    res.MimeType,
+			})
+		}
+		return map[string]any{"resources": resources}, nil
+	case "resources/templates/list":
+		templates := make([]map[string]string, 0, len(s.templates))
+		for _, tmpl := range s.templates {
+			templates = append(templates, map[string]string{
+				"uriTemplate": tmpl.URITemplate,
+				"name":        tmpl.Name,
+				"description": tmpl.Description,
+				"mimeType":    tmpl.MimeType,
+			})
+		}
+		return map[string]any{"resourceTemplates": templates}, nil
+	case "resources/read":
+		return s.readResource(params)
+	default:
+		return nil, &mcpError{Code: mcpErrMethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+func (s *MCPServer) callTool(params json.RawMessage) (any, *mcpError) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, &mcpError{Code: mcpErrInvalidParams, Message: err.Error()}
+	}
+	for _, tool := range s.tools {
+		if tool.Name != call.Name {
+			continue
+		}
+		args := call.Arguments
+		if len(args) == 0 {
+			args = json.RawMessage("{}")
+		}
+		text, err := tool.Handler(args)
+		if err != nil {
+			if text != "" {
+				text += "\n"
+			}
+			return mcpToolResult{Content: []mcpContent{{Type: "text", Text: text + "Error: " + err.Error()}}, IsError: true}, nil
+		}
+		return mcpToolResult{Content: []mcpContent{{Type: "text", Text: text}}}, nil
+	}
+	return nil, &mcpError{Code: mcpErrInvalidParams, Message: "unknown tool: " + call.Name}
+}
+
+func (s *MCPServer) readResource(params json.RawMessage) (any, *mcpError) {
+	var req struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, &mcpError{Code: mcpErrInvalidParams, Message: err.Error()}
+	}
+	read, mimeType, ok := s.resolveResource(req.URI)
+	if !ok {
+		return nil, &mcpError{Code: mcpErrInvalidParams, Message: "unknown resource: " + req.URI}
+	}
+	text, err := read()
+	if err != nil {
+		return nil, &mcpError{Code: mcpErrInvalidParams, Message: err.Error()}
+	}
+	return map[string]any{"contents": []mcpResourceContent{{URI: req.URI, MimeType: mimeType, Text: text}}}, nil
+}
+
+func (s *MCPServer) resolveResource(uri string) (func() (string, error), string, bool) {
+	for _, res := range s.resources {
+		if res.URI == uri {
+			return res.Read, res.MimeType, true
+		}
+	}
+	for _, tmpl := range s.templates {
+		if read, ok := tmpl.Resolve(uri); ok {
+			return read, tmpl.MimeType, true
+		}
+	}
+	return nil, "", false
+}
+
+// DecodeMCPArgs unmarshals tool arguments into v and rejects unknown fields.
+func DecodeMCPArgs(args json.RawMessage, v any) error {
+	decoder := json.NewDecoder(strings.NewReader(string(args)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// MCPObjectSchema builds a JSON Schema object with string properties. Each
+// entry in props maps a property name to its description.
+func MCPObjectSchema(props map[string]string, required ...string) map[string]any {
+	properties := make(map[string]any, len(props))
+	for name, desc := range props {
+		properties[name] = map[string]string{"type": "string", "description": desc}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+const mcpURIPrefix = "agent-team://"
+
+// AddProjectMCPResources registers the rules index and per-task context and
+// verification documents for the project at root.
+func AddProjectMCPResources(s *MCPServer, root string) {
+	s.AddResource(MCPResource{
+		URI:         mcpURIPrefix + "rules/index",
+		Name:        "rules-index",
+		Description: "Project rules entry point (.agent-team/rules/index.md)",
+		MimeType:    "text/markdown",
+		Read:        func() (string, error) { return readMCPFile(RulesIndexPath(root)) },
+	})
+	for _, doc := range []string{"context", "verification"} {
+		doc := doc
+		s.AddResourceTemplate(MCPResourceTemplate{
+			URITemplate: mcpURIPrefix + "task/{task_id}/" + doc,
+			Name:        "task-" + doc,
+			Description: "A task package's " + doc + ".md",
+			MimeType:    "text/markdown",
+			Resolve: func(uri string) (func() (string, error), bool) {
+				taskID, ok := parseTaskResourceURI(uri, doc)
+				if !ok {
+					return nil, false
+				}
+				return func() (string, error) {
+					_, location, err := LoadTaskRecord(root, taskID)
+					if err != nil {
+						return "", err
+					}
+					return readMCPFile(taskDocPath(root, taskID, location, doc+".md"))
+				}, true
+			},
+		})
+	}
+}
+
+func parseTaskResourceURI(uri, doc string) (string, bool) {
+	rest, ok := strings.CutPrefix(uri, mcpURIPrefix+"task/")
+	if !ok {
+		return "", false
+	}
+	taskID, ok := strings.CutSuffix(rest, "/"+doc)
+	if !ok || taskID == "" || strings.Contains(taskID, "/") || taskID == "." || taskID == ".." {
+		return "", false
+	}
+	return taskID, true
+}
+
+func taskDocPath(root, taskID string, location TaskRecordLocation, name string) string {
+	switch location {
+	case TaskRecordLocationArchived:
+		return filepath.Join(TaskArchiveDir(root, taskID), name)
+	case TaskRecordLocationDeprecated:
+		return filepath.Join(TaskDeprecatedDir(root, taskID), name)
+	default:
+		return filepath.Join(TaskDir(root, taskID), name)
+	}
+}
+
+func readMCPFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+	return string(data), nil
+}
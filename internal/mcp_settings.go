@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MCPServerName is the key agent-team registers itself under in provider settings.
+const MCPServerName = "agent-team"
+
+// mcpServerCommand is the command providers launch to reach the MCP server.
+var mcpServerCommand = []string{"agent-team", "mcp"}
+
+// InitMCPServerSettings registers the agent-team MCP server for every
+// supported provider, preserving unrelated settings in each file:
+//   - Claude:   .mcp.json (mcpServers), enabled via .claude/settings.local.json
+//   - Gemini:   .gemini/settings.json (mcpServers)
+//   - OpenCode: opencode.json (mcp)
+//   - Codex:    .codex/config.toml ([mcp_servers.agent-team])
+func InitMCPServerSettings(root string) error {
+	stdio := map[string]any{"command": mcpServerCommand[0], "args": mcpServerCommand[1:]}
+	if err := upsertJSONServerEntry(filepath.Join(root, ".mcp.json"), "mcpServers", stdio); err != nil {
+		return err
+	}
+	if err := upsertJSONServerEntry(filepath.Join(root, ".gemini", "settings.json"), "mcpServers", stdio); err != nil {
+		return err
+	}
+	opencode := map[string]any{"type": "local", "command": mcpServerCommand, "enabled": true}
+	if err := upsertJSONServerEntry(filepath.Join(root, "opencode.json"), "mcp", opencode); err != nil {
+		return err
+	}
+	return ensureCodexMCPServer(filepath.Join(root, ".codex", "config.toml"))
+}
+
+// upsertJSONServerEntry sets settings[section][MCPServerName] = entry while
+// keeping all other keys of the JSON settings file intact.
+func upsertJSONServerEntry(path, section string, entry any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create %s directory: %w", filepath.Dir(path), err)
+	}
+	cfg := ClaudeSettings{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+
+	servers := map[string]json.RawMessage{}
+	if raw, ok := cfg[section]; ok && len(raw) > 0 {
+		if err := json.Unmarshal(raw, &servers); err != nil {
+			return fmt.Errorf("parse %s in %s: %w", section, path, err)
+		}
+	}
+	entryData, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal %s entry: %w", section, err)
+	}
+	servers[MCPServerName] = entryData
+	serversData, err := json.Marshal(servers)
+	if err != nil {
+		return fmt.Errorf("marshal %s for %s: %w", section, path, err)
+	}
+	cfg[section] = serversData
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", path, err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ensureCodexMCPServer appends the server table to Codex's TOML config unless
+// one is already present; existing user edits to the table are left alone.
+func ensureCodexMCPServer(path string) error {
+	header := "[mcp_servers." + MCPServerName + "]"
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == header {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create %s directory: %w", filepath.Dir(path), err)
+	}
+
+	content := string(data)
+	if content != "" {
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += "\n"
+	}
+	quoted := make([]string, 0, len(mcpServerCommand)-1)
+	for _, arg := range mcpServerCommand[1:] {
+		quoted = append(quoted, fmt.Sprintf("%q", arg))
+	}
+	content += fmt.Sprintf("%s\ncommand = %q\nargs = [%s]\n", header, mcpServerCommand[0], strings.Join(quoted, ", "))
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
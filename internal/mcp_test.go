@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func serveMCPLines(t *testing.T, s *MCPServer, lines ...string) []map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	if err := s.Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	var responses []map[string]any
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var resp map[string]any
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestMCPServerProtocol(t *testing.T) {
+	s := NewMCPServer("agent-team", "test")
+	s.AddTool(MCPTool{
+		Name:        "echo",
+		InputSchema: MCPObjectSchema(map[string]string{"text": "Text to echo"}, "text"),
+		Handler: func(raw json.RawMessage) (string, error) {
+			var args struct{ Text string }
+			if err := DecodeMCPArgs(raw, &args); err != nil {
+				return "", err
+			}
+			if args.Text == "fail" {
+				return "partial", errors.New("boom")
+			}
+			return args.Text, nil
+		},
+	})
+
+	responses := serveMCPLines(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{"text":"fail"}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"echo","arguments":{"bogus":1}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"nope"}`,
+		`not json`,
+	)
+	if len(responses) != 7 {
+		t.Fatalf("responses = %d, want 7", len(responses))
+	}
+
+	initResult := responses[0]["result"].(map[string]any)
+	if initResult["protocolVersion"] != MCPProtocolVersion {
+		t.Fatalf("protocolVersion = %v", initResult["protocolVersion"])
+	}
+	tools := responses[1]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 1 || tools[0].(map[string]any)["name"] != "echo" {
+		t.Fatalf("unexpected tools/list: %v", tools)
+	}
+
+	toolText := func(resp map[string]any) (string, bool) {
+		result := resp["result"].(map[string]any)
+		text := result["content"].([]any)[0].(map[string]any)["text"].(string)
+		isError, _ := result["isError"].(bool)
+		return text, isError
+	}
+	if text, isError := toolText(responses[2]); text != "hi" || isError {
+		t.Fatalf("echo = %q isError=%v", text, isError)
+	}
+	if text, isError := toolText(responses[3]); !isError || text != "partial\nError: boom" {
+		t.Fatalf("failing tool = %q isError=%v", text, isError)
+	}
+	if _, isError := toolText(responses[4]); !isError {
+		t.Fatal("unknown argument should be reported as a tool error")
+	}
+	if code := responses[5]["error"].(map[string]any)["code"].(float64); code != mcpErrMethodNotFound {
+		t.Fatalf("unknown method code = %v", code)
+	}
+	if code := responses[6]["error"].(map[string]any)["code"].(float64); code != mcpErrParse {
+		t.Fatalf("parse error code = %v", code)
+	}
+}
+
+func TestMCPProjectResources(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(RulesRootDir(root), 0755); err != nil {
+		t.Fatalf("mkdir rules: %v", err)
+	}
+	if err := os.WriteFile(RulesIndexPath(root), []byte("# Rules Index\n"), 0644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+	record, err := CreateTaskPackage(root, "Resource task", "backend", "", time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+
+	s := NewMCPServer("agent-team", "test")
+	AddProjectMCPResources(s, root)
+	responses := serveMCPLines(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"agent-team://rules/index"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"agent-team://task/`+record.TaskID+`/context"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"agent-team://task/../context"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"resources/templates/list"}`,
+	)
+
+	readText := func(resp map[string]any) string {
+		contents := resp["result"].(map[string]any)["contents"].([]any)
+		return contents[0].(map[string]any)["text"].(string)
+	}
+	if got := readText(responses[0]); got != "# Rules Index\n" {
+		t.Fatalf("rules index = %q", got)
+	}
+	want, err := os.ReadFile(filepath.Join(TaskDir(root, record.TaskID), "context.md"))
+	if err != nil {
+		t.Fatalf("read context.md: %v", err)
+	}
+	if got := readText(responses[1]); got != string(want) {
+		t.Fatalf("context = %q", got)
+	}
+	if responses[2]["error"] == nil {
+		t.Fatal("path traversal uri should be rejected")
+	}
+	templates := responses[3]["result"].(map[string]any)["resourceTemplates"].([]any)
+	if len(templates) != 2 {
+		t.Fatalf("templates = %d, want 2", len(templates))
+	}
+}
+
+func TestInitMCPServerSettingsPreservesExistingConfig(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".gemini"), 0755); err != nil {
+		t.Fatalf("mkdir .gemini: %v", err)
+	}
+	existing := `{"theme":"dark","mcpServers":{"other":{"command":"other-mcp"}}}`
+	if err := os.WriteFile(filepath.Join(root, ".gemini", "settings.json"), []byte(existing), 0644); err != nil {
+		t.Fatalf("write gemini settings: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := InitMCPServerSettings(root); err != nil {
+			t.Fatalf("InitMCPServerSettings run %d: %v", i+1, err)
+		}
+	}
+
+	var gemini struct {
+		Theme      string                    `json:"theme"`
+		MCPServers map[string]map[string]any `json:"mcpServers"`
+	}
+	data, err := os.ReadFile(filepath.Join(root, ".gemini", "settings.json"))
+	if err != nil {
+		t.Fatalf("read gemini settings: %v", err)
+	}
+	if err := json.Unmarshal(data, &gemini); err != nil {
+		t.Fatalf("parse gemini settings: %v", err)
+	}
+	if gemini.Theme != "dark" || gemini.MCPServers["other"] == nil || gemini.MCPServers[MCPServerName]["command"] != "agent-team" {
+		t.Fatalf("unexpected gemini settings: %s", data)
+	}
+
+	for _, rel := range []string{".mcp.json", "opencode.json"} {
+		data, err := os.ReadFile(filepath.Join(root, rel))
+		if err != nil {
+			t.Fatalf("read %s: %v", rel, err)
+		}
+		if !strings.Contains(string(data), `"agent-team"`) {
+			t.Fatalf("%s missing agent-team server: %s", rel, data)
+		}
+	}
+
+	codex, err := os.ReadFile(filepath.Join(root, ".codex", "config.toml"))
+	if err != nil {
+		t.Fatalf("read codex config: %v", err)
+	}
+	if strings.Count(string(codex), "[mcp_servers.agent-team]") != 1 || !strings.Contains(string(codex), `args = ["mcp"]`) {
+		t.Fatalf("unexpected codex config: %s", codex)
+	}
+}
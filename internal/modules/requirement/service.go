@@ -31,33 +31,24 @@ func (s Service) LoadGovernanceIndex() (governance.Index, error) {
 }
 
 func (s Service) EnsureIndexEntry(entry governance.IndexEntry) error {
-	idx, err := internal.LoadRequirementIndex(s.Root)
-	if err != nil {
-		return err
-	}
-
 	status := internal.RequirementStatusOpen
 	if entry.Archived {
 		status = internal.RequirementStatusDone
 	}
 
-	found := false
-	for i := range idx.Requirements {
-		if idx.Requirements[i].Name == entry.ID {
-			idx.Requirements[i].Status = status
-			found = true
-			break
+	return internal.UpdateRequirementIndex(s.Root, func(idx *internal.RequirementIndex) error {
+		for i := range idx.Requirements {
+			if idx.Requirements[i].Name == entry.ID {
+				idx.Requirements[i].Status = status
+				return nil
+			}
 		}
-	}
-
-	if !found {
 		idx.Requirements = append(idx.Requirements, internal.RequirementIndexEntry{
 			Name:         entry.ID,
 			Status:       status,
 			SubTaskCount: 0,
 			DoneCount:    0,
 		})
-	}
-
-	return internal.SaveRequirementIndex(s.Root, idx)
+		return nil
+	})
 }
@@ -26,11 +26,12 @@ func (s Service) SaveWorkflowPlan(plan *governance.WorkflowPlan) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("create workflow plan directory: %w", err)
 	}
+	plan.SchemaVersion = internal.CurrentSchemaVersion(internal.SchemaKindWorkflowPlan)
 	data, err := yaml.Marshal(plan)
 	if err != nil {
 		return fmt.Errorf("marshal workflow plan: %w", err)
 	}
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := internal.WriteFileLocked(path, data, 0644); err != nil {
 		return fmt.Errorf("write workflow plan: %w", err)
 	}
 	return nil
@@ -42,8 +43,12 @@ func (s Service) LoadWorkflowPlan(planID string) (*governance.WorkflowPlan, erro
 	if err != nil {
 		return nil, fmt.Errorf("read workflow plan: %w", err)
 	}
+	result, err := internal.MigrateSchema(internal.SchemaKindWorkflowPlan, data)
+	if err != nil {
+		return nil, fmt.Errorf("parse workflow plan: %w", err)
+	}
 	var plan governance.WorkflowPlan
-	if err := yaml.Unmarshal(data, &plan); err != nil {
+	if err := yaml.Unmarshal(result.Data, &plan); err != nil {
 		return nil, fmt.Errorf("parse workflow plan: %w", err)
 	}
 	return &plan, nil
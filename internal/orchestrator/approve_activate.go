@@ -33,12 +33,14 @@ func (u *Usecases) ApproveWorkflowPlan(input ApproveWorkflowPlanInput) (*governa
 		return nil, err
 	}
 
+	from := plan.Status
 	if err := governance.ApproveWorkflowPlan(plan, input.Actor, input.Now); err != nil {
 		return nil, err
 	}
 	if err := u.Workflow.SaveWorkflowPlan(plan); err != nil {
 		return nil, fmt.Errorf("save workflow plan: %w", err)
 	}
+	u.emitWorkflowPlanTransition(plan, from)
 	return plan, nil
 }
 
@@ -67,12 +69,14 @@ func (u *Usecases) ActivateWorkflowPlan(input ActivateWorkflowPlanInput) (*gover
 		return nil, err
 	}
 
+	from := plan.Status
 	if err := governance.ActivateWorkflowPlan(plan, input.Now); err != nil {
 		return nil, err
 	}
 	if err := u.Workflow.SaveWorkflowPlan(plan); err != nil {
 		return nil, fmt.Errorf("save workflow plan: %w", err)
 	}
+	u.emitWorkflowPlanTransition(plan, from)
 	return plan, nil
 }
 
@@ -87,11 +91,13 @@ func (u *Usecases) CloseWorkflowPlan(input CloseWorkflowPlanInput) (*governance.
 		return nil, fmt.Errorf("load workflow plan: %w", err)
 	}
 
+	from := plan.Status
 	if err := governance.CloseWorkflowPlan(plan, input.Now); err != nil {
 		return nil, err
 	}
 	if err := u.Workflow.SaveWorkflowPlan(plan); err != nil {
 		return nil, fmt.Errorf("save workflow plan: %w", err)
 	}
+	u.emitWorkflowPlanTransition(plan, from)
 	return plan, nil
 }
@@ -60,5 +60,6 @@ func (u *Usecases) GenerateWorkflowPlan(input GenerateWorkflowPlanInput) (*gover
 	if err := u.Workflow.SaveWorkflowPlan(plan); err != nil {
 		return nil, fmt.Errorf("save workflow plan: %w", err)
 	}
+	u.emitWorkflowPlanTransition(plan, "")
 	return plan, nil
 }
@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"fmt"
 
+	"github.com/JsonLee12138/agent-team/internal"
 	"github.com/JsonLee12138/agent-team/internal/governance"
 	requirementmodule "github.com/JsonLee12138/agent-team/internal/modules/requirement"
 	workflowmodule "github.com/JsonLee12138/agent-team/internal/modules/workflow"
@@ -55,3 +56,14 @@ func gateResultError(result governance.GateResult) error {
 	}
 	return fmt.Errorf("gate blocked: code=%s message=%s", result.Code, result.Message)
 }
+
+func (u *Usecases) emitWorkflowPlanTransition(plan *governance.WorkflowPlan, from string) {
+	internal.EmitEvent(u.Root, internal.Event{
+		Type:    internal.EventWorkflowPlanTransition,
+		TaskID:  plan.TaskID,
+		Subject: plan.ID,
+		From:    from,
+		To:      plan.Status,
+		Data:    map[string]string{"owner": plan.Owner},
+	})
+}
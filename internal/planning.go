@@ -22,10 +22,11 @@ const (
 
 // PlanningRecord is the structured record stored in roadmap/milestone/phase yaml files.
 type PlanningRecord struct {
+	SchemaVersion    int               `yaml:"schema_version"`
 	ID               string            `yaml:"id"`
 	Kind             PlanningKind      `yaml:"kind"`
 	Title            string            `yaml:"title"`
-	Status           string            `yaml:"status,omitempty"`
+	Status           PlanningStatus    `yaml:"status,omitempty"`
 	Goal             string            `yaml:"goal,omitempty"`
 	Lifecycle        PlanningLifecycle `yaml:"lifecycle"`
 	Path             string            `yaml:"path"`
@@ -39,6 +40,7 @@ type PlanningRecord struct {
 	DeprecatedAt     string            `yaml:"deprecated_at,omitempty"`
 	DeprecatedReason string            `yaml:"deprecated_reason,omitempty"`
 	ReplacedBy       string            `yaml:"replaced_by,omitempty"`
+	Revision         int               `yaml:"revision,omitempty"`
 }
 
 func ValidPlanningKind(kind PlanningKind) bool {
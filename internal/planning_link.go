@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// PlanningLink describes a resolved parent → child reference. Exactly one of
+// Child or Task is set.
+type PlanningLink struct {
+	Parent *PlanningRecord
+	Child  *PlanningRecord
+	Task   *TaskRecord
+}
+
+// ChildID returns the ID of the linked planning record or task.
+func (l *PlanningLink) ChildID() string {
+	if l.Task != nil {
+		return l.Task.TaskID
+	}
+	return l.Child.ID
+}
+
+// ChildKind returns the child's planning kind, or "task".
+func (l *PlanningLink) ChildKind() string {
+	if l.Task != nil {
+		return "task"
+	}
+	return string(l.Child.Kind)
+}
+
+// LinkPlanning attaches childID (a planning record or task) to parentID and
+// records the back-reference on the child: the parent's kind list on a
+// planning child, parent_ids on a task. Linking is idempotent.
+func LinkPlanning(root, parentID, childID string, now time.Time) (*PlanningLink, error) {
+	link, err := resolvePlanningLink(root, parentID, childID)
+	if err != nil {
+		return nil, err
+	}
+	down, up := link.refLists()
+	*down = appendUnique(*down, childID)
+	*up = appendUnique(*up, parentID)
+	if err := link.save(root, now); err != nil {
+		return nil, err
+	}
+	EmitEvent(root, Event{Type: EventPlanningLinked, Subject: parentID, To: childID, TaskID: link.taskID()})
+	return link, nil
+}
+
+// UnlinkPlanning removes the reference between parentID and childID on both sides.
+func UnlinkPlanning(root, parentID, childID string, now time.Time) (*PlanningLink, error) {
+	link, err := resolvePlanningLink(root, parentID, childID)
+	if err != nil {
+		return nil, err
+	}
+	down, up := link.refLists()
+	if !slices.Contains(*down, childID) && !slices.Contains(*up, parentID) {
+		return nil, fmt.Errorf("%s '%s' is not linked to %s '%s'", link.ChildKind(), childID, link.Parent.Kind, parentID)
+	}
+	*down = slices.DeleteFunc(*down, func(id string) bool { return id == childID })
+	*up = slices.DeleteFunc(*up, func(id string) bool { return id == parentID })
+	if err := link.save(root, now); err != nil {
+		return nil, err
+	}
+	EmitEvent(root, Event{Type: EventPlanningUnlinked, Subject: parentID, From: childID, TaskID: link.taskID()})
+	return link, nil
+}
+
+// planningParentKinds lists the kinds a child's back-reference may name.
+var planningParentKinds = map[PlanningKind][]PlanningKind{
+	PlanningKindMilestone: {PlanningKindRoadmap},
+	PlanningKindPhase:     {PlanningKindRoadmap, PlanningKindMilestone},
+}
+
+// resolvePlanningLink loads both ends and checks the hierarchy: roadmaps hold
+// milestones and phases, milestones hold phases, and any kind holds tasks.
+func resolvePlanningLink(root, parentID, childID string) (*PlanningLink, error) {
+	if parentID == childID {
+		return nil, fmt.Errorf("cannot link '%s' to itself", parentID)
+	}
+	parent, err := LoadPlanningRecord(root, parentID)
+	if err != nil {
+		return nil, err
+	}
+	child, err := LoadPlanningRecord(root, childID)
+	if err == nil {
+		for _, want := range planningParentKinds[child.Kind] {
+			if len(validatePlanningRef(root, parentID, want)) == 0 {
+				return &PlanningLink{Parent: parent, Child: child}, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot link %s '%s' under %s '%s'", child.Kind, childID, parent.Kind, parentID)
+	}
+	if !errors.Is(err, ErrPlanningNotFound) {
+		return nil, err
+	}
+	task, _, err := LoadTaskRecord(root, childID)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is neither a planning record nor a task", childID)
+	}
+	return &PlanningLink{Parent: parent, Task: task}, nil
+}
+
+// refLists returns the parent's downward list and the child's upward list.
+func (l *PlanningLink) refLists() (down, up *[]string) {
+	if l.Task != nil {
+		return &l.Parent.TaskIDs, &l.Task.ParentIDs
+	}
+	switch l.Child.Kind {
+	case PlanningKindMilestone:
+		down = &l.Parent.MilestoneIDs
+	default:
+		down = &l.Parent.PhaseIDs
+	}
+	switch l.Parent.Kind {
+	case PlanningKindRoadmap:
+		up = &l.Child.RoadmapIDs
+	default:
+		up = &l.Child.MilestoneIDs
+	}
+	return down, up
+}
+
+// save writes the child first so a conflicting parent write leaves, at worst,
+// a back-reference that ValidatePlanningReferences reports as dangling.
+func (l *PlanningLink) save(root string, now time.Time) error {
+	stamp := now.UTC().Format(time.RFC3339)
+	if l.Task != nil {
+		if err := SaveTaskRecord(root, l.Task); err != nil {
+			return fmt.Errorf("save task '%s': %w", l.Task.TaskID, err)
+		}
+	} else {
+		l.Child.UpdatedAt = stamp
+		if err := savePlanningRecord(root, l.Child); err != nil {
+			return fmt.Errorf("save planning '%s': %w", l.Child.ID, err)
+		}
+	}
+	l.Parent.UpdatedAt = stamp
+	if err := savePlanningRecord(root, l.Parent); err != nil {
+		return fmt.Errorf("save planning '%s': %w", l.Parent.ID, err)
+	}
+	return nil
+}
+
+func (l *PlanningLink) taskID() string {
+	if l.Task != nil {
+		return l.Task.TaskID
+	}
+	return ""
+}
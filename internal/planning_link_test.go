@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLinkPlanningMaintainsBackReferences(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	roadmap, err := CreatePlanningRecord(root, PlanningKindRoadmap, "Roadmap", now)
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord roadmap: %v", err)
+	}
+	milestone, err := CreatePlanningRecord(root, PlanningKindMilestone, "Milestone", now)
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord milestone: %v", err)
+	}
+	phase, err := CreatePlanningRecord(root, PlanningKindPhase, "Phase", now)
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord phase: %v", err)
+	}
+	task, err := CreateTaskPackage(root, "Task", "backend", "", now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+
+	for _, pair := range [][2]string{{roadmap.ID, milestone.ID}, {milestone.ID, phase.ID}, {phase.ID, task.TaskID}, {phase.ID, task.TaskID}} {
+		if _, err := LinkPlanning(root, pair[0], pair[1], now); err != nil {
+			t.Fatalf("LinkPlanning(%s, %s): %v", pair[0], pair[1], err)
+		}
+	}
+
+	loadPlanning := func(id string) *PlanningRecord {
+		t.Helper()
+		record, err := LoadPlanningRecord(root, id)
+		if err != nil {
+			t.Fatalf("LoadPlanningRecord(%s): %v", id, err)
+		}
+		return record
+	}
+	if got := loadPlanning(roadmap.ID).MilestoneIDs; !slices.Equal(got, []string{milestone.ID}) {
+		t.Fatalf("roadmap MilestoneIDs = %v", got)
+	}
+	ms := loadPlanning(milestone.ID)
+	if !slices.Equal(ms.RoadmapIDs, []string{roadmap.ID}) || !slices.Equal(ms.PhaseIDs, []string{phase.ID}) {
+		t.Fatalf("milestone refs = %v / %v", ms.RoadmapIDs, ms.PhaseIDs)
+	}
+	ph := loadPlanning(phase.ID)
+	if !slices.Equal(ph.MilestoneIDs, []string{milestone.ID}) || !slices.Equal(ph.TaskIDs, []string{task.TaskID}) {
+		t.Fatalf("phase refs = %v / %v", ph.MilestoneIDs, ph.TaskIDs)
+	}
+	loadedTask, _, err := LoadTaskRecord(root, task.TaskID)
+	if err != nil {
+		t.Fatalf("LoadTaskRecord: %v", err)
+	}
+	if !slices.Equal(loadedTask.ParentIDs, []string{phase.ID}) {
+		t.Fatalf("task ParentIDs = %v", loadedTask.ParentIDs)
+	}
+
+	if _, err := UnlinkPlanning(root, phase.ID, task.TaskID, now); err != nil {
+		t.Fatalf("UnlinkPlanning: %v", err)
+	}
+	if got := loadPlanning(phase.ID).TaskIDs; len(got) != 0 {
+		t.Fatalf("phase TaskIDs after unlink = %v", got)
+	}
+	loadedTask, _, _ = LoadTaskRecord(root, task.TaskID)
+	if len(loadedTask.ParentIDs) != 0 {
+		t.Fatalf("task ParentIDs after unlink = %v", loadedTask.ParentIDs)
+	}
+	if _, err := UnlinkPlanning(root, phase.ID, task.TaskID, now); err == nil {
+		t.Fatal("expected error unlinking twice")
+	}
+}
+
+func TestLinkPlanningRejectsInvalidHierarchy(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	roadmap, _ := CreatePlanningRecord(root, PlanningKindRoadmap, "Roadmap", now)
+	phase, _ := CreatePlanningRecord(root, PlanningKindPhase, "Phase", now)
+	if _, err := LinkPlanning(root, phase.ID, roadmap.ID, now); err == nil {
+		t.Fatal("expected error linking roadmap under phase")
+	}
+	if _, err := LinkPlanning(root, phase.ID, phase.ID, now); err == nil {
+		t.Fatal("expected error linking to itself")
+	}
+	if _, err := LinkPlanning(root, roadmap.ID, "missing", now); err == nil {
+		t.Fatal("expected error for unknown child")
+	}
+	if _, err := LinkPlanning(root, "missing", phase.ID, now); err == nil {
+		t.Fatal("expected error for unknown parent")
+	}
+}
+
+func TestLinkPlanningReportsUnreadableChild(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	roadmap, _ := CreatePlanningRecord(root, PlanningKindRoadmap, "Roadmap", now)
+	path := PlanningYAMLPath(root, PlanningKindPhase, "broken-phase", PlanningLifecycleActive)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("id: broken-phase\nkind: phase\nphase_ids: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LinkPlanning(root, roadmap.ID, "broken-phase", now)
+	if err == nil || !strings.Contains(err.Error(), "parse planning record") {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+}
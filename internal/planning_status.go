@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PlanningStatus is the work status of a planning artifact, independent of
+// the storage lifecycle (planning/archived/deprecated).
+type PlanningStatus string
+
+const (
+	PlanningStatusProposed  PlanningStatus = "proposed"
+	PlanningStatusActive    PlanningStatus = "active"
+	PlanningStatusDone      PlanningStatus = "done"
+	PlanningStatusCancelled PlanningStatus = "cancelled"
+)
+
+var validPlanningTransitions = map[PlanningStatus][]PlanningStatus{
+	PlanningStatusProposed:  {PlanningStatusActive, PlanningStatusCancelled},
+	PlanningStatusActive:    {PlanningStatusDone, PlanningStatusCancelled},
+	PlanningStatusDone:      {PlanningStatusActive},
+	PlanningStatusCancelled: {PlanningStatusProposed},
+}
+
+func ValidPlanningStatus(status PlanningStatus) bool {
+	_, ok := validPlanningTransitions[status]
+	return ok
+}
+
+func ParsePlanningStatus(raw string) (PlanningStatus, error) {
+	status := PlanningStatus(raw)
+	if !ValidPlanningStatus(status) {
+		return "", fmt.Errorf("invalid planning status: %s (use proposed, active, done, or cancelled)", raw)
+	}
+	return status, nil
+}
+
+func ValidatePlanningTransition(from, to PlanningStatus) error {
+	allowed, ok := validPlanningTransitions[from]
+	if !ok {
+		return fmt.Errorf("unknown planning status: %s", from)
+	}
+	for _, next := range allowed {
+		if next == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid planning transition: %s → %s", from, to)
+}
+
+// normalizeLegacyPlanningStatus maps the free-form statuses written before
+// the status machine existed onto its states; anything unrecognized restarts
+// as proposed.
+func normalizeLegacyPlanningStatus(raw string) PlanningStatus {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "active", "in_progress", "in-progress", "started", "doing":
+		return PlanningStatusActive
+	case "done", "complete", "completed", "finished":
+		return PlanningStatusDone
+	case "cancelled", "canceled", "dropped":
+		return PlanningStatusCancelled
+	default:
+		return PlanningStatusProposed
+	}
+}
+
+// SetPlanningStatus moves a planning artifact through the status machine.
+func SetPlanningStatus(root, id string, to PlanningStatus, now time.Time) (*PlanningRecord, error) {
+	record, err := LoadPlanningRecord(root, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidatePlanningTransition(record.Status, to); err != nil {
+		return nil, err
+	}
+	if err := setPlanningStatus(root, record, to, now); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func setPlanningStatus(root string, record *PlanningRecord, to PlanningStatus, now time.Time) error {
+	from := record.Status
+	record.Status = to
+	record.UpdatedAt = now.UTC().Format(time.RFC3339)
+	if err := savePlanningRecord(root, record); err != nil {
+		record.Status = from
+		return err
+	}
+	EmitEvent(root, Event{Type: EventPlanningTransition, Subject: record.ID, From: string(from), To: string(to)})
+	return nil
+}
+
+// AutoPromotePlanning marks every active planning artifact as done once all
+// of its non-deprecated tasks, including those under child artifacts, are
+// archived. Proposed artifacts pass through active on the way. Returns the
+// promoted records.
+func AutoPromotePlanning(root string, now time.Time) ([]*PlanningRecord, error) {
+	nodes, err := BuildPlanningTree(root, "")
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var promoted []*PlanningRecord
+	var walk func(node *PlanningTreeNode) error
+	walk = func(node *PlanningTreeNode) error {
+		if seen[node.ID] {
+			return nil
+		}
+		seen[node.ID] = true
+		for _, child := range node.Children {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		if node.Status != PlanningStatusProposed && node.Status != PlanningStatusActive {
+			return nil
+		}
+		live := node.Progress.Total - node.Progress.Counts[TaskStatusDeprecated]
+		if live == 0 || node.Progress.Counts[TaskStatusArchived] != live {
+			return nil
+		}
+		record, err := LoadPlanningRecord(root, node.ID)
+		if err != nil {
+			return err
+		}
+		if record.Status == PlanningStatusProposed {
+			if err := setPlanningStatus(root, record, PlanningStatusActive, now); err != nil {
+				return err
+			}
+		}
+		if err := setPlanningStatus(root, record, PlanningStatusDone, now); err != nil {
+			return err
+		}
+		promoted = append(promoted, record)
+		return nil
+	}
+	for _, node := range nodes {
+		if err := walk(node); err != nil {
+			return promoted, err
+		}
+	}
+	return promoted, nil
+}
@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidatePlanningTransition(t *testing.T) {
+	for _, tc := range []struct {
+		from, to PlanningStatus
+		ok       bool
+	}{
+		{PlanningStatusProposed, PlanningStatusActive, true},
+		{PlanningStatusActive, PlanningStatusDone, true},
+		{PlanningStatusActive, PlanningStatusCancelled, true},
+		{PlanningStatusDone, PlanningStatusActive, true},
+		{PlanningStatusProposed, PlanningStatusDone, false},
+		{PlanningStatusCancelled, PlanningStatusDone, false},
+		{"in_progress", PlanningStatusDone, false},
+	} {
+		err := ValidatePlanningTransition(tc.from, tc.to)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s → %s: err = %v, want ok=%v", tc.from, tc.to, err, tc.ok)
+		}
+	}
+}
+
+func TestSetPlanningStatus(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	record, err := CreatePlanningRecord(root, PlanningKindPhase, "Phase", now)
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord: %v", err)
+	}
+	if _, err := SetPlanningStatus(root, record.ID, PlanningStatusDone, now); err == nil {
+		t.Fatal("expected proposed → done to be rejected")
+	}
+	if _, err := SetPlanningStatus(root, record.ID, PlanningStatusActive, now); err != nil {
+		t.Fatalf("SetPlanningStatus: %v", err)
+	}
+	loaded, err := LoadPlanningRecord(root, record.ID)
+	if err != nil {
+		t.Fatalf("LoadPlanningRecord: %v", err)
+	}
+	if loaded.Status != PlanningStatusActive {
+		t.Fatalf("Status = %s", loaded.Status)
+	}
+}
+
+func TestAutoPromotePlanningWhenTasksArchived(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	milestone, _ := CreatePlanningRecord(root, PlanningKindMilestone, "Milestone", now)
+	phase, _ := CreatePlanningRecord(root, PlanningKindPhase, "Phase", now)
+	if _, err := LinkPlanning(root, milestone.ID, phase.ID, now); err != nil {
+		t.Fatalf("LinkPlanning: %v", err)
+	}
+	if _, err := SetPlanningStatus(root, phase.ID, PlanningStatusActive, now); err != nil {
+		t.Fatalf("SetPlanningStatus: %v", err)
+	}
+	task, err := CreateTaskPackage(root, "Task", "backend", "", now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	if _, err := LinkPlanning(root, phase.ID, task.TaskID, now); err != nil {
+		t.Fatalf("LinkPlanning task: %v", err)
+	}
+
+	promoted, err := AutoPromotePlanning(root, now)
+	if err != nil || len(promoted) != 0 {
+		t.Fatalf("AutoPromotePlanning before archive = %v, %v", promoted, err)
+	}
+
+	if _, err := BindTaskToWorker(root, task.TaskID, "backend-001", "", now); err != nil {
+		t.Fatalf("BindTaskToWorker: %v", err)
+	}
+	if _, err := MarkTaskDone(root, task.TaskID, "", now); err != nil {
+		t.Fatalf("MarkTaskDone: %v", err)
+	}
+	if err := os.WriteFile(TaskVerificationPath(root, task.TaskID), []byte("# Verification\n\n## Result\n- pass\n"), 0644); err != nil {
+		t.Fatalf("WriteFile verification: %v", err)
+	}
+	if _, err := ArchiveTask(root, task.TaskID, "deadbeef", "", false, now); err != nil {
+		t.Fatalf("ArchiveTask: %v", err)
+	}
+
+	promoted, err = AutoPromotePlanning(root, now)
+	if err != nil {
+		t.Fatalf("AutoPromotePlanning: %v", err)
+	}
+	if len(promoted) != 2 || promoted[0].ID != phase.ID || promoted[1].ID != milestone.ID {
+		t.Fatalf("promoted = %+v", promoted)
+	}
+	for _, id := range []string{phase.ID, milestone.ID} {
+		record, _ := LoadPlanningRecord(root, id)
+		if record.Status != PlanningStatusDone {
+			t.Fatalf("%s status = %s", id, record.Status)
+		}
+	}
+	if promoted, _ := AutoPromotePlanning(root, now); len(promoted) != 0 {
+		t.Fatalf("second AutoPromotePlanning = %+v", promoted)
+	}
+}
+
+func TestMovePlanningTreeCascades(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	roadmap, _ := CreatePlanningRecord(root, PlanningKindRoadmap, "Roadmap", now)
+	milestone, _ := CreatePlanningRecord(root, PlanningKindMilestone, "Milestone", now)
+	phase, _ := CreatePlanningRecord(root, PlanningKindPhase, "Phase", now)
+	other, _ := CreatePlanningRecord(root, PlanningKindPhase, "Other", now)
+	for _, pair := range [][2]string{{roadmap.ID, milestone.ID}, {milestone.ID, phase.ID}} {
+		if _, err := LinkPlanning(root, pair[0], pair[1], now); err != nil {
+			t.Fatalf("LinkPlanning: %v", err)
+		}
+	}
+	moved, err := MovePlanningTree(root, roadmap.ID, PlanningLifecycleArchived, "", now)
+	if err != nil {
+		t.Fatalf("MovePlanningTree: %v", err)
+	}
+	if len(moved) != 3 {
+		t.Fatalf("moved = %d records, want 3", len(moved))
+	}
+	for _, id := range []string{roadmap.ID, milestone.ID, phase.ID} {
+		record, err := LoadPlanningRecord(root, id)
+		if err != nil || record.Lifecycle != PlanningLifecycleArchived {
+			t.Fatalf("%s = %+v, %v", id, record, err)
+		}
+	}
+	if record, _ := LoadPlanningRecord(root, other.ID); record.Lifecycle != PlanningLifecycleActive {
+		t.Fatalf("unrelated phase moved: %s", record.Lifecycle)
+	}
+}
+
+func TestMigrateSchemaNormalizesPlanningStatus(t *testing.T) {
+	result, err := MigrateSchema(SchemaKindPlanning, []byte("schema_version: 1\nid: p\nkind: phase\nstatus: In_Progress\n"))
+	if err != nil {
+		t.Fatalf("MigrateSchema: %v", err)
+	}
+	if !strings.Contains(string(result.Data), "status: active") || !strings.Contains(string(result.Data), "schema_version: 2") {
+		t.Fatalf("migrated = %s", result.Data)
+	}
+}
@@ -1,16 +1,18 @@
 package internal
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
-
-	"gopkg.in/yaml.v3"
 )
 
+// ErrPlanningNotFound is returned when no lifecycle directory holds the record.
+var ErrPlanningNotFound = errors.New("planning not found")
+
 func PlanningRootDir(root string) string {
 	return filepath.Join(AgentTeamDir(root), "planning")
 }
@@ -80,7 +82,7 @@ func CreatePlanningRecord(root string, kind PlanningKind, title string, now time
 		ID:        id,
 		Kind:      kind,
 		Title:     title,
-		Status:    "proposed",
+		Status:    PlanningStatusProposed,
 		Lifecycle: PlanningLifecycleActive,
 		Path:      PlanningRelPath(kind, id, PlanningLifecycleActive),
 		CreatedAt: now.UTC().Format(time.RFC3339),
@@ -89,6 +91,7 @@ func CreatePlanningRecord(root string, kind PlanningKind, title string, now time
 	if err := savePlanningRecord(root, record); err != nil {
 		return nil, err
 	}
+	EmitEvent(root, Event{Type: EventPlanningCreated, Subject: record.ID, To: string(record.Lifecycle), Message: record.Title})
 	return record, nil
 }
 
@@ -104,19 +107,23 @@ func LoadPlanningRecord(root, id string) (*PlanningRecord, error) {
 				return nil, fmt.Errorf("read planning record: %w", err)
 			}
 			var record PlanningRecord
-			if err := yaml.Unmarshal(data, &record); err != nil {
+			if err := decodeMigratedYAML(SchemaKindPlanning, data, &record); err != nil {
 				return nil, fmt.Errorf("parse planning record: %w", err)
 			}
 			if !ValidPlanningKind(record.Kind) {
 				return nil, fmt.Errorf("invalid planning kind: %s", record.Kind)
 			}
+			if record.Lifecycle == "" {
+				// Legacy records predate the field; their directory says where they are.
+				record.Lifecycle = lifecycle
+			}
 			if !ValidPlanningLifecycle(record.Lifecycle) {
 				return nil, fmt.Errorf("invalid planning lifecycle: %s", record.Lifecycle)
 			}
 			return &record, nil
 		}
 	}
-	return nil, fmt.Errorf("planning '%s' not found", id)
+	return nil, fmt.Errorf("%w: %s", ErrPlanningNotFound, id)
 }
 
 func ListPlanningRecords(root string, kind PlanningKind, lifecycle PlanningLifecycle) ([]*PlanningRecord, error) {
@@ -211,20 +218,37 @@ func MovePlanningRecord(root, id string, to PlanningLifecycle, reason string, no
 		}
 		return nil, fmt.Errorf("write moved planning metadata: %w", err)
 	}
+	EmitEvent(root, Event{Type: EventPlanningMoved, Subject: record.ID, From: string(from), To: string(to), Message: record.DeprecatedReason})
 	return record, nil
 }
 
+// MovePlanningTree moves id and every planning record below it to the target
+// lifecycle. Records already there are skipped; linked tasks keep their own
+// lifecycle. Returns the moved records.
+func MovePlanningTree(root, id string, to PlanningLifecycle, reason string, now time.Time) ([]*PlanningRecord, error) {
+	records, err := PlanningSubtree(root, id)
+	if err != nil {
+		return nil, err
+	}
+	var moved []*PlanningRecord
+	for _, record := range records {
+		if record.Lifecycle == to {
+			continue
+		}
+		next, err := MovePlanningRecord(root, record.ID, to, reason, now)
+		if err != nil {
+			return moved, fmt.Errorf("move %s '%s': %w", record.Kind, record.ID, err)
+		}
+		moved = append(moved, next)
+	}
+	return moved, nil
+}
+
 func savePlanningRecord(root string, record *PlanningRecord) error {
 	dir := PlanningDir(root, record.Kind, record.ID, record.Lifecycle)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("create planning directory: %w", err)
 	}
-	data, err := yaml.Marshal(record)
-	if err != nil {
-		return fmt.Errorf("marshal planning record: %w", err)
-	}
-	if err := os.WriteFile(PlanningYAMLPath(root, record.Kind, record.ID, record.Lifecycle), data, 0644); err != nil {
-		return fmt.Errorf("write planning record: %w", err)
-	}
-	return nil
+	record.SchemaVersion = CurrentSchemaVersion(SchemaKindPlanning)
+	return saveYAMLState(PlanningYAMLPath(root, record.Kind, record.ID, record.Lifecycle), &record.Revision, record)
 }
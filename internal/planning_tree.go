@@ -0,0 +1,352 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// planningProgressStatuses are the task statuses counted in progress rollups.
+var planningProgressStatuses = []TaskStatus{
+	TaskStatusDraft, TaskStatusAssigned, TaskStatusVerifying, TaskStatusArchived, TaskStatusDeprecated,
+}
+
+// PlanningProgress aggregates the statuses of every task under a node.
+// Percent values are rounded down shares of Total; archived counts as done.
+type PlanningProgress struct {
+	Total   int                `json:"total"`
+	Counts  map[TaskStatus]int `json:"counts"`
+	Percent map[TaskStatus]int `json:"percent"`
+}
+
+// DonePercent is the share of non-deprecated tasks that are archived.
+func (p PlanningProgress) DonePercent() int {
+	live := p.Total - p.Counts[TaskStatusDeprecated]
+	if live <= 0 {
+		return 0
+	}
+	return p.Counts[TaskStatusArchived] * 100 / live
+}
+
+// PlanningTreeTask is a task leaf in the planning tree.
+type PlanningTreeTask struct {
+	TaskID string     `json:"task_id"`
+	Title  string     `json:"title"`
+	Status TaskStatus `json:"status"`
+}
+
+// PlanningTreeNode is one planning record with its resolved children.
+type PlanningTreeNode struct {
+	ID        string              `json:"id"`
+	Kind      PlanningKind        `json:"kind"`
+	Title     string              `json:"title"`
+	Status    PlanningStatus      `json:"status,omitempty"`
+	Lifecycle PlanningLifecycle   `json:"lifecycle"`
+	Progress  PlanningProgress    `json:"progress"`
+	Issues    []string            `json:"issues,omitempty"`
+	Children  []*PlanningTreeNode `json:"children,omitempty"`
+	Tasks     []PlanningTreeTask  `json:"tasks,omitempty"`
+
+	taskIDs map[string]bool
+}
+
+// BuildPlanningTree resolves the hierarchy below id, or below every active
+// top-level record when id is empty. Children are taken from downward
+// references (roadmap → milestones/phases, milestone → phases) and from
+// upward references on active records (a phase listing its milestone).
+func BuildPlanningTree(root, id string) ([]*PlanningTreeNode, error) {
+	b := &planningTreeBuilder{
+		root:    root,
+		records: map[string]*PlanningRecord{},
+		tasks:   map[string]*TaskRecord{},
+		parents: map[string][]string{},
+	}
+	active, err := ListPlanningRecords(root, "", PlanningLifecycleActive)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range active {
+		b.records[record.ID] = record
+	}
+	for _, record := range active {
+		for _, parentID := range planningUpwardRefs(record) {
+			b.parents[parentID] = appendUnique(b.parents[parentID], record.ID)
+		}
+	}
+
+	var rootIDs []string
+	if id != "" {
+		if _, err := b.load(id); err != nil {
+			return nil, err
+		}
+		rootIDs = []string{id}
+	} else {
+		referenced := map[string]bool{}
+		for _, record := range active {
+			for _, childID := range b.childIDs(record) {
+				referenced[childID] = true
+			}
+		}
+		for _, record := range active {
+			if !referenced[record.ID] {
+				rootIDs = append(rootIDs, record.ID)
+			}
+		}
+		sort.SliceStable(rootIDs, func(i, j int) bool {
+			ki, kj := planningKindRank(b.records[rootIDs[i]].Kind), planningKindRank(b.records[rootIDs[j]].Kind)
+			if ki != kj {
+				return ki < kj
+			}
+			return rootIDs[i] < rootIDs[j]
+		})
+	}
+
+	nodes := make([]*PlanningTreeNode, 0, len(rootIDs))
+	for _, rootID := range rootIDs {
+		node, err := b.build(rootID, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+type planningTreeBuilder struct {
+	root    string
+	records map[string]*PlanningRecord
+	tasks   map[string]*TaskRecord
+	parents map[string][]string
+}
+
+func (b *planningTreeBuilder) load(id string) (*PlanningRecord, error) {
+	if record, ok := b.records[id]; ok {
+		return record, nil
+	}
+	record, err := LoadPlanningRecord(b.root, id)
+	if err != nil {
+		return nil, err
+	}
+	b.records[id] = record
+	return record, nil
+}
+
+// childIDs lists the planning children of record in hierarchy order.
+func (b *planningTreeBuilder) childIDs(record *PlanningRecord) []string {
+	var candidates []string
+	switch record.Kind {
+	case PlanningKindRoadmap:
+		candidates = append(candidates, record.MilestoneIDs...)
+		candidates = append(candidates, record.PhaseIDs...)
+	case PlanningKindMilestone:
+		candidates = append(candidates, record.PhaseIDs...)
+	}
+	for _, childID := range b.parents[record.ID] {
+		child := b.records[childID]
+		if child != nil && planningKindRank(child.Kind) > planningKindRank(record.Kind) {
+			candidates = append(candidates, childID)
+		}
+	}
+	var ids []string
+	for _, childID := range candidates {
+		ids = appendUnique(ids, childID)
+	}
+	return ids
+}
+
+func (b *planningTreeBuilder) build(id string, path map[string]bool) (*PlanningTreeNode, error) {
+	record, err := b.load(id)
+	if err != nil {
+		return nil, err
+	}
+	node := &PlanningTreeNode{
+		ID:        record.ID,
+		Kind:      record.Kind,
+		Title:     record.Title,
+		Status:    record.Status,
+		Lifecycle: record.Lifecycle,
+		Issues:    ValidatePlanningReferences(b.root, record),
+		taskIDs:   map[string]bool{},
+	}
+	path[id] = true
+	defer delete(path, id)
+
+	for _, childID := range b.childIDs(record) {
+		if path[childID] {
+			node.Issues = append(node.Issues, fmt.Sprintf("reference cycle: %s → %s", id, childID))
+			continue
+		}
+		if _, err := b.load(childID); err != nil {
+			// Already reported by ValidatePlanningReferences.
+			continue
+		}
+		child, err := b.build(childID, path)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+		for taskID := range child.taskIDs {
+			node.taskIDs[taskID] = true
+		}
+	}
+
+	for _, taskID := range record.TaskIDs {
+		task, ok := b.task(taskID)
+		if !ok {
+			continue
+		}
+		node.Tasks = append(node.Tasks, PlanningTreeTask{TaskID: task.TaskID, Title: task.Title, Status: task.Status})
+		node.taskIDs[taskID] = true
+	}
+
+	node.Progress = b.progress(node.taskIDs)
+	return node, nil
+}
+
+// PlanningSubtree returns id followed by every planning record below it, each
+// once, parents before children.
+func PlanningSubtree(root, id string) ([]*PlanningRecord, error) {
+	nodes, err := BuildPlanningTree(root, id)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var records []*PlanningRecord
+	var walk func(node *PlanningTreeNode) error
+	walk = func(node *PlanningTreeNode) error {
+		if seen[node.ID] {
+			return nil
+		}
+		seen[node.ID] = true
+		record, err := LoadPlanningRecord(root, node.ID)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+		for _, child := range node.Children {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, node := range nodes {
+		if err := walk(node); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+func (b *planningTreeBuilder) task(taskID string) (*TaskRecord, bool) {
+	if task, ok := b.tasks[taskID]; ok {
+		return task, task != nil
+	}
+	task, _, err := LoadTaskRecord(b.root, taskID)
+	if err != nil {
+		b.tasks[taskID] = nil
+		return nil, false
+	}
+	b.tasks[taskID] = task
+	return task, true
+}
+
+func (b *planningTreeBuilder) progress(taskIDs map[string]bool) PlanningProgress {
+	progress := PlanningProgress{Counts: map[TaskStatus]int{}, Percent: map[TaskStatus]int{}}
+	for _, status := range planningProgressStatuses {
+		progress.Counts[status] = 0
+		progress.Percent[status] = 0
+	}
+	for taskID := range taskIDs {
+		if task, ok := b.task(taskID); ok {
+			progress.Counts[task.Status]++
+			progress.Total++
+		}
+	}
+	if progress.Total > 0 {
+		for _, status := range planningProgressStatuses {
+			progress.Percent[status] = progress.Counts[status] * 100 / progress.Total
+		}
+	}
+	return progress
+}
+
+// RenderPlanningTreeMermaid renders nodes as a Mermaid flowchart.
+func RenderPlanningTreeMermaid(nodes []*PlanningTreeNode) string {
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+	ids := map[string]string{}
+	nodeID := func(key string) (string, bool) {
+		if id, ok := ids[key]; ok {
+			return id, false
+		}
+		id := fmt.Sprintf("n%d", len(ids))
+		ids[key] = id
+		return id, true
+	}
+
+	var walk func(node *PlanningTreeNode) string
+	walk = func(node *PlanningTreeNode) string {
+		id, fresh := nodeID("planning:" + node.ID)
+		if !fresh {
+			return id
+		}
+		label := fmt.Sprintf("%s: %s<br/>%d%% done (%d tasks)", node.Kind, node.Title, node.Progress.DonePercent(), node.Progress.Total)
+		if len(node.Issues) > 0 {
+			label += fmt.Sprintf("<br/>⚠ %d issue(s)", len(node.Issues))
+		}
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", id, mermaidEscape(label))
+		for _, child := range node.Children {
+			childID := walk(child)
+			fmt.Fprintf(&sb, "  %s --> %s\n", id, childID)
+		}
+		for _, task := range node.Tasks {
+			taskID, fresh := nodeID("task:" + task.TaskID)
+			if fresh {
+				fmt.Fprintf(&sb, "  %s([\"%s<br/>%s\"])\n", taskID, mermaidEscape(task.Title), task.Status)
+			}
+			fmt.Fprintf(&sb, "  %s --> %s\n", id, taskID)
+		}
+		return id
+	}
+	for _, node := range nodes {
+		walk(node)
+	}
+	return sb.String()
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+func planningUpwardRefs(record *PlanningRecord) []string {
+	var ids []string
+	switch record.Kind {
+	case PlanningKindMilestone:
+		ids = append(ids, record.RoadmapIDs...)
+	case PlanningKindPhase:
+		ids = append(ids, record.RoadmapIDs...)
+		ids = append(ids, record.MilestoneIDs...)
+	}
+	return ids
+}
+
+func planningKindRank(kind PlanningKind) int {
+	switch kind {
+	case PlanningKindRoadmap:
+		return 0
+	case PlanningKindMilestone:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
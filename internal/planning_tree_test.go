@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildPlanningTreeRollsUpTaskProgress(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	roadmap, err := CreatePlanningRecord(root, PlanningKindRoadmap, "Roadmap", now)
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord roadmap: %v", err)
+	}
+	milestone, err := CreatePlanningRecord(root, PlanningKindMilestone, "Milestone", now)
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord milestone: %v", err)
+	}
+	phase, err := CreatePlanningRecord(root, PlanningKindPhase, "Phase", now)
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord phase: %v", err)
+	}
+
+	var taskIDs []string
+	for i, status := range []TaskStatus{TaskStatusDraft, TaskStatusAssigned, TaskStatusVerifying, TaskStatusVerifying} {
+		task, err := CreateTaskPackage(root, "Task "+string(rune('A'+i)), "backend", "", now.Add(time.Duration(i)*time.Minute))
+		if err != nil {
+			t.Fatalf("CreateTaskPackage: %v", err)
+		}
+		task.Status = status
+		if err := SaveTaskRecord(root, task); err != nil {
+			t.Fatalf("SaveTaskRecord: %v", err)
+		}
+		taskIDs = append(taskIDs, task.TaskID)
+	}
+
+	roadmap.MilestoneIDs = []string{milestone.ID}
+	phase.MilestoneIDs = []string{milestone.ID}
+	phase.TaskIDs = taskIDs[:3]
+	milestone.TaskIDs = taskIDs[3:]
+	milestone.PhaseIDs = []string{"missing-phase"}
+	for _, record := range []*PlanningRecord{roadmap, milestone, phase} {
+		if err := savePlanningRecord(root, record); err != nil {
+			t.Fatalf("savePlanningRecord: %v", err)
+		}
+	}
+
+	nodes, err := BuildPlanningTree(root, "")
+	if err != nil {
+		t.Fatalf("BuildPlanningTree: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].ID != roadmap.ID {
+		t.Fatalf("roots = %#v", nodes)
+	}
+	top := nodes[0]
+	if len(top.Children) != 1 || top.Children[0].ID != milestone.ID {
+		t.Fatalf("roadmap children = %#v", top.Children)
+	}
+	ms := top.Children[0]
+	if len(ms.Children) != 1 || ms.Children[0].ID != phase.ID {
+		t.Fatalf("milestone children = %#v", ms.Children)
+	}
+	if got := ms.Children[0].Progress; got.Total != 3 || got.Counts[TaskStatusVerifying] != 1 || got.Percent[TaskStatusDraft] != 33 {
+		t.Fatalf("phase progress = %#v", got)
+	}
+	if got := top.Progress; got.Total != 4 || got.Counts[TaskStatusVerifying] != 2 || got.Percent[TaskStatusVerifying] != 50 {
+		t.Fatalf("roadmap progress = %#v", got)
+	}
+	if !strings.Contains(strings.Join(ms.Issues, "\n"), "missing phase reference: missing-phase") {
+		t.Fatalf("milestone issues = %#v", ms.Issues)
+	}
+
+	sub, err := BuildPlanningTree(root, phase.ID)
+	if err != nil {
+		t.Fatalf("BuildPlanningTree(phase): %v", err)
+	}
+	if len(sub) != 1 || len(sub[0].Tasks) != 3 {
+		t.Fatalf("phase subtree = %#v", sub)
+	}
+
+	mermaid := RenderPlanningTreeMermaid(nodes)
+	for _, needle := range []string{"graph TD", "roadmap: Roadmap", "0% done (4 tasks)", "-->"} {
+		if !strings.Contains(mermaid, needle) {
+			t.Fatalf("mermaid missing %q:\n%s", needle, mermaid)
+		}
+	}
+}
+
+func TestBuildPlanningTreeMissingID(t *testing.T) {
+	if _, err := BuildPlanningTree(t.TempDir(), "nope"); err == nil {
+		t.Fatal("expected error for unknown planning id")
+	}
+}
+
+func TestPlanningProgressDonePercentIgnoresDeprecated(t *testing.T) {
+	p := PlanningProgress{Total: 5, Counts: map[TaskStatus]int{TaskStatusArchived: 2, TaskStatusDeprecated: 1, TaskStatusDraft: 2}}
+	if got := p.DonePercent(); got != 50 {
+		t.Fatalf("DonePercent = %d, want 50", got)
+	}
+	if got := (PlanningProgress{}).DonePercent(); got != 0 {
+		t.Fatalf("empty DonePercent = %d", got)
+	}
+}
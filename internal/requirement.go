@@ -27,6 +27,7 @@ type SubTask struct {
 	AssignedTo string        `yaml:"assigned_to,omitempty"`
 	Status     SubTaskStatus `yaml:"status"`
 	ChangeName string        `yaml:"change_name,omitempty"`
+	TaskID     string        `yaml:"task_id,omitempty"`
 }
 
 // Requirement represents a named requirement with sub-tasks.
@@ -36,6 +37,7 @@ type Requirement struct {
 	Status      RequirementStatus `yaml:"status"`
 	CreatedAt   string            `yaml:"created_at"`
 	SubTasks    []SubTask         `yaml:"sub_tasks,omitempty"`
+	Revision    int               `yaml:"revision,omitempty"`
 }
 
 // RequirementIndexEntry is a summary entry in the requirement index.
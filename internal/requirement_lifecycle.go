@@ -102,3 +102,63 @@ func AssignSubTask(req *Requirement, subTaskID int, workerID, changeName string)
 
 	return nil
 }
+
+// SkipSubTask marks a pending or assigned sub-task as skipped and
+// auto-promotes the requirement if nothing else remains.
+func SkipSubTask(req *Requirement, subTaskID int) error {
+	idx := findSubTaskIndex(req, subTaskID)
+	if idx == -1 {
+		return fmt.Errorf("sub-task %d not found", subTaskID)
+	}
+
+	if err := ValidateSubTaskTransition(req.SubTasks[idx].Status, SubTaskStatusSkipped); err != nil {
+		return err
+	}
+
+	req.SubTasks[idx].Status = SubTaskStatusSkipped
+	AutoPromoteRequirement(req)
+
+	return nil
+}
+
+// SplitRequirement appends pending sub-tasks with the next free IDs and
+// returns the newly added entries. Done requirements cannot be split.
+func SplitRequirement(req *Requirement, titles []string) ([]SubTask, error) {
+	if req.Status == RequirementStatusDone {
+		return nil, fmt.Errorf("requirement '%s' is done", req.Name)
+	}
+
+	nextID := 1
+	for _, st := range req.SubTasks {
+		if st.ID >= nextID {
+			nextID = st.ID + 1
+		}
+	}
+
+	added := make([]SubTask, 0, len(titles))
+	for _, title := range titles {
+		added = append(added, SubTask{ID: nextID, Title: title, Status: SubTaskStatusPending})
+		nextID++
+	}
+	req.SubTasks = append(req.SubTasks, added...)
+
+	return added, nil
+}
+
+// FindSubTask returns the sub-task with the given ID.
+func FindSubTask(req *Requirement, subTaskID int) (*SubTask, error) {
+	idx := findSubTaskIndex(req, subTaskID)
+	if idx == -1 {
+		return nil, fmt.Errorf("sub-task %d not found in requirement '%s'", subTaskID, req.Name)
+	}
+	return &req.SubTasks[idx], nil
+}
+
+func findSubTaskIndex(req *Requirement, subTaskID int) int {
+	for i := range req.SubTasks {
+		if req.SubTasks[i].ID == subTaskID {
+			return i
+		}
+	}
+	return -1
+}
@@ -391,3 +391,53 @@ func TestMarkSubTaskDoneAlreadyDone(t *testing.T) {
 		t.Error("MarkSubTaskDone on already-done sub-task should fail")
 	}
 }
+
+func TestSkipSubTaskAutoPromotes(t *testing.T) {
+	req := &Requirement{
+		Status: RequirementStatusInProgress,
+		SubTasks: []SubTask{
+			{ID: 1, Status: SubTaskStatusDone},
+			{ID: 2, Status: SubTaskStatusPending},
+		},
+	}
+
+	if err := SkipSubTask(req, 2); err != nil {
+		t.Fatalf("SkipSubTask: %v", err)
+	}
+	if req.SubTasks[1].Status != SubTaskStatusSkipped {
+		t.Errorf("SubTask status = %q, want skipped", req.SubTasks[1].Status)
+	}
+	if req.Status != RequirementStatusDone {
+		t.Errorf("status = %q, want done (auto-promote)", req.Status)
+	}
+	if err := SkipSubTask(req, 1); err == nil {
+		t.Error("skipping a done sub-task should fail")
+	}
+	if err := SkipSubTask(req, 9); err == nil {
+		t.Error("skipping a missing sub-task should fail")
+	}
+}
+
+func TestSplitRequirementAssignsNextIDs(t *testing.T) {
+	req := &Requirement{
+		Name:     "auth",
+		Status:   RequirementStatusOpen,
+		SubTasks: []SubTask{{ID: 1, Status: SubTaskStatusPending}, {ID: 4, Status: SubTaskStatusPending}},
+	}
+
+	added, err := SplitRequirement(req, []string{"API", "UI"})
+	if err != nil {
+		t.Fatalf("SplitRequirement: %v", err)
+	}
+	if len(added) != 2 || added[0].ID != 5 || added[1].ID != 6 || added[1].Status != SubTaskStatusPending {
+		t.Fatalf("unexpected sub-tasks: %+v", added)
+	}
+	if len(req.SubTasks) != 4 {
+		t.Fatalf("len(SubTasks) = %d, want 4", len(req.SubTasks))
+	}
+
+	req.Status = RequirementStatusDone
+	if _, err := SplitRequirement(req, []string{"late"}); err == nil {
+		t.Error("splitting a done requirement should fail")
+	}
+}
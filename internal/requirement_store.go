@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -39,16 +40,7 @@ func SaveRequirement(wtPath string, req *Requirement) error {
 		return fmt.Errorf("create requirement directory: %w", err)
 	}
 
-	data, err := yaml.Marshal(req)
-	if err != nil {
-		return fmt.Errorf("marshal requirement: %w", err)
-	}
-
-	if err := os.WriteFile(yamlPath, data, 0644); err != nil {
-		return fmt.Errorf("write requirement.yaml: %w", err)
-	}
-
-	return nil
+	return saveYAMLState(yamlPath, &req.Revision, req)
 }
 
 // LoadRequirement loads a requirement from its YAML file.
@@ -119,6 +111,57 @@ func CreateRequirement(wtPath, name, description string, subTasks []SubTask) (*R
 	return req, nil
 }
 
+// ValidateRequirementName ensures a requirement name is a kebab-case directory name.
+func ValidateRequirementName(name string) error {
+	if !IsKebabCase(name) {
+		return fmt.Errorf("requirement name %q must be kebab-case (example: 'user-auth')", name)
+	}
+	return nil
+}
+
+// SaveRequirementAndIndex saves a requirement and refreshes its index entry.
+func SaveRequirementAndIndex(wtPath string, req *Requirement) error {
+	if err := SaveRequirement(wtPath, req); err != nil {
+		return err
+	}
+	return UpdateIndexEntry(wtPath, req)
+}
+
+// CreateSubTaskPackage creates a task package for a pending sub-task, links
+// the two in both directions, and moves the sub-task to assigned.
+func CreateSubTaskPackage(root string, req *Requirement, subTaskID int, role string, now time.Time) (*TaskRecord, error) {
+	st, err := FindSubTask(req, subTaskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateSubTaskTransition(st.Status, SubTaskStatusAssigned); err != nil {
+		return nil, err
+	}
+
+	design := fmt.Sprintf("Requirement: %s (sub-task %d)", req.Name, st.ID)
+	if strings.TrimSpace(req.Description) != "" {
+		design += "\n\n" + strings.TrimSpace(req.Description)
+	}
+	record, err := CreateTaskPackage(root, st.Title, role, design, now)
+	if err != nil {
+		return nil, err
+	}
+	record.Requirement = req.Name
+	record.SubTaskID = st.ID
+	if err := SaveTaskRecord(root, record); err != nil {
+		return nil, err
+	}
+
+	if err := AssignSubTask(req, subTaskID, "", ""); err != nil {
+		return nil, err
+	}
+	st.TaskID = record.TaskID
+	if err := SaveRequirementAndIndex(root, req); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
 // --- RequirementIndex CRUD ---
 
 // LoadRequirementIndex loads the requirement index from index.yaml.
@@ -142,6 +185,13 @@ func LoadRequirementIndex(wtPath string) (*RequirementIndex, error) {
 
 // SaveRequirementIndex saves the requirement index to index.yaml.
 func SaveRequirementIndex(wtPath string, idx *RequirementIndex) error {
+	return WithSharedStateLock(RequirementIndexPath(wtPath), func() error {
+		return writeRequirementIndex(wtPath, idx)
+	})
+}
+
+// writeRequirementIndex writes index.yaml; callers hold the index lock.
+func writeRequirementIndex(wtPath string, idx *RequirementIndex) error {
 	idxPath := RequirementIndexPath(wtPath)
 
 	if err := os.MkdirAll(filepath.Dir(idxPath), 0755); err != nil {
@@ -153,7 +203,7 @@ func SaveRequirementIndex(wtPath string, idx *RequirementIndex) error {
 		return fmt.Errorf("marshal index: %w", err)
 	}
 
-	if err := os.WriteFile(idxPath, data, 0644); err != nil {
+	if err := WriteFileAtomic(idxPath, data, 0644); err != nil {
 		return fmt.Errorf("write index.yaml: %w", err)
 	}
 
@@ -162,46 +212,58 @@ func SaveRequirementIndex(wtPath string, idx *RequirementIndex) error {
 
 // RebuildRequirementIndex rebuilds the index by scanning all requirement directories.
 func RebuildRequirementIndex(wtPath string) (*RequirementIndex, error) {
-	reqs, err := ListRequirements(wtPath)
-	if err != nil {
-		return nil, fmt.Errorf("list requirements: %w", err)
-	}
-
 	idx := &RequirementIndex{}
-	for _, req := range reqs {
-		idx.Requirements = append(idx.Requirements, buildIndexEntry(req))
-	}
-
-	if err := SaveRequirementIndex(wtPath, idx); err != nil {
+	err := WithSharedStateLock(RequirementIndexPath(wtPath), func() error {
+		reqs, err := ListRequirements(wtPath)
+		if err != nil {
+			return fmt.Errorf("list requirements: %w", err)
+		}
+		for _, req := range reqs {
+			idx.Requirements = append(idx.Requirements, buildIndexEntry(req))
+		}
+		return writeRequirementIndex(wtPath, idx)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return idx, nil
 }
 
+// UpdateRequirementIndex loads index.yaml, applies mutate and saves the
+// result while holding the index lock, so concurrent updates are not lost.
+func UpdateRequirementIndex(wtPath string, mutate func(idx *RequirementIndex) error) error {
+	return WithSharedStateLock(RequirementIndexPath(wtPath), func() error {
+		idx, err := LoadRequirementIndex(wtPath)
+		if err != nil {
+			return err
+		}
+		if err := mutate(idx); err != nil {
+			return err
+		}
+		return writeRequirementIndex(wtPath, idx)
+	})
+}
+
 // UpdateIndexEntry updates a single entry in the index for the given requirement.
 // If the requirement is not found in the index, it is appended.
 func UpdateIndexEntry(wtPath string, req *Requirement) error {
-	idx, err := LoadRequirementIndex(wtPath)
-	if err != nil {
-		return err
-	}
-
-	entry := buildIndexEntry(req)
-	found := false
-	for i, e := range idx.Requirements {
-		if e.Name == req.Name {
-			idx.Requirements[i] = entry
-			found = true
-			break
+	return UpdateRequirementIndex(wtPath, func(idx *RequirementIndex) error {
+		entry := buildIndexEntry(req)
+		found := false
+		for i, e := range idx.Requirements {
+			if e.Name == req.Name {
+				idx.Requirements[i] = entry
+				found = true
+				break
+			}
 		}
-	}
 
-	if !found {
-		idx.Requirements = append(idx.Requirements, entry)
-	}
-
-	return SaveRequirementIndex(wtPath, idx)
+		if !found {
+			idx.Requirements = append(idx.Requirements, entry)
+		}
+		return nil
+	})
 }
 
 // buildIndexEntry creates an index entry from a requirement.
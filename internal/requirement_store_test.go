@@ -2,9 +2,12 @@
 package internal
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestRequirementPaths(t *testing.T) {
@@ -240,6 +243,35 @@ func TestUpdateIndexEntry(t *testing.T) {
 	}
 }
 
+func TestParallelUpdateIndexEntryKeepsEveryEntry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	wtPath := t.TempDir()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := &Requirement{Name: fmt.Sprintf("req-%d", i), Status: RequirementStatusOpen}
+			if err := UpdateIndexEntry(wtPath, req); err != nil {
+				t.Errorf("UpdateIndexEntry(%s): %v", req.Name, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	idx, err := LoadRequirementIndex(wtPath)
+	if err != nil {
+		t.Fatalf("LoadRequirementIndex failed: %v", err)
+	}
+	if len(idx.Requirements) != 8 {
+		t.Fatalf("Expected 8 entries, got %d: %+v", len(idx.Requirements), idx.Requirements)
+	}
+	if _, err := os.Stat(RequirementIndexPath(wtPath) + ".lock"); !os.IsNotExist(err) {
+		t.Fatalf("lock file left next to index.yaml: %v", err)
+	}
+}
+
 func TestRebuildRequirementIndex(t *testing.T) {
 	wtPath := t.TempDir()
 
@@ -495,3 +527,49 @@ func TestRebuildRequirementIndexEmpty(t *testing.T) {
 		t.Errorf("Saved index should have 0 entries, got %d", len(loaded.Requirements))
 	}
 }
+
+func TestCreateSubTaskPackageLinksBothWays(t *testing.T) {
+	root := t.TempDir()
+	req, err := CreateRequirement(root, "user-auth", "Login and signup", []SubTask{
+		{ID: 1, Title: "Login API", Status: SubTaskStatusPending},
+	})
+	if err != nil {
+		t.Fatalf("CreateRequirement: %v", err)
+	}
+
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	record, err := CreateSubTaskPackage(root, req, 1, "backend", now)
+	if err != nil {
+		t.Fatalf("CreateSubTaskPackage: %v", err)
+	}
+	if record.Requirement != "user-auth" || record.SubTaskID != 1 || record.Title != "Login API" {
+		t.Fatalf("unexpected task record: %+v", record)
+	}
+
+	stored, _, err := LoadTaskRecord(root, record.TaskID)
+	if err != nil {
+		t.Fatalf("LoadTaskRecord: %v", err)
+	}
+	if stored.Requirement != "user-auth" || stored.SubTaskID != 1 {
+		t.Fatalf("task.yaml missing back-link: %+v", stored)
+	}
+
+	loaded, err := LoadRequirement(root, "user-auth")
+	if err != nil {
+		t.Fatalf("LoadRequirement: %v", err)
+	}
+	if loaded.Status != RequirementStatusInProgress || loaded.SubTasks[0].Status != SubTaskStatusAssigned || loaded.SubTasks[0].TaskID != record.TaskID {
+		t.Fatalf("unexpected requirement after assign: %+v", loaded)
+	}
+	idx, err := LoadRequirementIndex(root)
+	if err != nil {
+		t.Fatalf("LoadRequirementIndex: %v", err)
+	}
+	if len(idx.Requirements) != 1 || idx.Requirements[0].Status != RequirementStatusInProgress {
+		t.Fatalf("index not updated: %+v", idx.Requirements)
+	}
+
+	if _, err := CreateSubTaskPackage(root, loaded, 1, "backend", now.Add(time.Minute)); err == nil {
+		t.Fatal("assigning an already assigned sub-task should fail")
+	}
+}
@@ -363,7 +363,7 @@ func NextWorkerID(root, wtBase, roleName string) string {
 
 // WriteWorktreeGitignore writes a .gitignore to exclude worker-local files.
 func WriteWorktreeGitignore(wtPath string) error {
-	content := ".gitignore\n.claude/\n.codex/\n.gemini/\n.opencode/\n.tasks/\nworker.yaml\nCLAUDE.md\nGEMINI.md\nAGENTS.md\n"
+	content := ".gitignore\n.claude/\n.codex/\n.gemini/\n.opencode/\n.tasks/\nworker.yaml\nworker.yaml.lock\nCLAUDE.md\nGEMINI.md\nAGENTS.md\n"
 	return os.WriteFile(filepath.Join(wtPath, ".gitignore"), []byte(content), 0644)
 }
 
@@ -542,12 +542,16 @@ func HasRulesDir(root string) bool {
 }
 
 // buildRoleIdentity returns a minimal role identity string (role name + description).
+// The description may be inherited through extends or mixins.
 func buildRoleIdentity(roleName, rolePath string) string {
-	ry := readRoleYAMLFull(rolePath)
+	description := readRoleYAMLFull(rolePath).Description
+	if role, err := ResolveRoleFromPath(rolePath); err == nil {
+		description = role.Description
+	}
 	var b strings.Builder
 	b.WriteString("# System Prompt: " + roleName + "\n\nYou are the " + roleName + " role.\n")
-	if ry.Description != "" {
-		b.WriteString("\nPrimary objective:\n" + ry.Description + "\n")
+	if description != "" {
+		b.WriteString("\nPrimary objective:\n" + description + "\n")
 	}
 	return b.String()
 }
@@ -642,12 +646,19 @@ func buildSkillIndexSection(root, roleName, rolePath string) string {
 // When .agent-team/rules/ exists, uses slim mode (minimal identity + rules index + skill index).
 // Otherwise falls back to legacy mode (full system.md + inline completion protocol).
 func buildRoleSectionFromPath(wtPath, workerID, roleName, rolePath, root string) (string, error) {
-	roleSystemPath := filepath.Join(rolePath, "system.md")
-	if _, err := os.Stat(roleSystemPath); err != nil {
-		if os.IsNotExist(err) {
-			return "", nil
+	role, err := ResolveRoleFromPath(rolePath)
+	if err != nil {
+		// Fall back to the role's own files so a broken role.yaml does not block injection.
+		fmt.Fprintf(os.Stderr, "Warning: resolve role '%s': %v; using its own system.md\n", roleName, err)
+		role = &RoleDefinition{Description: readRoleYAMLFull(rolePath).Description}
+		prompt, readErr := os.ReadFile(filepath.Join(rolePath, "system.md"))
+		if readErr != nil && !os.IsNotExist(readErr) {
+			return "", readErr
 		}
-		return "", err
+		role.SystemPrompt = string(prompt)
+	}
+	if strings.TrimSpace(role.SystemPrompt) == "" {
+		return "", nil
 	}
 
 	var depSkills []string
@@ -676,12 +687,8 @@ func buildRoleSectionFromPath(wtPath, workerID, roleName, rolePath, root string)
 		b.WriteString(buildRulesIndexSection(root))
 		b.WriteString(buildSkillIndexSection(root, roleName, rolePath))
 	} else {
-		// Legacy mode: full system.md + full inline template
-		prompt, err := os.ReadFile(roleSystemPath)
-		if err != nil {
-			return "", err
-		}
-		b.Write(prompt)
+		// Legacy mode: full (resolved) system.md + full inline template
+		b.WriteString(role.SystemPrompt)
 		if err := legacyRoleSectionTmpl.Execute(&b, data); err != nil {
 			return "", fmt.Errorf("execute legacy role section template: %w", err)
 		}
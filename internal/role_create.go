@@ -2,16 +2,13 @@
 package internal
 
 import (
-	"bytes"
 	"embed"
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
-	"text/template"
 )
 
 //go:embed templates/*.tmpl
@@ -351,19 +348,10 @@ func RenderFiles(config RoleConfig) (map[string]string, error) {
 
 	rendered := make(map[string]string, len(managedFiles))
 	for outputPath, tmplName := range managedFiles {
-		tmplContent, err := fs.ReadFile(roleTemplateFS, "templates/"+tmplName)
+		content, err := renderRoleTemplate(tmplName, data)
 		if err != nil {
-			return nil, fmt.Errorf("read template %s: %w", tmplName, err)
+			return nil, err
 		}
-		t, err := template.New(tmplName).Parse(string(tmplContent))
-		if err != nil {
-			return nil, fmt.Errorf("parse template %s: %w", tmplName, err)
-		}
-		var buf bytes.Buffer
-		if err := t.Execute(&buf, data); err != nil {
-			return nil, fmt.Errorf("render template %s: %w", tmplName, err)
-		}
-		content := strings.TrimRight(buf.String(), "\n") + "\n"
 		rendered[outputPath] = content
 	}
 	return rendered, nil
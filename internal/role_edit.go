@@ -0,0 +1,294 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RoleEdit describes in-place changes to a role's references/role.yaml.
+// A nil Description leaves the description unchanged.
+type RoleEdit struct {
+	Description      *string
+	AddSkills        []RoleSkillSpec
+	RemoveSkills     []string
+	AddInScope       []string
+	RemoveInScope    []string
+	AddOutOfScope    []string
+	RemoveOutOfScope []string
+}
+
+// RoleEditResult reports which files EditRole rewrote.
+type RoleEditResult struct {
+	Changed []string
+}
+
+// EditRole applies edit to the role at rolePath. role.yaml is edited as a
+// yaml.Node tree so comments and key order survive; SKILL.md only has its
+// frontmatter description re-rendered, and only when the description or
+// in-scope list changed.
+func EditRole(rolePath string, edit RoleEdit) (RoleEditResult, error) {
+	var result RoleEditResult
+	yamlPath := filepath.Join(rolePath, "references", "role.yaml")
+	data, err := os.ReadFile(yamlPath)
+	if err != nil {
+		return result, fmt.Errorf("read role.yaml: %w", err)
+	}
+	if _, err := readRoleSkillSpecs(data); err != nil {
+		return result, fmt.Errorf("parse role.yaml: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return result, fmt.Errorf("parse role.yaml: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return result, fmt.Errorf("parse role.yaml: top level must be a mapping")
+	}
+	root := doc.Content[0]
+
+	yamlChanged, skillMDChanged := false, false
+	if edit.Description != nil {
+		description := strings.TrimSpace(*edit.Description)
+		if description == "" {
+			return result, fmt.Errorf("description must not be empty")
+		}
+		node := ensureYAMLMappingValue(root, "description", yaml.ScalarNode)
+		if node.Value != description {
+			node.Kind, node.Tag, node.Value, node.Style = yaml.ScalarNode, "!!str", description, yaml.DoubleQuotedStyle
+			yamlChanged, skillMDChanged = true, true
+		}
+	}
+
+	for _, list := range []struct {
+		key            string
+		add, remove    []string
+		affectsSkillMD bool
+	}{
+		{"in_scope", edit.AddInScope, edit.RemoveInScope, true},
+		{"out_of_scope", edit.AddOutOfScope, edit.RemoveOutOfScope, false},
+	} {
+		if len(list.add) == 0 && len(list.remove) == 0 {
+			continue
+		}
+		scope := ensureYAMLMappingValue(root, "scope", yaml.MappingNode)
+		if scope.Kind != yaml.MappingNode {
+			return result, fmt.Errorf("scope must be a mapping")
+		}
+		seq := ensureYAMLMappingValue(scope, list.key, yaml.SequenceNode)
+		if seq.Kind != yaml.SequenceNode {
+			return result, fmt.Errorf("scope.%s must be a list", list.key)
+		}
+		changed, err := editYAMLStringList(seq, list.add, list.remove, "scope."+list.key)
+		if err != nil {
+			return result, err
+		}
+		if changed {
+			yamlChanged = true
+			skillMDChanged = skillMDChanged || list.affectsSkillMD
+		}
+	}
+
+	if len(edit.AddSkills) > 0 || len(edit.RemoveSkills) > 0 {
+		seq := ensureYAMLMappingValue(root, "skills", yaml.SequenceNode)
+		changed, err := editYAMLSkillList(seq, edit.AddSkills, edit.RemoveSkills)
+		if err != nil {
+			return result, err
+		}
+		yamlChanged = yamlChanged || changed
+	}
+
+	if yamlChanged {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&doc); err != nil {
+			return result, fmt.Errorf("encode role.yaml: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return result, fmt.Errorf("encode role.yaml: %w", err)
+		}
+		if err := os.WriteFile(yamlPath, buf.Bytes(), 0644); err != nil {
+			return result, err
+		}
+		result.Changed = append(result.Changed, "references/role.yaml")
+	}
+	if skillMDChanged {
+		changed, err := rerenderRoleSkillMDDescription(rolePath)
+		if err != nil {
+			return result, err
+		}
+		if changed {
+			result.Changed = append(result.Changed, "SKILL.md")
+		}
+	}
+	return result, nil
+}
+
+// ensureYAMLMappingValue returns the value for key, appending an empty node
+// of the given kind when the key is missing.
+func ensureYAMLMappingValue(node *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	if value := yamlMappingValue(node, key); value != nil {
+		if value.Kind == yaml.ScalarNode && value.Tag == "!!null" && kind != yaml.ScalarNode {
+			value.Kind, value.Tag, value.Value = kind, "", ""
+		}
+		return value
+	}
+	value := &yaml.Node{Kind: kind}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+func editYAMLStringList(seq *yaml.Node, add, remove []string, field string) (bool, error) {
+	changed := false
+	for _, item := range remove {
+		idx := yamlSequenceIndex(seq, func(n *yaml.Node) bool { return n.Value == item })
+		if idx < 0 {
+			return false, fmt.Errorf("%s does not contain %q", field, item)
+		}
+		seq.Content = append(seq.Content[:idx], seq.Content[idx+1:]...)
+		changed = true
+	}
+	for _, item := range add {
+		if yamlSequenceIndex(seq, func(n *yaml.Node) bool { return n.Value == item }) >= 0 {
+			continue
+		}
+		seq.Style &^= yaml.FlowStyle
+		seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item, Style: yaml.DoubleQuotedStyle})
+		changed = true
+	}
+	return changed, nil
+}
+
+func editYAMLSkillList(seq *yaml.Node, add []RoleSkillSpec, remove []string) (bool, error) {
+	if seq.Kind != yaml.SequenceNode {
+		return false, fmt.Errorf("skills must be a list")
+	}
+	skillName := func(n *yaml.Node) string {
+		if nameNode := yamlMappingValue(n, "name"); nameNode != nil {
+			return nameNode.Value
+		}
+		return ""
+	}
+	changed := false
+	for _, name := range remove {
+		short := parseSkillName(name)
+		idx := yamlSequenceIndex(seq, func(n *yaml.Node) bool {
+			current := skillName(n)
+			return current == name || current == short
+		})
+		if idx < 0 {
+			return false, fmt.Errorf("skills does not contain %q", name)
+		}
+		seq.Content = append(seq.Content[:idx], seq.Content[idx+1:]...)
+		changed = true
+	}
+	for _, skill := range add {
+		if yamlSequenceIndex(seq, func(n *yaml.Node) bool { return skillName(n) == skill.Name }) >= 0 {
+			continue
+		}
+		seq.Style &^= yaml.FlowStyle
+		seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "name"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: skill.Name, Style: yaml.DoubleQuotedStyle},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "description"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: skill.Description, Style: yaml.DoubleQuotedStyle},
+		}})
+		changed = true
+	}
+	return changed, nil
+}
+
+func yamlSequenceIndex(seq *yaml.Node, match func(*yaml.Node) bool) int {
+	for i, item := range seq.Content {
+		if match(item) {
+			return i
+		}
+	}
+	return -1
+}
+
+// rerenderRoleSkillMDDescription re-renders the description in SKILL.md's
+// frontmatter from the SKILL.md template, leaving the rest of the file
+// (other frontmatter keys and the body) untouched.
+func rerenderRoleSkillMDDescription(rolePath string) (bool, error) {
+	skillPath := filepath.Join(rolePath, "SKILL.md")
+	data, err := os.ReadFile(skillPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	content := string(data)
+	frontmatter, ok := splitSkillFrontmatter(content)
+	if !ok {
+		return false, nil
+	}
+	var fm yaml.Node
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
+		return false, fmt.Errorf("parse SKILL.md frontmatter: %w", err)
+	}
+	if len(fm.Content) == 0 || fm.Content[0].Kind != yaml.MappingNode {
+		return false, nil
+	}
+
+	role, err := ReadRoleDefinition(rolePath)
+	if err != nil {
+		return false, err
+	}
+	rendered, err := renderRoleTemplate("SKILL.md.tmpl", templateData{
+		RoleName:       role.Name,
+		Description:    role.Description,
+		InScopeSummary: strings.ToLower(strings.Join(role.InScope, ", ")),
+	})
+	if err != nil {
+		return false, err
+	}
+	renderedFM, _ := splitSkillFrontmatter(rendered)
+	var want struct {
+		Description string `yaml:"description"`
+	}
+	if err := yaml.Unmarshal([]byte(renderedFM), &want); err != nil {
+		return false, fmt.Errorf("parse rendered SKILL.md frontmatter: %w", err)
+	}
+
+	node := ensureYAMLMappingValue(fm.Content[0], "description", yaml.ScalarNode)
+	if node.Value == want.Description {
+		return false, nil
+	}
+	node.Kind, node.Tag, node.Value, node.Style = yaml.ScalarNode, "!!str", want.Description, yaml.FoldedStyle
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&fm); err != nil {
+		return false, fmt.Errorf("encode SKILL.md frontmatter: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return false, fmt.Errorf("encode SKILL.md frontmatter: %w", err)
+	}
+	body := content[strings.Index(content, frontmatter)+len(frontmatter):]
+	return true, os.WriteFile(skillPath, []byte("---\n"+buf.String()+body), 0644)
+}
+
+// renderRoleTemplate renders one of the managed role templates.
+func renderRoleTemplate(tmplName string, data templateData) (string, error) {
+	tmplContent, err := fs.ReadFile(roleTemplateFS, "templates/"+tmplName)
+	if err != nil {
+		return "", fmt.Errorf("read template %s: %w", tmplName, err)
+	}
+	t, err := template.New(tmplName).Parse(string(tmplContent))
+	if err != nil {
+		return "", fmt.Errorf("parse template %s: %w", tmplName, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render template %s: %w", tmplName, err)
+	}
+	return strings.TrimRight(buf.String(), "\n") + "\n", nil
+}
@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEditRolePreservesCommentsAndRerendersSkillMD(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "skills", "vitest"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	result, err := CreateOrUpdateRole(root, RoleConfig{
+		RoleName:    "frontend-dev",
+		Description: "Frontend role",
+		SystemGoal:  "Ship UI",
+		InScope:     []string{"Build components"},
+		OutOfScope:  []string{"Database migrations"},
+		Skills:      []RoleSkillSpec{},
+	}, "yes", nil, ".agent-team/teams")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rolePath := result.TargetDir
+	yamlPath := filepath.Join(rolePath, "references", "role.yaml")
+	data, _ := os.ReadFile(yamlPath)
+	commented := strings.Replace(string(data), "scope:\n", "# keep this comment\nscope:\n", 1)
+	if err := os.WriteFile(yamlPath, []byte(commented), 0644); err != nil {
+		t.Fatal(err)
+	}
+	skillMD := filepath.Join(rolePath, "SKILL.md")
+	original, _ := os.ReadFile(skillMD)
+	if err := os.WriteFile(skillMD, append(original, []byte("\nCustom notes.\n")...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Skills-only edits leave SKILL.md alone.
+	res, err := EditRole(rolePath, RoleEdit{AddSkills: []RoleSkillSpec{{Name: "vitest", Description: "Unit tests"}}})
+	if err != nil {
+		t.Fatalf("EditRole skills: %v", err)
+	}
+	if !reflect.DeepEqual(res.Changed, []string{"references/role.yaml"}) {
+		t.Fatalf("Changed = %v", res.Changed)
+	}
+
+	description := "Frontend and design systems"
+	res, err = EditRole(rolePath, RoleEdit{
+		Description:      &description,
+		AddInScope:       []string{"Design tokens"},
+		RemoveOutOfScope: []string{"Database migrations"},
+		AddOutOfScope:    []string{"Infrastructure"},
+	})
+	if err != nil {
+		t.Fatalf("EditRole: %v", err)
+	}
+	if !reflect.DeepEqual(res.Changed, []string{"references/role.yaml", "SKILL.md"}) {
+		t.Fatalf("Changed = %v", res.Changed)
+	}
+
+	role, err := ReadRoleDefinition(rolePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role.Description != description ||
+		!reflect.DeepEqual(role.InScope, []string{"Build components", "Design tokens"}) ||
+		!reflect.DeepEqual(role.OutOfScope, []string{"Infrastructure"}) ||
+		!reflect.DeepEqual(role.SkillNames(), []string{"vitest"}) {
+		t.Fatalf("unexpected role after edit: %+v", role)
+	}
+	data, _ = os.ReadFile(yamlPath)
+	if !strings.Contains(string(data), "# keep this comment") || !strings.Contains(string(data), "single_role_focus: true") {
+		t.Fatalf("role.yaml lost content:\n%s", data)
+	}
+
+	data, _ = os.ReadFile(skillMD)
+	if !strings.Contains(string(data), "Frontend and design systems") ||
+		!strings.Contains(string(data), "such as: build components, design tokens.") ||
+		!strings.Contains(string(data), "Custom notes.") {
+		t.Fatalf("SKILL.md not re-rendered in place:\n%s", data)
+	}
+	if lint := LintRole(root, rolePath); lint.Errors() != 0 {
+		t.Fatalf("edited role fails lint:\n%s", lintMessages(lint))
+	}
+
+	if _, err := EditRole(rolePath, RoleEdit{RemoveSkills: []string{"missing"}}); err == nil || !strings.Contains(err.Error(), `skills does not contain "missing"`) {
+		t.Fatalf("expected missing skill error, got %v", err)
+	}
+}
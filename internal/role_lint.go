@@ -0,0 +1,235 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Role lint severities. Errors fail `role lint`; warnings are reported only.
+const (
+	RoleLintError   = "error"
+	RoleLintWarning = "warning"
+)
+
+// system.md limits, in line with the project rule file limits.
+const (
+	roleSystemPromptMaxLines    = 200
+	roleSystemPromptMaxBytes    = 16000
+	roleSystemPromptMaxHeadings = 16
+)
+
+// RoleLintIssue is one problem found in a role package. Path is relative to
+// the role directory.
+type RoleLintIssue struct {
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+// RoleLintResult holds the issues found in one role package.
+type RoleLintResult struct {
+	Role   string          `json:"role"`
+	Path   string          `json:"path"`
+	Issues []RoleLintIssue `json:"issues"`
+}
+
+// Errors counts the error-level issues.
+func (r RoleLintResult) Errors() int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == RoleLintError {
+			n++
+		}
+	}
+	return n
+}
+
+// Warnings counts the warning-level issues.
+func (r RoleLintResult) Warnings() int {
+	return len(r.Issues) - r.Errors()
+}
+
+// roleLintYAML is the role.yaml contract checked by LintRole.
+type roleLintYAML struct {
+	Name             string   `yaml:"name"`
+	Description      string   `yaml:"description"`
+	SystemPromptFile string   `yaml:"system_prompt_file"`
+	Extends          string   `yaml:"extends"`
+	Mixins           []string `yaml:"mixins"`
+}
+
+// LintRole validates the role package at rolePath against the role
+// contract: SKILL.md frontmatter, the references/role.yaml schema, skill
+// resolution, system prompt size and scope consistency. Inherited fields
+// (extends/mixins) count toward the required description and scope lists.
+func LintRole(root, rolePath string) RoleLintResult {
+	name := filepath.Base(rolePath)
+	result := RoleLintResult{Role: name, Path: rolePath, Issues: []RoleLintIssue{}}
+	add := func(severity, path, format string, args ...any) {
+		result.Issues = append(result.Issues, RoleLintIssue{Severity: severity, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	lintRoleSkillMD(rolePath, name, add)
+
+	const yamlRel = "references/role.yaml"
+	data, err := os.ReadFile(filepath.Join(rolePath, "references", "role.yaml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			add(RoleLintError, yamlRel, "missing role.yaml")
+		} else {
+			add(RoleLintError, yamlRel, "read failed: %v", err)
+		}
+		return result
+	}
+	var ry roleLintYAML
+	if err := yaml.Unmarshal(data, &ry); err != nil {
+		add(RoleLintError, yamlRel, "invalid YAML: %v", err)
+		return result
+	}
+	if _, err := readRoleSkillSpecs(data); err != nil {
+		add(RoleLintError, yamlRel, "%v", err)
+	}
+	if strings.TrimSpace(ry.Name) == "" {
+		add(RoleLintError, yamlRel, "name is required")
+	} else if ry.Name != name {
+		add(RoleLintError, yamlRel, "name %q does not match directory %q", ry.Name, name)
+	}
+	if !IsKebabCase(name) {
+		add(RoleLintError, "", "role directory %q is not kebab-case", name)
+	}
+
+	promptFile := strings.TrimSpace(ry.SystemPromptFile)
+	inherits := strings.TrimSpace(ry.Extends) != "" || len(ry.Mixins) > 0
+	switch {
+	case promptFile != "":
+		promptPath := filepath.Join(rolePath, filepath.FromSlash(promptFile))
+		if _, err := os.Stat(promptPath); err != nil {
+			add(RoleLintError, yamlRel, "system_prompt_file %s does not exist", promptFile)
+		} else {
+			var metrics []RulesValidationIssue
+			validateMarkdownMetrics(promptPath, promptFile, roleSystemPromptMaxLines, roleSystemPromptMaxBytes, roleSystemPromptMaxHeadings, &metrics)
+			for _, issue := range metrics {
+				add(RoleLintError, issue.Path, "%s", issue.Message)
+			}
+		}
+	case !inherits:
+		add(RoleLintError, yamlRel, "system_prompt_file is required")
+	}
+
+	role, err := ResolveRoleFromPath(rolePath)
+	if err != nil {
+		add(RoleLintError, yamlRel, "resolve role: %v", err)
+		return result
+	}
+	if role.Description == "" {
+		add(RoleLintError, yamlRel, "description is required")
+	}
+	if len(role.InScope) == 0 {
+		add(RoleLintError, yamlRel, "scope.in_scope must not be empty")
+	}
+	if len(role.OutOfScope) == 0 {
+		add(RoleLintError, yamlRel, "scope.out_of_scope must not be empty")
+	}
+	outOfScope := map[string]bool{}
+	for _, item := range role.OutOfScope {
+		outOfScope[strings.ToLower(item)] = true
+	}
+	for _, item := range role.InScope {
+		if outOfScope[strings.ToLower(item)] {
+			add(RoleLintError, yamlRel, "%q is both in scope and out of scope", item)
+		}
+	}
+	for _, skill := range role.Skills {
+		if findLocalSkillPath(root, skill.Name) != "" {
+			continue
+		}
+		if isScopedSkill(skill.Name) {
+			// Scoped skills can still be downloaded when a worker starts.
+			add(RoleLintWarning, yamlRel, "skill %s is not installed locally", skill.Name)
+			continue
+		}
+		add(RoleLintError, yamlRel, "skill %s cannot be resolved", skill.Name)
+	}
+	return result
+}
+
+// lintRoleSkillMD checks that SKILL.md exists and carries name and
+// description frontmatter.
+func lintRoleSkillMD(rolePath, name string, add func(severity, path, format string, args ...any)) {
+	const rel = "SKILL.md"
+	data, err := os.ReadFile(filepath.Join(rolePath, rel))
+	if err != nil {
+		if os.IsNotExist(err) {
+			add(RoleLintError, rel, "missing SKILL.md")
+		} else {
+			add(RoleLintError, rel, "read failed: %v", err)
+		}
+		return
+	}
+	frontmatter, ok := splitSkillFrontmatter(string(data))
+	if !ok {
+		add(RoleLintError, rel, "missing YAML frontmatter")
+		return
+	}
+	var fm struct {
+		Name        string `yaml:"name"`
+		Description string `yaml:"description"`
+	}
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
+		add(RoleLintError, rel, "invalid frontmatter: %v", err)
+		return
+	}
+	if strings.TrimSpace(fm.Name) == "" {
+		add(RoleLintError, rel, "frontmatter name is required")
+	} else if strings.TrimSpace(fm.Name) != name {
+		add(RoleLintError, rel, "frontmatter name %q does not match directory %q", fm.Name, name)
+	}
+	if strings.TrimSpace(fm.Description) == "" {
+		add(RoleLintError, rel, "frontmatter description is required")
+	}
+}
+
+// splitSkillFrontmatter returns the YAML between the leading "---" lines.
+func splitSkillFrontmatter(content string) (string, bool) {
+	content = strings.TrimPrefix(content, "\ufeff")
+	if !strings.HasPrefix(content, "---\n") && !strings.HasPrefix(content, "---\r\n") {
+		return "", false
+	}
+	rest := content[strings.Index(content, "\n")+1:]
+	for offset := 0; offset < len(rest); {
+		end := strings.Index(rest[offset:], "\n")
+		line := rest[offset:]
+		if end >= 0 {
+			line = rest[offset : offset+end]
+		}
+		if strings.TrimRight(line, "\r") == "---" {
+			return rest[:offset], true
+		}
+		if end < 0 {
+			break
+		}
+		offset += end + 1
+	}
+	return "", false
+}
+
+// LintProjectRoles lints every role directory under .agent-team/teams/.
+func LintProjectRoles(root string) []RoleLintResult {
+	teamsDir := filepath.Join(ResolveAgentsDir(root), "teams")
+	entries, err := os.ReadDir(teamsDir)
+	if err != nil {
+		return nil
+	}
+	var results []RoleLintResult
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		results = append(results, LintRole(root, filepath.Join(teamsDir, e.Name())))
+	}
+	return results
+}
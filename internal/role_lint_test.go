@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func lintMessages(result RoleLintResult) string {
+	var b strings.Builder
+	for _, issue := range result.Issues {
+		b.WriteString(issue.Severity + " " + issue.Path + ": " + issue.Message + "\n")
+	}
+	return b.String()
+}
+
+func TestLintRoleAcceptsGeneratedRole(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	skillDir := filepath.Join(root, "skills", "vitest")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	result, err := CreateOrUpdateRole(root, RoleConfig{
+		RoleName:    "frontend-dev",
+		Description: "Frontend role",
+		SystemGoal:  "Ship UI",
+		InScope:     []string{"Build components"},
+		OutOfScope:  []string{"Database migrations"},
+		Skills: []RoleSkillSpec{
+			{Name: "vitest", Description: "Unit tests"},
+			{Name: "antfu/skills@vite", Description: "Vite"},
+		},
+	}, "yes", nil, ".agent-team/teams")
+	if err != nil {
+		t.Fatalf("CreateOrUpdateRole: %v", err)
+	}
+
+	lint := LintRole(root, result.TargetDir)
+	if lint.Errors() != 0 || lint.Warnings() != 1 || !strings.Contains(lintMessages(lint), "antfu/skills@vite is not installed locally") {
+		t.Fatalf("unexpected lint result:\n%s", lintMessages(lint))
+	}
+	if all := LintProjectRoles(root); len(all) != 1 || all[0].Role != "frontend-dev" {
+		t.Fatalf("LintProjectRoles = %+v", all)
+	}
+}
+
+func TestLintRoleReportsContractViolations(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	teams := filepath.Join(root, ".agent-team", "teams")
+	dir := writeRoleFiles(t, teams, "backend", `name: api
+system_prompt_file: prompt.md
+scope:
+  in_scope:
+    - "REST APIs"
+  out_of_scope:
+    - "rest apis"
+skills:
+  - name: "missing-skill"
+    description: "Nowhere"
+`, "")
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lint := LintRole(root, dir)
+	got := lintMessages(lint)
+	for _, want := range []string{
+		"SKILL.md: missing YAML frontmatter",
+		`name "api" does not match directory "backend"`,
+		"system_prompt_file prompt.md does not exist",
+		"description is required",
+		`"REST APIs" is both in scope and out of scope`,
+		"error references/role.yaml: skill missing-skill cannot be resolved",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("lint output missing %q:\n%s", want, got)
+		}
+	}
+
+	big := "# Prompt\n" + strings.Repeat("line\n", roleSystemPromptMaxLines+1)
+	if err := os.WriteFile(filepath.Join(dir, "prompt.md"), []byte(big), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := lintMessages(LintRole(root, dir)); !strings.Contains(got, "prompt.md: file is too long") {
+		t.Fatalf("expected size issue:\n%s", got)
+	}
+}
+
+func TestSplitSkillFrontmatter(t *testing.T) {
+	fm, ok := splitSkillFrontmatter("---\nname: x\ndescription: >\n  a --- b\n---\n# body\n")
+	if !ok || fm != "name: x\ndescription: >\n  a --- b\n" {
+		t.Fatalf("frontmatter = %q, %v", fm, ok)
+	}
+	if _, ok := splitSkillFrontmatter("---\nname: x\n"); ok {
+		t.Fatal("unterminated frontmatter should not parse")
+	}
+}
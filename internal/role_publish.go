@@ -0,0 +1,268 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RolePublishOptions controls PublishRole. Branch defaults to
+// "publish/<role>" and Message to "Publish role <role>".
+type RolePublishOptions struct {
+	Branch  string
+	Message string
+}
+
+// RolePublishResult describes a published role.
+type RolePublishResult struct {
+	RoleName   string
+	RolePath   string // slash-separated path inside the repository, e.g. skills/<role>
+	RepoDir    string
+	Branch     string
+	Commit     string
+	FolderHash string
+	Files      []string
+	Unchanged  bool   // the repository already held these files
+	Pushed     bool   // the branch was pushed to a remote target
+	Source     string // how role-repo add would refer to the repository
+}
+
+// RolePublishTarget is a repository checkout that roles are published
+// into. Remote is set when the checkout was cloned for the publish and the
+// branch must be pushed back.
+type RolePublishTarget struct {
+	Dir    string
+	Remote string
+}
+
+// PrepareRolePublishTarget resolves --to: an existing directory must be a
+// git checkout; anything else is cloned as a git remote into a temporary
+// directory. The returned cleanup removes that clone.
+func PrepareRolePublishTarget(ctx context.Context, to string) (RolePublishTarget, func(), error) {
+	noop := func() {}
+	if info, err := os.Stat(to); err == nil && info.IsDir() {
+		out, err := runRoleRepoGit(ctx, to, "rev-parse", "--show-toplevel")
+		if err != nil {
+			return RolePublishTarget{}, noop, fmt.Errorf("%s is not a git repository: %w", to, err)
+		}
+		return RolePublishTarget{Dir: strings.TrimSpace(string(out))}, noop, nil
+	}
+	if strings.HasPrefix(to, "-") {
+		return RolePublishTarget{}, noop, fmt.Errorf("invalid publish target %q", to)
+	}
+	remote := to
+	if source, err := ParseRoleRepoSource(to); err == nil && source.Type != RoleRepoSourceLocal {
+		remote = source.CloneURL
+		if remote == "" {
+			remote = source.URL() + ".git"
+		}
+	}
+	dir, err := os.MkdirTemp("", "agent-team-publish-")
+	if err != nil {
+		return RolePublishTarget{}, noop, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+	if _, err := runRoleRepoGit(ctx, "", "clone", "--quiet", "--end-of-options", remote, dir); err != nil {
+		cleanup()
+		return RolePublishTarget{}, noop, err
+	}
+	return RolePublishTarget{Dir: dir, Remote: remote}, cleanup, nil
+}
+
+// PublishRole copies the role package at rolePath into skills/<role>/ of the
+// target checkout, the strict layout ParseRolePathFromYAMLPath recognises,
+// and commits it on a branch. Remote targets get the branch pushed. Roles
+// that extend or mix in other roles require those roles to be published in
+// the repository first. Callers are expected to lint the role beforehand.
+func PublishRole(ctx context.Context, rolePath string, target RolePublishTarget, opts RolePublishOptions) (*RolePublishResult, error) {
+	name := filepath.Base(rolePath)
+	if _, _, ok := ParseRolePathFromYAMLPath(path.Join("skills", name, "references", "role.yaml")); !ok || !IsKebabCase(name) {
+		return nil, fmt.Errorf("role name %q cannot be published", name)
+	}
+	result := &RolePublishResult{
+		RoleName: name,
+		RolePath: path.Join("skills", name),
+		RepoDir:  target.Dir,
+		Branch:   strings.TrimSpace(opts.Branch),
+		Source:   rolePublishSource(ctx, target),
+	}
+	if result.Branch == "" {
+		result.Branch = "publish/" + name
+	} else if err := ValidateRoleRepoRef(result.Branch); err != nil {
+		return nil, fmt.Errorf("invalid branch name %q", result.Branch)
+	}
+	message := strings.TrimSpace(opts.Message)
+	if message == "" {
+		message = "Publish role " + name
+	}
+
+	def, err := ReadRoleDefinition(rolePath)
+	if err != nil {
+		return nil, err
+	}
+	parents := def.Mixins
+	if def.Extends != "" {
+		parents = append([]string{def.Extends}, parents...)
+	}
+	for _, parent := range parents {
+		if !fileExists(filepath.Join(target.Dir, "skills", parent, "references", "role.yaml")) {
+			return nil, fmt.Errorf("role %s depends on %s, which is not published in the repository; publish it first", name, parent)
+		}
+	}
+
+	files, _, err := readRoleRepoInstalledFiles(rolePath)
+	if err != nil {
+		return nil, err
+	}
+	for rel := range files {
+		if strings.HasPrefix(path.Base(rel), ".") {
+			delete(files, rel)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("role %s has no files to publish", name)
+	}
+
+	if out, err := runRoleRepoGit(ctx, target.Dir, "status", "--porcelain"); err != nil {
+		return nil, err
+	} else if strings.TrimSpace(string(out)) != "" {
+		return nil, fmt.Errorf("target repository %s has uncommitted changes", target.Dir)
+	}
+	workDir, cleanup, err := rolePublishCheckout(ctx, target, result.Branch)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	destDir := filepath.Join(workDir, "skills", name)
+	if err := os.RemoveAll(destDir); err != nil {
+		return nil, err
+	}
+	if err := WriteRoleRepoMergedFiles(filepath.Join(workDir, "skills"), name, files); err != nil {
+		return nil, err
+	}
+	for rel := range files {
+		result.Files = append(result.Files, rel)
+	}
+	sort.Strings(result.Files)
+	result.FolderHash = RoleRepoInstalledHash(result.RolePath, files)
+
+	if _, err := runRoleRepoGit(ctx, workDir, "add", "-A", "--", result.RolePath); err != nil {
+		return nil, err
+	}
+	if _, err := runRoleRepoGit(ctx, workDir, "diff", "--cached", "--quiet"); err == nil {
+		result.Unchanged = true
+	} else if _, err := runRoleRepoGit(ctx, workDir, "commit", "--quiet", "-m", message); err != nil {
+		return nil, err
+	}
+	out, err := runRoleRepoGit(ctx, workDir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	result.Commit = strings.TrimSpace(string(out))
+
+	if target.Remote != "" && !result.Unchanged {
+		if _, err := runRoleRepoGit(ctx, workDir, "push", "--quiet", "origin", result.Branch); err != nil {
+			return nil, err
+		}
+		result.Pushed = true
+	}
+	return result, nil
+}
+
+// rolePublishCheckout returns a checkout of branch to commit in. A local
+// target gets a temporary worktree so the user's checkout stays on its
+// branch; a clone made for the publish, or a checkout already on branch, is
+// used in place. The returned cleanup removes the worktree.
+func rolePublishCheckout(ctx context.Context, target RolePublishTarget, branch string) (string, func(), error) {
+	noop := func() {}
+	exists := false
+	if _, err := runRoleRepoGit(ctx, target.Dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+		exists = true
+	}
+	if out, err := runRoleRepoGit(ctx, target.Dir, "symbolic-ref", "--short", "--quiet", "HEAD"); err == nil && strings.TrimSpace(string(out)) == branch {
+		return target.Dir, noop, nil
+	}
+	if target.Remote != "" {
+		args := []string{"checkout", "--quiet", "-b", branch}
+		if exists {
+			args = []string{"checkout", "--quiet", branch}
+		}
+		if _, err := runRoleRepoGit(ctx, target.Dir, args...); err != nil {
+			return "", noop, err
+		}
+		return target.Dir, noop, nil
+	}
+	if _, err := runRoleRepoGit(ctx, target.Dir, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil && !exists {
+		return "", noop, fmt.Errorf("target repository %s has no commits to branch %s from", target.Dir, branch)
+	}
+
+	dir, err := os.MkdirTemp("", "agent-team-publish-")
+	if err != nil {
+		return "", noop, err
+	}
+	args := []string{"worktree", "add", "--quiet", "-b", branch, dir}
+	if exists {
+		args = []string{"worktree", "add", "--quiet", dir, branch}
+	}
+	if _, err := runRoleRepoGit(ctx, target.Dir, args...); err != nil {
+		os.RemoveAll(dir)
+		return "", noop, err
+	}
+	cleanup := func() {
+		runRoleRepoGit(context.Background(), target.Dir, "worktree", "remove", "--force", dir)
+		os.RemoveAll(dir)
+		runRoleRepoGit(context.Background(), target.Dir, "worktree", "prune")
+	}
+	return dir, cleanup, nil
+}
+
+// rolePublishSource returns the role-repo source for the target: its remote
+// URL when it has one, else the checkout path.
+func rolePublishSource(ctx context.Context, target RolePublishTarget) string {
+	remote := target.Remote
+	if remote == "" {
+		if out, err := runRoleRepoGit(ctx, target.Dir, "remote", "get-url", "origin"); err == nil {
+			remote = strings.TrimSpace(string(out))
+		}
+	}
+	if remote == "" {
+		return target.Dir
+	}
+	if source, err := ParseRoleRepoSource(remote); err == nil && source.Type != RoleRepoSourceLocal {
+		return source.Canonical()
+	}
+	return remote
+}
+
+// RolePublishCatalogEntry builds the catalog entry for a published role,
+// keeping DiscoveredAt from an existing entry for the same source.
+func RolePublishCatalogEntry(catalog RoleRepoCatalog, result *RolePublishResult, nowFn func() time.Time) RoleRepoCatalogEntry {
+	now := nowFn().UTC()
+	entry := RoleRepoCatalogEntry{
+		Name:         result.RoleName,
+		Source:       result.Source,
+		SourceType:   RoleRepoSourceLocal,
+		SourceURL:    result.Source,
+		RolePath:     result.RolePath,
+		FolderHash:   result.FolderHash,
+		Status:       CatalogStatusDiscovered,
+		DiscoveredAt: now,
+		UpdatedAt:    now,
+	}
+	if source, err := ParseRoleRepoSource(result.Source); err == nil {
+		entry.Source = source.FullName()
+		entry.SourceType = source.Type
+		entry.SourceURL = source.URL()
+	}
+	if existing, ok := FindCatalogEntry(catalog, entry.Source, entry.Name); ok {
+		entry.DiscoveredAt = existing.DiscoveredAt
+		entry.InstallCount = existing.InstallCount
+	}
+	return entry
+}
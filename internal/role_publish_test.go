@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPublishRoleCommitsStrictLayoutAndPushesRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+	root := t.TempDir()
+	created, err := CreateOrUpdateRole(root, RoleConfig{
+		RoleName:    "qa",
+		Description: "QA role",
+		SystemGoal:  "Verify changes",
+		InScope:     []string{"Tests"},
+		OutOfScope:  []string{"Deploys"},
+	}, "yes", nil, ".agent-team/teams")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(created.TargetDir, ".DS_Store"), []byte("junk"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := t.TempDir()
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git(repo, "init", "--quiet", "-b", "main")
+	git(repo, "commit", "--quiet", "--allow-empty", "-m", "init")
+
+	ctx := context.Background()
+	target, cleanup, err := PrepareRolePublishTarget(ctx, repo)
+	if err != nil {
+		t.Fatalf("PrepareRolePublishTarget: %v", err)
+	}
+	defer cleanup()
+	result, err := PublishRole(ctx, created.TargetDir, target, RolePublishOptions{})
+	if err != nil {
+		t.Fatalf("PublishRole: %v", err)
+	}
+	if result.Branch != "publish/qa" || result.RolePath != "skills/qa" || result.Unchanged || result.Pushed {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	// The commit goes through a worktree; the user's checkout stays on main.
+	if got := git(repo, "rev-parse", "--abbrev-ref", "HEAD"); got != "main" {
+		t.Fatalf("checkout moved to %s", got)
+	}
+	if got := git(repo, "log", "-1", "--format=%s", "publish/qa"); got != "Publish role qa" {
+		t.Fatalf("commit subject = %q", got)
+	}
+	if got := git(repo, "worktree", "list", "--porcelain"); strings.Count(got, "worktree ") != 1 {
+		t.Fatalf("publish worktree left behind:\n%s", got)
+	}
+	if strings.Contains(git(repo, "ls-tree", "-r", "--name-only", "publish/qa"), ".DS_Store") {
+		t.Fatal("dotfiles should not be published")
+	}
+	git(repo, "checkout", "--quiet", "publish/qa")
+
+	// The folder hash matches what role-repo discovery computes for the repository.
+	source, err := ParseRoleRepoSource(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roles, err := ResolveRoleRepoRoles(ctx, NewRoleRepoLocalProvider(), source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roles) != 1 || roles[0].Candidate.RolePath != "skills/qa" || roles[0].FolderHash != result.FolderHash {
+		t.Fatalf("discovered %+v, want hash %s", roles, result.FolderHash)
+	}
+
+	again, err := PublishRole(ctx, created.TargetDir, target, RolePublishOptions{})
+	if err != nil {
+		t.Fatalf("PublishRole again: %v", err)
+	}
+	if !again.Unchanged || again.Commit != result.Commit {
+		t.Fatalf("republish should be a no-op: %+v", again)
+	}
+
+	entry := RolePublishCatalogEntry(RoleRepoCatalog{}, result, func() time.Time { return time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC) })
+	if entry.Source != repo || entry.SourceType != RoleRepoSourceLocal || entry.FolderHash != result.FolderHash || entry.Status != CatalogStatusDiscovered {
+		t.Fatalf("unexpected catalog entry: %+v", entry)
+	}
+
+	// A remote target is cloned and the branch pushed back.
+	bare := filepath.Join(t.TempDir(), "roles.git")
+	git(repo, "checkout", "--quiet", "main")
+	git(repo, "clone", "--quiet", "--bare", repo, bare)
+	remote, cleanupRemote, err := PrepareRolePublishTarget(ctx, "file://"+filepath.ToSlash(bare))
+	if err != nil {
+		t.Fatalf("PrepareRolePublishTarget remote: %v", err)
+	}
+	defer cleanupRemote()
+	pushed, err := PublishRole(ctx, created.TargetDir, remote, RolePublishOptions{Branch: "roles/qa", Message: "Add qa"})
+	if err != nil {
+		t.Fatalf("PublishRole remote: %v", err)
+	}
+	if !pushed.Pushed || git(bare, "log", "-1", "--format=%s", "roles/qa") != "Add qa" {
+		t.Fatalf("branch not pushed: %+v", pushed)
+	}
+}
+
+func TestPublishRoleRequiresPublishedParents(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	teams := t.TempDir()
+	writeRoleFiles(t, teams, "base", "name: base\ndescription: Base\nsystem_prompt_file: system.md\n", "## Rules\nBe careful.\n")
+	child := writeRoleFiles(t, teams, "child", "name: child\nextends: base\n", "")
+
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "-C", repo, "init", "--quiet").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	_, err := PublishRole(context.Background(), child, RolePublishTarget{Dir: repo}, RolePublishOptions{})
+	if err == nil || !strings.Contains(err.Error(), "depends on base") {
+		t.Fatalf("expected missing parent error, got %v", err)
+	}
+}
+
+func TestPublishRoleRejectsOptionLikeArguments(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	if _, _, err := PrepareRolePublishTarget(context.Background(), "--upload-pack=touch pwned"); err == nil || !strings.Contains(err.Error(), "invalid publish target") {
+		t.Fatalf("expected invalid target error, got %v", err)
+	}
+	teams := t.TempDir()
+	role := writeRoleFiles(t, teams, "qa", "name: qa\ndescription: QA\nsystem_prompt_file: system.md\n", "## Rules\nTest.\n")
+	_, err := PublishRole(context.Background(), role, RolePublishTarget{Dir: t.TempDir()}, RolePublishOptions{Branch: "--orphan"})
+	if err == nil || !strings.Contains(err.Error(), "invalid branch name") {
+		t.Fatalf("expected invalid branch error, got %v", err)
+	}
+}
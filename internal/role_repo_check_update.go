@@ -4,12 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 )
 
-func CheckRoleRepoUpdates(ctx context.Context, client *RoleRepoGitHubClient, installRoot string, lock RoleRepoLockFile) ([]RoleRepoCheckStatus, []string) {
+func CheckRoleRepoUpdates(ctx context.Context, providers *RoleRepoProviderSet, installRoot string, lock RoleRepoLockFile) ([]RoleRepoCheckStatus, []string) {
 	status := make([]RoleRepoCheckStatus, 0, len(lock.Entries))
 	remoteCache := map[string]map[string]RoleRepoRemoteRole{}
 
@@ -19,28 +20,15 @@ func CheckRoleRepoUpdates(ctx context.Context, client *RoleRepoGitHubClient, ins
 			CurrentHash: entry.FolderHash,
 			Source:      entry.Source,
 			RolePath:    entry.RolePath,
+			Ref:         entry.Ref,
 			State:       "error",
 		}
 
-		repoMap, ok := remoteCache[entry.Source]
-		if !ok {
-			source, err := ParseRoleRepoSource(entry.Source)
-			if err != nil {
-				item.Err = err
-				status = append(status, item)
-				continue
-			}
-			roles, err := client.DiscoverRemoteRoles(ctx, source)
-			if err != nil {
-				item.Err = err
-				status = append(status, item)
-				continue
-			}
-			repoMap = map[string]RoleRepoRemoteRole{}
-			for _, role := range roles {
-				repoMap[role.Candidate.RolePath] = role
-			}
-			remoteCache[entry.Source] = repoMap
+		repoMap, _, err := discoverRoleRepoLockEntry(ctx, providers, entry, remoteCache)
+		if err != nil {
+			item.Err = err
+			status = append(status, item)
+			continue
 		}
 
 		remote, exists := repoMap[entry.RolePath]
@@ -53,6 +41,9 @@ func CheckRoleRepoUpdates(ctx context.Context, client *RoleRepoGitHubClient, ins
 
 		item.RemoteExists = true
 		item.RemoteHash = remote.FolderHash
+		if local, installed, err := readRoleRepoInstalledFiles(filepath.Join(installRoot, entry.Name)); err == nil && installed {
+			item.LocalModified = RoleRepoInstalledHash(entry.RolePath, local) != entry.FolderHash
+		}
 		if remote.FolderHash != entry.FolderHash {
 			item.State = "update_available"
 		} else {
@@ -87,7 +78,13 @@ func roleRepoUntrackedLocalRoles(installRoot string, lock RoleRepoLockFile) []st
 	return out
 }
 
-func UpdateRoleRepoFromLock(ctx context.Context, client *RoleRepoGitHubClient, installRoot string, lock *RoleRepoLockFile, overwrite bool, selectedNames []string, nowFn func() time.Time) (updated []string, skipped []string, failed map[string]error) {
+// UpdateRoleRepoFromLock reinstalls roles whose remote folder changed at
+// their pinned ref. A non-empty toRef moves the selected entries to that ref;
+// their lock entries are repinned even when the role files are unchanged.
+// Installed roles with local modifications are three-way merged with the
+// remote changes and fail with ErrRoleRepoLocalChanges when that is not
+// possible; force overwrites them instead.
+func UpdateRoleRepoFromLock(ctx context.Context, providers *RoleRepoProviderSet, installRoot string, lock *RoleRepoLockFile, overwrite bool, selectedNames []string, toRef string, force bool, nowFn func() time.Time) (updated []string, skipped []string, failed map[string]error) {
 	failed = map[string]error{}
 	remoteCache := map[string]map[string]RoleRepoRemoteRole{}
 	selectedSet := map[string]bool{}
@@ -103,23 +100,13 @@ func UpdateRoleRepoFromLock(ctx context.Context, client *RoleRepoGitHubClient, i
 			skipped = append(skipped, entry.Name)
 			continue
 		}
-		sourceRoles, ok := remoteCache[entry.Source]
-		if !ok {
-			source, err := ParseRoleRepoSource(entry.Source)
-			if err != nil {
-				failed[entry.Name] = err
-				continue
-			}
-			roles, err := client.DiscoverRemoteRoles(ctx, source)
-			if err != nil {
-				failed[entry.Name] = err
-				continue
-			}
-			sourceRoles = map[string]RoleRepoRemoteRole{}
-			for _, role := range roles {
-				sourceRoles[role.Candidate.RolePath] = role
-			}
-			remoteCache[entry.Source] = sourceRoles
+		if toRef != "" {
+			entry.Ref = toRef
+		}
+		sourceRoles, provider, err := discoverRoleRepoLockEntry(ctx, providers, entry, remoteCache)
+		if err != nil {
+			failed[entry.Name] = err
+			continue
 		}
 
 		remoteRole, ok := sourceRoles[entry.RolePath]
@@ -128,6 +115,14 @@ func UpdateRoleRepoFromLock(ctx context.Context, client *RoleRepoGitHubClient, i
 			continue
 		}
 		if remoteRole.FolderHash == entry.FolderHash {
+			// Same files: only the pin and resolved commit move.
+			lock.Entries[i].Commit = remoteRole.Commit
+			if entry.Ref != lock.Entries[i].Ref {
+				lock.Entries[i].Ref = entry.Ref
+				lock.Entries[i].UpdatedAt = nowFn().UTC()
+				updated = append(updated, entry.Name)
+				continue
+			}
 			skipped = append(skipped, entry.Name)
 			continue
 		}
@@ -136,15 +131,16 @@ func UpdateRoleRepoFromLock(ctx context.Context, client *RoleRepoGitHubClient, i
 			continue
 		}
 
-		_, err := InstallRoleRepoRemoteRole(ctx, client, remoteRole, installRoot, true)
-		if err != nil {
+		if err := updateRoleRepoRole(ctx, providers, provider, installRoot, entry, remoteRole, force, remoteCache); err != nil {
 			failed[entry.Name] = err
 			continue
 		}
-		lock.Entries[i].Source = remoteRole.Candidate.Source.FullName()
+		lock.Entries[i].Source = providers.LockSource(remoteRole.Candidate.Source)
 		lock.Entries[i].SourceType = remoteRole.Candidate.SourceType
 		lock.Entries[i].SourceURL = remoteRole.Candidate.SourceURL
 		lock.Entries[i].RolePath = remoteRole.Candidate.RolePath
+		lock.Entries[i].Ref = entry.Ref
+		lock.Entries[i].Commit = remoteRole.Commit
 		lock.Entries[i].FolderHash = remoteRole.FolderHash
 		lock.Entries[i].UpdatedAt = nowFn().UTC()
 		updated = append(updated, entry.Name)
@@ -155,6 +151,111 @@ func UpdateRoleRepoFromLock(ctx context.Context, client *RoleRepoGitHubClient, i
 	return updated, skipped, failed
 }
 
+// updateRoleRepoRole replaces an installed role with remoteRole, merging
+// local modifications unless force is set.
+func updateRoleRepoRole(ctx context.Context, providers *RoleRepoProviderSet, provider RoleRepoProvider, installRoot string, entry RoleRepoLockEntry, remoteRole RoleRepoRemoteRole, force bool, cache map[string]map[string]RoleRepoRemoteRole) error {
+	if !force {
+		diff, err := diffRoleRepoRole(ctx, providers, installRoot, entry, cache)
+		if err != nil {
+			return err
+		}
+		if diff.LocalModified {
+			merged, conflicts, err := MergeRoleRepoRole(diff)
+			if err != nil {
+				return fmt.Errorf("%w (use --force to overwrite)", err)
+			}
+			if len(conflicts) > 0 {
+				return fmt.Errorf("%w: conflicts in %s (run role-repo diff %s, or use --force to overwrite)", ErrRoleRepoLocalChanges, strings.Join(conflicts, ", "), entry.Name)
+			}
+			return WriteRoleRepoMergedFiles(installRoot, entry.Name, merged)
+		}
+	}
+	_, err := InstallRoleRepoRemoteRole(ctx, provider, remoteRole, installRoot, true)
+	return err
+}
+
+// InstallRoleRepoFromLock restores every lock entry (or only names) exactly
+// as recorded: files are read at the locked commit and must hash to the
+// locked folder hash. Existing role directories are replaced; the lock file
+// itself is never changed.
+func InstallRoleRepoFromLock(ctx context.Context, providers *RoleRepoProviderSet, installRoot string, lock RoleRepoLockFile, names []string) (installed []string, failed map[string]error) {
+	failed = map[string]error{}
+	selected := map[string]bool{}
+	for _, name := range names {
+		if _, ok := FindRoleRepoLockEntry(lock, name); !ok {
+			failed[name] = fmt.Errorf("role %q is not in the lock file", name)
+			continue
+		}
+		selected[name] = true
+	}
+	remoteCache := map[string]map[string]RoleRepoRemoteRole{}
+
+	for _, entry := range lock.Entries {
+		if len(names) > 0 && !selected[entry.Name] {
+			continue
+		}
+		if entry.Commit != "" {
+			entry.Ref = entry.Commit
+		}
+		sourceRoles, provider, err := discoverRoleRepoLockEntry(ctx, providers, entry, remoteCache)
+		if err != nil {
+			failed[entry.Name] = err
+			continue
+		}
+		remoteRole, ok := sourceRoles[entry.RolePath]
+		if !ok {
+			failed[entry.Name] = fmt.Errorf("role path %s not found in source", entry.RolePath)
+			continue
+		}
+		if remoteRole.FolderHash != entry.FolderHash {
+			failed[entry.Name] = fmt.Errorf("folder hash mismatch: lock has %s, source has %s (run role-repo update to accept the change)", shortRoleRepoHash(entry.FolderHash), shortRoleRepoHash(remoteRole.FolderHash))
+			continue
+		}
+		if _, err := InstallRoleRepoRemoteRole(ctx, provider, remoteRole, installRoot, true); err != nil {
+			failed[entry.Name] = err
+			continue
+		}
+		installed = append(installed, entry.Name)
+	}
+	sort.Strings(installed)
+	return installed, failed
+}
+
+func shortRoleRepoHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// discoverRoleRepoLockEntry lists the roles of entry's source at its pinned
+// ref through the backend its SourceType names, caching results per source
+// and ref.
+func discoverRoleRepoLockEntry(ctx context.Context, providers *RoleRepoProviderSet, entry RoleRepoLockEntry, cache map[string]map[string]RoleRepoRemoteRole) (map[string]RoleRepoRemoteRole, RoleRepoProvider, error) {
+	source, err := providers.LockEntrySource(entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	provider, err := providers.ForSource(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := source.Type + "\x00" + source.Canonical() + "\x00" + source.Ref
+	if roles, ok := cache[key]; ok {
+		return roles, provider, nil
+	}
+	roles, err := ResolveRoleRepoRoles(ctx, provider, source)
+	if err != nil {
+		return nil, nil, err
+	}
+	byPath := map[string]RoleRepoRemoteRole{}
+	for _, role := range roles {
+		byPath[role.Candidate.RolePath] = role
+	}
+	cache[key] = byPath
+	return byPath, provider, nil
+}
+
 func FormatRoleRepoCheckSummary(statuses []RoleRepoCheckStatus, untracked []string) string {
 	var b strings.Builder
 	if len(statuses) == 0 {
@@ -165,11 +266,17 @@ func FormatRoleRepoCheckSummary(statuses []RoleRepoCheckStatus, untracked []stri
 			case "up_to_date":
 				b.WriteString("- ")
 				b.WriteString(st.Name)
-				b.WriteString(": up to date\n")
+				b.WriteString(": up to date")
+				writeRoleRepoPin(&b, st.Ref)
+				writeRoleRepoLocalChanges(&b, st.LocalModified)
+				b.WriteString("\n")
 			case "update_available":
 				b.WriteString("- ")
 				b.WriteString(st.Name)
-				b.WriteString(": update available\n")
+				b.WriteString(": update available")
+				writeRoleRepoPin(&b, st.Ref)
+				writeRoleRepoLocalChanges(&b, st.LocalModified)
+				b.WriteString("\n")
 			default:
 				b.WriteString("- ")
 				b.WriteString(st.Name)
@@ -191,6 +298,20 @@ func FormatRoleRepoCheckSummary(statuses []RoleRepoCheckStatus, untracked []stri
 	return b.String()
 }
 
+func writeRoleRepoPin(b *strings.Builder, ref string) {
+	if ref != "" {
+		b.WriteString(" (pinned to ")
+		b.WriteString(ref)
+		b.WriteString(")")
+	}
+}
+
+func writeRoleRepoLocalChanges(b *strings.Builder, modified bool) {
+	if modified {
+		b.WriteString(" [local changes]")
+	}
+}
+
 func EnsureRoleRepoInstallRoot(path string) error {
 	if err := os.MkdirAll(path, 0755); err != nil {
 		return err
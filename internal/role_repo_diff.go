@@ -0,0 +1,273 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var ErrRoleRepoLocalChanges = errors.New("role has local modifications")
+
+// RoleRepoFileDiff holds one role file in its locked (base), installed
+// (local) and remote versions. Path is relative to the role folder.
+type RoleRepoFileDiff struct {
+	Path      string
+	Base      []byte
+	Local     []byte
+	Remote    []byte
+	HasBase   bool
+	HasLocal  bool
+	HasRemote bool
+}
+
+// LocallyModified reports whether the installed file differs from the
+// locked one.
+func (d RoleRepoFileDiff) LocallyModified() bool {
+	return d.HasLocal != d.HasBase || !bytes.Equal(d.Local, d.Base)
+}
+
+// Changed reports whether updating would change the installed file.
+func (d RoleRepoFileDiff) Changed() bool {
+	return d.HasLocal != d.HasRemote || !bytes.Equal(d.Local, d.Remote)
+}
+
+// RoleRepoRoleDiff compares an installed role with its lock entry and the
+// remote at the entry's pinned ref. BaseKnown is false when the installed
+// folder was edited and the locked files cannot be fetched (no locked
+// commit, or a local source that has moved on), so no three-way merge is
+// possible.
+type RoleRepoRoleDiff struct {
+	Entry         RoleRepoLockEntry
+	RemoteCommit  string
+	RemoteHash    string
+	LocalHash     string
+	LocalModified bool
+	BaseKnown     bool
+	Files         []RoleRepoFileDiff
+}
+
+// ModifiedFiles lists files edited since install, when the base is known.
+func (d *RoleRepoRoleDiff) ModifiedFiles() []string {
+	var out []string
+	if !d.BaseKnown {
+		return out
+	}
+	for _, f := range d.Files {
+		if f.LocallyModified() {
+			out = append(out, f.Path)
+		}
+	}
+	return out
+}
+
+// RoleRepoInstalledHash hashes an installed role folder the way providers
+// hash remote folders, so an untouched install matches its lock entry.
+func RoleRepoInstalledHash(rolePath string, files map[string][]byte) string {
+	entries := make([]RoleRepoTreeEntry, 0, len(files))
+	for rel, data := range files {
+		entries = append(entries, RoleRepoTreeEntry{Path: rolePath + "/" + rel, Type: "blob", SHA: gitBlobSHA(data)})
+	}
+	return hashRoleRepoTreeFiles(entries)
+}
+
+// DiffRoleRepoRole fetches the remote version of an installed role and
+// compares it file by file with the installed and locked versions.
+func DiffRoleRepoRole(ctx context.Context, providers *RoleRepoProviderSet, installRoot string, entry RoleRepoLockEntry) (*RoleRepoRoleDiff, error) {
+	return diffRoleRepoRole(ctx, providers, installRoot, entry, map[string]map[string]RoleRepoRemoteRole{})
+}
+
+func diffRoleRepoRole(ctx context.Context, providers *RoleRepoProviderSet, installRoot string, entry RoleRepoLockEntry, cache map[string]map[string]RoleRepoRemoteRole) (*RoleRepoRoleDiff, error) {
+	local, installed, err := readRoleRepoInstalledFiles(filepath.Join(installRoot, entry.Name))
+	if err != nil {
+		return nil, err
+	}
+	diff := &RoleRepoRoleDiff{Entry: entry, LocalHash: RoleRepoInstalledHash(entry.RolePath, local)}
+	diff.LocalModified = installed && diff.LocalHash != entry.FolderHash
+
+	sourceRoles, provider, err := discoverRoleRepoLockEntry(ctx, providers, entry, cache)
+	if err != nil {
+		return nil, err
+	}
+	remoteRole, ok := sourceRoles[entry.RolePath]
+	if !ok {
+		return nil, fmt.Errorf("role path %s not found in source", entry.RolePath)
+	}
+	diff.RemoteCommit = remoteRole.Commit
+	diff.RemoteHash = remoteRole.FolderHash
+	blobs := map[string][]byte{}
+	remote, err := fetchRoleRepoFiles(ctx, provider, remoteRole, blobs)
+	if err != nil {
+		return nil, err
+	}
+
+	// An untouched install is the locked version; otherwise read the locked
+	// files back from the locked commit when there is one.
+	base := local
+	diff.BaseKnown = !diff.LocalModified
+	if diff.LocalModified {
+		switch {
+		case remoteRole.FolderHash == entry.FolderHash:
+			base, diff.BaseKnown = remote, true
+		case entry.Commit != "":
+			locked := entry
+			locked.Ref = entry.Commit
+			lockedRoles, _, err := discoverRoleRepoLockEntry(ctx, providers, locked, cache)
+			if err != nil {
+				return nil, fmt.Errorf("read locked commit %s: %w", entry.Commit, err)
+			}
+			if baseRole, ok := lockedRoles[entry.RolePath]; ok && baseRole.FolderHash == entry.FolderHash {
+				if base, err = fetchRoleRepoFiles(ctx, provider, baseRole, blobs); err != nil {
+					return nil, err
+				}
+				diff.BaseKnown = true
+			}
+		}
+	}
+
+	paths := map[string]bool{}
+	for _, m := range []map[string][]byte{local, remote} {
+		for rel := range m {
+			paths[rel] = true
+		}
+	}
+	if diff.BaseKnown {
+		for rel := range base {
+			paths[rel] = true
+		}
+	}
+	for rel := range paths {
+		f := RoleRepoFileDiff{Path: rel}
+		f.Local, f.HasLocal = local[rel]
+		f.Remote, f.HasRemote = remote[rel]
+		if diff.BaseKnown {
+			f.Base, f.HasBase = base[rel]
+		}
+		diff.Files = append(diff.Files, f)
+	}
+	sort.Slice(diff.Files, func(i, j int) bool { return diff.Files[i].Path < diff.Files[j].Path })
+	return diff, nil
+}
+
+// MergeRoleRepoRole three-way merges local edits with the remote changes.
+// It returns the merged folder contents and the files that conflict.
+func MergeRoleRepoRole(diff *RoleRepoRoleDiff) (map[string][]byte, []string, error) {
+	if !diff.BaseKnown {
+		return nil, nil, fmt.Errorf("%w: the locked version is unavailable, so changes cannot be merged", ErrRoleRepoLocalChanges)
+	}
+	merged := map[string][]byte{}
+	var conflicts []string
+	for _, f := range diff.Files {
+		localChanged := f.LocallyModified()
+		remoteChanged := f.HasRemote != f.HasBase || !bytes.Equal(f.Remote, f.Base)
+		switch {
+		case !localChanged:
+			if f.HasRemote {
+				merged[f.Path] = f.Remote
+			}
+		case !remoteChanged || !f.Changed():
+			if f.HasLocal {
+				merged[f.Path] = f.Local
+			}
+		case f.HasBase && f.HasLocal && f.HasRemote:
+			data, n := MergeLines3(f.Base, f.Local, f.Remote)
+			if n > 0 {
+				conflicts = append(conflicts, f.Path)
+				continue
+			}
+			merged[f.Path] = data
+		default:
+			// Added on both sides, or deleted on one and edited on the other.
+			conflicts = append(conflicts, f.Path)
+		}
+	}
+	return merged, conflicts, nil
+}
+
+// WriteRoleRepoMergedFiles replaces the installed role folder's contents
+// with files.
+func WriteRoleRepoMergedFiles(installRoot, name string, files map[string][]byte) error {
+	dir := filepath.Join(installRoot, name)
+	existing, _, err := readRoleRepoInstalledFiles(dir)
+	if err != nil {
+		return err
+	}
+	for rel := range existing {
+		if _, ok := files[rel]; !ok {
+			if err := os.Remove(filepath.Join(dir, filepath.FromSlash(rel))); err != nil {
+				return err
+			}
+		}
+	}
+	for rel, data := range files {
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readRoleRepoInstalledFiles reads every file below dir keyed by its
+// slash-separated relative path. A missing dir yields no files and false.
+func readRoleRepoInstalledFiles(dir string) (map[string][]byte, bool, error) {
+	files := map[string][]byte{}
+	if st, err := os.Stat(dir); err != nil || !st.IsDir() {
+		if err != nil && !os.IsNotExist(err) {
+			return nil, false, err
+		}
+		return files, false, nil
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return nil, true, fmt.Errorf("read installed role %s: %w", filepath.Base(dir), err)
+	}
+	return files, true, nil
+}
+
+// fetchRoleRepoFiles downloads a remote role's files keyed by their path
+// relative to the role folder, reusing blobs already fetched.
+func fetchRoleRepoFiles(ctx context.Context, provider RoleRepoProvider, role RoleRepoRemoteRole, blobs map[string][]byte) (map[string][]byte, error) {
+	prefix := role.Candidate.RolePath + "/"
+	files := map[string][]byte{}
+	for _, f := range role.Files {
+		if f.Type != "blob" || !strings.HasPrefix(f.Path, prefix) {
+			continue
+		}
+		data, ok := blobs[f.SHA]
+		if !ok {
+			var err error
+			if data, err = provider.FetchBlob(ctx, role.Candidate.Source, f.SHA); err != nil {
+				return nil, err
+			}
+			blobs[f.SHA] = data
+		}
+		files[strings.TrimPrefix(f.Path, prefix)] = data
+	}
+	return files, nil
+}
@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoleRepoDiffAndMergeOnUpdate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "--quiet", "-b", "main")
+	writeRoleRepoFixture(t, repo, "name: frontend\ndescription: ui\nmode: worker\nmodel: small\n")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "v1")
+
+	cacheDir := t.TempDir()
+	newProviders := func() *RoleRepoProviderSet {
+		providers := NewRoleRepoProviderSet("", nil)
+		providers.CacheDir = cacheDir
+		return providers
+	}
+	source, err := ParseRoleRepoSource("git+file://" + filepath.ToSlash(repo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	providers := newProviders()
+	provider, err := providers.ForSource(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roles, err := ResolveRoleRepoRoles(context.Background(), provider, source)
+	if err != nil || len(roles) != 1 {
+		t.Fatalf("ResolveRoleRepoRoles: %v %+v", err, roles)
+	}
+	installRoot := filepath.Join(t.TempDir(), "teams")
+	if _, err := InstallRoleRepoRemoteRole(context.Background(), provider, roles[0], installRoot, false); err != nil {
+		t.Fatal(err)
+	}
+	entry := RoleRepoLockEntry{
+		Name:       "frontend",
+		Source:     providers.LockSource(source),
+		SourceType: RoleRepoSourceGit,
+		RolePath:   roles[0].Candidate.RolePath,
+		Commit:     roles[0].Commit,
+		FolderHash: roles[0].FolderHash,
+	}
+	lock := RoleRepoLockFile{Version: 1, Entries: []RoleRepoLockEntry{entry}}
+
+	diff, err := DiffRoleRepoRole(context.Background(), newProviders(), installRoot, entry)
+	if err != nil {
+		t.Fatalf("DiffRoleRepoRole: %v", err)
+	}
+	if diff.LocalModified || !diff.BaseKnown {
+		t.Fatalf("fresh install should be unmodified: %+v", diff)
+	}
+	for _, f := range diff.Files {
+		if f.Changed() {
+			t.Fatalf("unexpected change in %s", f.Path)
+		}
+	}
+
+	// Upstream changes the model, the installed copy the description.
+	writeRoleRepoFixture(t, repo, "name: frontend\ndescription: ui\nmode: worker\nmodel: large\n")
+	git("commit", "--quiet", "-am", "v2")
+	roleYAML := filepath.Join(installRoot, "frontend", "references", "role.yaml")
+	if err := os.WriteFile(roleYAML, []byte("name: frontend\ndescription: web ui\nmode: worker\nmodel: small\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err = DiffRoleRepoRole(context.Background(), newProviders(), installRoot, entry)
+	if err != nil {
+		t.Fatalf("DiffRoleRepoRole: %v", err)
+	}
+	if !diff.LocalModified || !diff.BaseKnown {
+		t.Fatalf("expected known local modifications: %+v", diff)
+	}
+	if got := diff.ModifiedFiles(); len(got) != 1 || got[0] != "references/role.yaml" {
+		t.Fatalf("ModifiedFiles = %v", got)
+	}
+	statuses, _ := CheckRoleRepoUpdates(context.Background(), newProviders(), installRoot, lock)
+	if len(statuses) != 1 || !statuses[0].LocalModified || !strings.Contains(FormatRoleRepoCheckSummary(statuses, nil), "[local changes]") {
+		t.Fatalf("check should flag local changes: %+v", statuses)
+	}
+
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	updated, _, failed := UpdateRoleRepoFromLock(context.Background(), newProviders(), installRoot, &lock, true, nil, "", false, func() time.Time { return now })
+	if len(updated) != 1 || len(failed) != 0 {
+		t.Fatalf("merge update: updated=%v failed=%v", updated, failed)
+	}
+	if data, _ := os.ReadFile(roleYAML); string(data) != "name: frontend\ndescription: web ui\nmode: worker\nmodel: large\n" {
+		t.Fatalf("merged role.yaml = %q", data)
+	}
+
+	// Both sides now edit the same line: the update refuses unless forced.
+	writeRoleRepoFixture(t, repo, "name: frontend\ndescription: ui\nmode: worker\nmodel: huge\n")
+	git("commit", "--quiet", "-am", "v3")
+	if err := os.WriteFile(roleYAML, []byte("name: frontend\ndescription: web ui\nmode: worker\nmodel: tiny\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, failed = UpdateRoleRepoFromLock(context.Background(), newProviders(), installRoot, &lock, true, nil, "", false, func() time.Time { return now })
+	if !errors.Is(failed["frontend"], ErrRoleRepoLocalChanges) || !strings.Contains(failed["frontend"].Error(), "references/role.yaml") {
+		t.Fatalf("expected conflict error, got %v", failed)
+	}
+	if data, _ := os.ReadFile(roleYAML); !strings.Contains(string(data), "model: tiny") {
+		t.Fatalf("conflicting update must leave the install alone, got %q", data)
+	}
+
+	updated, _, failed = UpdateRoleRepoFromLock(context.Background(), newProviders(), installRoot, &lock, true, nil, "", true, func() time.Time { return now })
+	if len(updated) != 1 || len(failed) != 0 {
+		t.Fatalf("forced update: updated=%v failed=%v", updated, failed)
+	}
+	if data, _ := os.ReadFile(roleYAML); string(data) != "name: frontend\ndescription: ui\nmode: worker\nmodel: huge\n" {
+		t.Fatalf("forced role.yaml = %q", data)
+	}
+}
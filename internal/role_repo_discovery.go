@@ -2,73 +2,68 @@ package internal
 
 import (
 	"context"
-	"sort"
-	"strings"
+	"fmt"
+	"net/http"
+	"net/url"
 )
 
 // RoleRepoRemoteRole bundles candidate metadata with tree files/hash for install/update flows.
+// Commit is the resolved commit the tree was read at; it is empty for local
+// sources and for listings of the default branch by name.
 type RoleRepoRemoteRole struct {
 	Candidate  RoleRepoCandidate
-	Files      []roleRepoTreeEntry
+	Files      []RoleRepoTreeEntry
 	FolderHash string
+	Commit     string
+}
+
+func (c *RoleRepoGitHubClient) SourceType() string {
+	return RoleRepoSourceGitHub
 }
 
 func (c *RoleRepoGitHubClient) DiscoverRemoteRoles(ctx context.Context, source RoleRepoSource) ([]RoleRepoRemoteRole, error) {
-	defaultBranch, err := c.getDefaultBranch(ctx, source)
-	if err != nil {
-		return nil, err
-	}
-	tree, err := c.getRepoTree(ctx, source, defaultBranch)
+	tree, err := c.FetchTree(ctx, source, "")
 	if err != nil {
 		return nil, err
 	}
+	return discoverRoleRepoRoles(c, source, tree), nil
+}
 
-	roleByPath := map[string]*RoleRepoRemoteRole{}
-	for _, e := range tree {
-		if e.Type != "blob" {
-			continue
-		}
-		roleName, rolePath, ok := ParseRolePathFromYAMLPath(e.Path)
-		if !ok {
-			continue
+func (c *RoleRepoGitHubClient) ResolveRef(ctx context.Context, source RoleRepoSource, ref string) (string, error) {
+	if ref == "" {
+		defaultBranch, err := c.getDefaultBranch(ctx, source)
+		if err != nil {
+			return "", err
 		}
-		candidate := RoleRepoCandidate{
-			Name:       roleName,
-			RolePath:   rolePath,
-			YAMLPath:   e.Path,
-			Source:     source,
-			SourceType: "github",
-			SourceURL:  source.HTTPSURL(),
-		}
-		roleByPath[rolePath] = &RoleRepoRemoteRole{Candidate: candidate}
+		ref = defaultBranch
 	}
-
-	if len(roleByPath) == 0 {
-		return []RoleRepoRemoteRole{}, nil
+	var payload struct {
+		SHA string `json:"sha"`
 	}
+	if err := c.doJSON(ctx, http.MethodGet, "/repos/"+source.FullName()+"/commits/"+url.PathEscape(ref), &payload); err != nil {
+		return "", err
+	}
+	if payload.SHA == "" {
+		return "", fmt.Errorf("ref %q of %s has no commit", ref, source.FullName())
+	}
+	return payload.SHA, nil
+}
 
-	for _, e := range tree {
-		if e.Type != "blob" {
-			continue
-		}
-		for rolePath, role := range roleByPath {
-			prefix := rolePath + "/"
-			if strings.HasPrefix(e.Path, prefix) {
-				role.Files = append(role.Files, e)
-			}
+func (c *RoleRepoGitHubClient) FetchTree(ctx context.Context, source RoleRepoSource, ref string) ([]RoleRepoTreeEntry, error) {
+	if ref == "" {
+		defaultBranch, err := c.getDefaultBranch(ctx, source)
+		if err != nil {
+			return nil, err
 		}
+		ref = defaultBranch
 	}
+	return c.getRepoTree(ctx, source, ref)
+}
 
-	roles := make([]RoleRepoRemoteRole, 0, len(roleByPath))
-	for _, role := range roleByPath {
-		role.FolderHash = hashRoleRepoTreeFiles(role.Files)
-		sort.Slice(role.Files, func(i, j int) bool {
-			return role.Files[i].Path < role.Files[j].Path
-		})
-		roles = append(roles, *role)
-	}
-	sort.Slice(roles, func(i, j int) bool {
-		return roles[i].Candidate.Name < roles[j].Candidate.Name
-	})
-	return roles, nil
+func (c *RoleRepoGitHubClient) FetchBlob(ctx context.Context, source RoleRepoSource, sha string) ([]byte, error) {
+	return c.getBlobContent(ctx, source, sha)
+}
+
+func (c *RoleRepoGitHubClient) FolderHash(files []RoleRepoTreeEntry) string {
+	return hashRoleRepoTreeFiles(files)
 }
@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// RoleRepoGitProvider serves roles from any git remote through a shallow
+// clone kept per remote in a cache directory and reused across runs. Runs
+// fetch the refs they need into the clone while holding its lock, so
+// concurrent runs never clone or fetch into the same directory at once;
+// trees and blobs are then read by SHA, which needs no lock.
+type RoleRepoGitProvider struct {
+	cacheDir string
+	clones   map[string]string
+	commits  map[string]string
+}
+
+// NewRoleRepoGitProvider caches clones in cacheDir, defaulting to
+// <user cache dir>/agent-team/role-repos.
+func NewRoleRepoGitProvider(cacheDir string) *RoleRepoGitProvider {
+	if cacheDir == "" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			cacheDir = filepath.Join(dir, "agent-team", "role-repos")
+		} else {
+			cacheDir = filepath.Join(os.TempDir(), "agent-team-role-repos")
+		}
+	}
+	return &RoleRepoGitProvider{cacheDir: cacheDir, clones: map[string]string{}, commits: map[string]string{}}
+}
+
+func (p *RoleRepoGitProvider) SourceType() string {
+	return RoleRepoSourceGit
+}
+
+func (p *RoleRepoGitProvider) DiscoverRemoteRoles(ctx context.Context, source RoleRepoSource) ([]RoleRepoRemoteRole, error) {
+	tree, err := p.FetchTree(ctx, source, "")
+	if err != nil {
+		return nil, err
+	}
+	return discoverRoleRepoRoles(p, source, tree), nil
+}
+
+func (p *RoleRepoGitProvider) ResolveRef(ctx context.Context, source RoleRepoSource, ref string) (string, error) {
+	dir, err := p.clone(ctx, source)
+	if err != nil {
+		return "", err
+	}
+	return p.resolve(ctx, source, dir, ref)
+}
+
+func (p *RoleRepoGitProvider) FetchTree(ctx context.Context, source RoleRepoSource, ref string) ([]RoleRepoTreeEntry, error) {
+	dir, err := p.clone(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := p.resolve(ctx, source, dir, ref)
+	if err != nil {
+		return nil, err
+	}
+	out, err := runRoleRepoGit(ctx, dir, "ls-tree", "-r", "--full-tree", "--end-of-options", commit)
+	if err != nil {
+		return nil, err
+	}
+	var tree []RoleRepoTreeEntry
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		// <mode> SP <type> SP <sha> TAB <path>
+		meta, path, ok := strings.Cut(scanner.Text(), "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 3 {
+			continue
+		}
+		tree = append(tree, RoleRepoTreeEntry{Path: path, Type: fields[1], SHA: fields[2]})
+	}
+	return tree, nil
+}
+
+func (p *RoleRepoGitProvider) FetchBlob(ctx context.Context, source RoleRepoSource, sha string) ([]byte, error) {
+	dir, err := p.clone(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	return runRoleRepoGit(ctx, dir, "cat-file", "blob", "--end-of-options", sha)
+}
+
+func (p *RoleRepoGitProvider) FolderHash(files []RoleRepoTreeEntry) string {
+	return hashRoleRepoTreeFiles(files)
+}
+
+// clone returns the cached clone of source's remote, cloning it on first
+// use or when the cached directory is not a clone of that remote.
+func (p *RoleRepoGitProvider) clone(ctx context.Context, source RoleRepoSource) (string, error) {
+	if dir, ok := p.clones[source.CloneURL]; ok {
+		return dir, nil
+	}
+	if source.CloneURL == "" || strings.HasPrefix(source.CloneURL, "-") {
+		return "", fmt.Errorf("invalid git remote %q", source.CloneURL)
+	}
+	dir := filepath.Join(p.cacheDir, roleRepoSHA256Hex(source.CloneURL)[:16])
+	err := WithStateLock(dir, func() error {
+		if out, err := runRoleRepoGit(ctx, dir, "config", "--get", "remote.origin.url"); err == nil && strings.TrimSpace(string(out)) == source.CloneURL {
+			if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+				return nil
+			}
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("remove stale clone of %s: %w", source.CloneURL, err)
+		}
+		if _, err := runRoleRepoGit(ctx, "", "clone", "--depth", "1", "--quiet", "--no-checkout", "--end-of-options", source.CloneURL, dir); err != nil {
+			os.RemoveAll(dir)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	p.clones[source.CloneURL] = dir
+	return dir, nil
+}
+
+// resolve returns the commit for ref. A full commit SHA already in the clone
+// is used as is; anything else, including the default branch for an empty
+// ref, is fetched from the remote so a reused clone never serves stale
+// branches or tags. Results are remembered for the life of the provider.
+func (p *RoleRepoGitProvider) resolve(ctx context.Context, source RoleRepoSource, dir, ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	} else if err := ValidateRoleRepoRef(ref); err != nil {
+		return "", err
+	}
+	key := source.CloneURL + "\x00" + ref
+	if commit, ok := p.commits[key]; ok {
+		return commit, nil
+	}
+	if len(ref) == 40 || len(ref) == 64 {
+		if out, err := runRoleRepoGit(ctx, dir, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}"); err == nil && strings.EqualFold(strings.TrimSpace(string(out)), ref) {
+			p.commits[key] = strings.TrimSpace(string(out))
+			return p.commits[key], nil
+		}
+	}
+	// FETCH_HEAD is shared by every run using the clone; read it before
+	// releasing the lock. Auto gc stays off so commits other runs resolved
+	// are not pruned from under them.
+	var commit string
+	err := WithStateLock(dir, func() error {
+		if _, err := runRoleRepoGit(ctx, dir, "fetch", "--depth", "1", "--quiet", "--no-auto-gc", "--end-of-options", "origin", ref); err != nil {
+			return fmt.Errorf("ref %q not found: %w", ref, err)
+		}
+		out, err := runRoleRepoGit(ctx, dir, "rev-parse", "--verify", "FETCH_HEAD^{commit}")
+		if err != nil {
+			return err
+		}
+		commit = strings.TrimSpace(string(out))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	p.commits[key] = commit
+	return commit, nil
+}
+
+func runRoleRepoGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	subcommand := args[0]
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("git %s: %s", subcommand, msg)
+	}
+	return out, nil
+}
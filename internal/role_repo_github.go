@@ -110,24 +110,24 @@ func (c *RoleRepoGitHubClient) getDefaultBranch(ctx context.Context, source Role
 	return payload.DefaultBranch, nil
 }
 
-type roleRepoTreeEntry struct {
+type RoleRepoTreeEntry struct {
 	Path string `json:"path"`
 	Type string `json:"type"`
 	SHA  string `json:"sha"`
 }
 
-func (c *RoleRepoGitHubClient) getRepoTree(ctx context.Context, source RoleRepoSource, ref string) ([]roleRepoTreeEntry, error) {
+func (c *RoleRepoGitHubClient) getRepoTree(ctx context.Context, source RoleRepoSource, ref string) ([]RoleRepoTreeEntry, error) {
 	q := url.Values{}
 	q.Set("recursive", "1")
 	path := fmt.Sprintf("/repos/%s/git/trees/%s?%s", source.FullName(), url.PathEscape(ref), q.Encode())
 	var payload struct {
-		Tree []roleRepoTreeEntry `json:"tree"`
+		Tree []RoleRepoTreeEntry `json:"tree"`
 	}
 	if err := c.doJSON(ctx, http.MethodGet, path, &payload); err != nil {
 		return nil, err
 	}
 	if payload.Tree == nil {
-		payload.Tree = []roleRepoTreeEntry{}
+		payload.Tree = []RoleRepoTreeEntry{}
 	}
 	return payload.Tree, nil
 }
@@ -152,7 +152,7 @@ func (c *RoleRepoGitHubClient) getBlobContent(ctx context.Context, source RoleRe
 	return decoded, nil
 }
 
-func hashRoleRepoTreeFiles(files []roleRepoTreeEntry) string {
+func hashRoleRepoTreeFiles(files []RoleRepoTreeEntry) string {
 	lines := make([]string, 0, len(files))
 	for _, f := range files {
 		if f.Type != "blob" {
@@ -20,7 +20,7 @@ type mockSearchItem struct {
 	RepoHTML string
 }
 
-func newRoleRepoMockGitHubServer(t *testing.T, defaultBranch string, tree []roleRepoTreeEntry, blobs map[string]string, searchItems []mockSearchItem) *httptest.Server {
+func newRoleRepoMockGitHubServer(t *testing.T, defaultBranch string, tree []RoleRepoTreeEntry, blobs map[string]string, searchItems []mockSearchItem) *httptest.Server {
 	t.Helper()
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -83,7 +83,7 @@ func TestRoleRepoSearchFiltersAndDeduplicates(t *testing.T) {
 }
 
 func TestRoleRepoDiscoverRemoteRolesStrictPathContracts(t *testing.T) {
-	tree := []roleRepoTreeEntry{
+	tree := []RoleRepoTreeEntry{
 		{Path: "skills/frontend/references/role.yaml", Type: "blob", SHA: "sha-role-1"},
 		{Path: "skills/frontend/SKILL.md", Type: "blob", SHA: "sha-skill-1"},
 		{Path: ".agents/teams/backend/references/role.yaml", Type: "blob", SHA: "sha-role-2"},
@@ -113,23 +113,26 @@ func TestRoleRepoDiscoverRemoteRolesStrictPathContracts(t *testing.T) {
 }
 
 func TestInstallRoleRepoRemoteRoleAndCheckUpdate(t *testing.T) {
-	treeV1 := []roleRepoTreeEntry{
-		{Path: "skills/frontend/references/role.yaml", Type: "blob", SHA: "sha-role-v1"},
-		{Path: "skills/frontend/SKILL.md", Type: "blob", SHA: "sha-skill-v1"},
+	treeV1 := []RoleRepoTreeEntry{
+		{Path: "skills/frontend/references/role.yaml", Type: "blob", SHA: gitBlobSHA([]byte("name: frontend\n"))},
+		{Path: "skills/frontend/SKILL.md", Type: "blob", SHA: gitBlobSHA([]byte("# frontend\n"))},
 	}
 	blobsV1 := map[string]string{
-		"sha-role-v1":  "name: frontend\n",
-		"sha-skill-v1": "# frontend\n",
+		gitBlobSHA([]byte("name: frontend\n")): "name: frontend\n",
+		gitBlobSHA([]byte("# frontend\n")):     "# frontend\n",
 	}
 
 	// Dynamic tree/blob state to simulate remote update.
 	currentTree := treeV1
 	currentBlobs := blobsV1
+	currentCommit := "commit-v1"
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		switch {
 		case r.URL.Path == "/repos/acme/roles":
 			_ = json.NewEncoder(w).Encode(map[string]any{"default_branch": "main"})
+		case strings.HasPrefix(r.URL.Path, "/repos/acme/roles/commits/"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"sha": currentCommit})
 		case strings.HasPrefix(r.URL.Path, "/repos/acme/roles/git/trees/"):
 			_ = json.NewEncoder(w).Encode(map[string]any{"tree": currentTree})
 		case strings.HasPrefix(r.URL.Path, "/repos/acme/roles/git/blobs/"):
@@ -186,16 +189,17 @@ func TestInstallRoleRepoRemoteRoleAndCheckUpdate(t *testing.T) {
 	}
 
 	// mutate remote role
-	currentTree = []roleRepoTreeEntry{
-		{Path: "skills/frontend/references/role.yaml", Type: "blob", SHA: "sha-role-v2"},
-		{Path: "skills/frontend/SKILL.md", Type: "blob", SHA: "sha-skill-v2"},
+	currentTree = []RoleRepoTreeEntry{
+		{Path: "skills/frontend/references/role.yaml", Type: "blob", SHA: gitBlobSHA([]byte("name: frontend\nversion: 2\n"))},
+		{Path: "skills/frontend/SKILL.md", Type: "blob", SHA: gitBlobSHA([]byte("# frontend v2\n"))},
 	}
 	currentBlobs = map[string]string{
-		"sha-role-v2":  "name: frontend\nversion: 2\n",
-		"sha-skill-v2": "# frontend v2\n",
+		gitBlobSHA([]byte("name: frontend\nversion: 2\n")): "name: frontend\nversion: 2\n",
+		gitBlobSHA([]byte("# frontend v2\n")):              "# frontend v2\n",
 	}
+	currentCommit = "commit-v2"
 
-	statuses, untracked := CheckRoleRepoUpdates(context.Background(), client, installRoot, lock)
+	statuses, untracked := CheckRoleRepoUpdates(context.Background(), NewRoleRepoProviderSet("", client), installRoot, lock)
 	if len(statuses) != 1 || statuses[0].State != "update_available" {
 		t.Fatalf("unexpected check statuses: %+v", statuses)
 	}
@@ -203,18 +207,21 @@ func TestInstallRoleRepoRemoteRoleAndCheckUpdate(t *testing.T) {
 		t.Fatalf("unexpected untracked: %+v", untracked)
 	}
 
-	updated, skipped, failed := UpdateRoleRepoFromLock(context.Background(), client, installRoot, &lock, false, nil, nowFn)
+	updated, skipped, failed := UpdateRoleRepoFromLock(context.Background(), NewRoleRepoProviderSet("", client), installRoot, &lock, false, nil, "", false, nowFn)
 	if len(updated) != 0 || len(skipped) != 1 || len(failed) != 0 {
 		t.Fatalf("expected skip without overwrite, got updated=%v skipped=%v failed=%v", updated, skipped, failed)
 	}
 
-	updated, skipped, failed = UpdateRoleRepoFromLock(context.Background(), client, installRoot, &lock, true, nil, nowFn)
+	updated, skipped, failed = UpdateRoleRepoFromLock(context.Background(), NewRoleRepoProviderSet("", client), installRoot, &lock, true, nil, "", false, nowFn)
 	if len(updated) != 1 || updated[0] != "frontend" || len(failed) != 0 {
 		t.Fatalf("unexpected update result: updated=%v skipped=%v failed=%v", updated, skipped, failed)
 	}
 	if lock.Entries[0].FolderHash == roles[0].FolderHash {
 		t.Fatalf("expected updated folder hash, got unchanged %s", lock.Entries[0].FolderHash)
 	}
+	if lock.Entries[0].Commit != "commit-v2" {
+		t.Fatalf("lock commit = %q, want commit-v2", lock.Entries[0].Commit)
+	}
 
 	data, err := os.ReadFile(filepath.Join(installRoot, "frontend", "references", "role.yaml"))
 	if err != nil {
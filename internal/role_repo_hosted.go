@@ -0,0 +1,352 @@
+package internal
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// RoleRepoAPIError is a non-2xx response from a GitLab or Gitea API.
+type RoleRepoAPIError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *RoleRepoAPIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s api error (%d)", e.Provider, e.StatusCode)
+	}
+	return fmt.Sprintf("%s api error (%d): %s", e.Provider, e.StatusCode, e.Message)
+}
+
+func (e *RoleRepoAPIError) IsAuthOrRateLimit() bool {
+	return e.StatusCode == 401 || e.StatusCode == 403 || e.StatusCode == 429
+}
+
+// roleRepoAPIGet issues a GET with the given auth header and decodes a JSON
+// body into out, or returns the raw body when out is nil.
+func roleRepoAPIGet(ctx context.Context, httpClient *http.Client, provider, fullURL, authHeader, authValue string, out any) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "agent-team-role-repo")
+	if authValue != "" {
+		req.Header.Set(authHeader, authValue)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		msg := strings.TrimSpace(string(body))
+		if strings.HasPrefix(msg, "{") {
+			var payload struct {
+				Message any `json:"message"`
+			}
+			if json.Unmarshal(body, &payload) == nil && payload.Message != nil {
+				msg = fmt.Sprint(payload.Message)
+			}
+		}
+		return nil, resp.Header, &RoleRepoAPIError{Provider: provider, StatusCode: resp.StatusCode, Message: msg}
+	}
+	if out == nil {
+		body, err := io.ReadAll(resp.Body)
+		return body, resp.Header, err
+	}
+	return nil, resp.Header, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// roleRepoTokenHosts reads a comma-separated host list (host, host:port or
+// an https URL) from env, falling back to defaultHost.
+func roleRepoTokenHosts(env, defaultHost string) []string {
+	raw := os.Getenv(env)
+	if strings.TrimSpace(raw) == "" {
+		raw = defaultHost
+	}
+	var hosts []string
+	for _, host := range strings.Split(raw, ",") {
+		host = strings.TrimSpace(host)
+		if u, err := url.Parse(host); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		if host = strings.ToLower(strings.TrimSuffix(host, "/")); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// roleRepoTokenFor returns token when baseURL may receive it: an https URL
+// on one of hosts. Sources come from the command line and from committed
+// lock files, so a token is never sent to a host the user did not name.
+func roleRepoTokenFor(token, baseURL string, hosts []string) string {
+	if token == "" {
+		return ""
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme != "https" {
+		return ""
+	}
+	for _, host := range hosts {
+		if strings.EqualFold(u.Host, host) {
+			return token
+		}
+	}
+	return ""
+}
+
+// RoleRepoGiteaClient reads roles through the Gitea (and Forgejo) v1 API.
+// GITEA_TOKEN is sent over https to the hosts listed in GITEA_HOST only.
+type RoleRepoGiteaClient struct {
+	httpClient *http.Client
+	token      string
+	tokenHosts []string
+}
+
+func NewRoleRepoGiteaClient(httpClient *http.Client) *RoleRepoGiteaClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &RoleRepoGiteaClient{
+		httpClient: httpClient,
+		token:      strings.TrimSpace(os.Getenv("GITEA_TOKEN")),
+		tokenHosts: roleRepoTokenHosts("GITEA_HOST", ""),
+	}
+}
+
+func (c *RoleRepoGiteaClient) SourceType() string {
+	return RoleRepoSourceGitea
+}
+
+func (c *RoleRepoGiteaClient) get(ctx context.Context, source RoleRepoSource, requestPath string, out any) ([]byte, error) {
+	auth := ""
+	if token := roleRepoTokenFor(c.token, source.BaseURL, c.tokenHosts); token != "" {
+		auth = "token " + token
+	}
+	fullURL := fmt.Sprintf("%s/api/v1/repos/%s/%s%s", source.BaseURL, url.PathEscape(source.Owner), url.PathEscape(source.Repo), requestPath)
+	body, _, err := roleRepoAPIGet(ctx, c.httpClient, "gitea", fullURL, "Authorization", auth, out)
+	return body, err
+}
+
+func (c *RoleRepoGiteaClient) DiscoverRemoteRoles(ctx context.Context, source RoleRepoSource) ([]RoleRepoRemoteRole, error) {
+	tree, err := c.FetchTree(ctx, source, "")
+	if err != nil {
+		return nil, err
+	}
+	return discoverRoleRepoRoles(c, source, tree), nil
+}
+
+func (c *RoleRepoGiteaClient) defaultBranch(ctx context.Context, source RoleRepoSource) (string, error) {
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if _, err := c.get(ctx, source, "", &repo); err != nil {
+		return "", err
+	}
+	if repo.DefaultBranch == "" {
+		return "", fmt.Errorf("repository %s has no default branch", source.FullName())
+	}
+	return repo.DefaultBranch, nil
+}
+
+func (c *RoleRepoGiteaClient) ResolveRef(ctx context.Context, source RoleRepoSource, ref string) (string, error) {
+	if ref == "" {
+		defaultBranch, err := c.defaultBranch(ctx, source)
+		if err != nil {
+			return "", err
+		}
+		ref = defaultBranch
+	}
+	q := url.Values{}
+	q.Set("sha", ref)
+	q.Set("limit", "1")
+	q.Set("stat", "false")
+	var commits []struct {
+		SHA string `json:"sha"`
+	}
+	if _, err := c.get(ctx, source, "/commits?"+q.Encode(), &commits); err != nil {
+		return "", err
+	}
+	if len(commits) == 0 || commits[0].SHA == "" {
+		return "", fmt.Errorf("ref %q of %s has no commit", ref, source.FullName())
+	}
+	return commits[0].SHA, nil
+}
+
+func (c *RoleRepoGiteaClient) FetchTree(ctx context.Context, source RoleRepoSource, ref string) ([]RoleRepoTreeEntry, error) {
+	if ref == "" {
+		defaultBranch, err := c.defaultBranch(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		ref = defaultBranch
+	}
+
+	tree := []RoleRepoTreeEntry{}
+	for page := 1; ; page++ {
+		q := url.Values{}
+		q.Set("recursive", "true")
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", "1000")
+		var payload struct {
+			Tree      []RoleRepoTreeEntry `json:"tree"`
+			Truncated bool                `json:"truncated"`
+		}
+		if _, err := c.get(ctx, source, "/git/trees/"+url.PathEscape(ref)+"?"+q.Encode(), &payload); err != nil {
+			return nil, err
+		}
+		tree = append(tree, payload.Tree...)
+		if !payload.Truncated || len(payload.Tree) == 0 {
+			return tree, nil
+		}
+	}
+}
+
+func (c *RoleRepoGiteaClient) FetchBlob(ctx context.Context, source RoleRepoSource, sha string) ([]byte, error) {
+	var payload struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if _, err := c.get(ctx, source, "/git/blobs/"+url.PathEscape(sha), &payload); err != nil {
+		return nil, err
+	}
+	if payload.Encoding != "base64" {
+		return nil, fmt.Errorf("unsupported blob encoding: %s", payload.Encoding)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(payload.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("decode blob %s: %w", sha, err)
+	}
+	return decoded, nil
+}
+
+func (c *RoleRepoGiteaClient) FolderHash(files []RoleRepoTreeEntry) string {
+	return hashRoleRepoTreeFiles(files)
+}
+
+// RoleRepoGitLabClient reads roles through the GitLab v4 API, including
+// projects in nested groups. GITLAB_TOKEN is sent over https to gitlab.com,
+// or to the hosts listed in GITLAB_HOST when set.
+type RoleRepoGitLabClient struct {
+	httpClient *http.Client
+	token      string
+	tokenHosts []string
+}
+
+func NewRoleRepoGitLabClient(httpClient *http.Client) *RoleRepoGitLabClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &RoleRepoGitLabClient{
+		httpClient: httpClient,
+		token:      strings.TrimSpace(os.Getenv("GITLAB_TOKEN")),
+		tokenHosts: roleRepoTokenHosts("GITLAB_HOST", "gitlab.com"),
+	}
+}
+
+func (c *RoleRepoGitLabClient) SourceType() string {
+	return RoleRepoSourceGitLab
+}
+
+func (c *RoleRepoGitLabClient) get(ctx context.Context, source RoleRepoSource, requestPath string, out any) ([]byte, http.Header, error) {
+	projectID := url.PathEscape(source.Owner + "/" + source.Repo)
+	fullURL := fmt.Sprintf("%s/api/v4/projects/%s%s", source.BaseURL, projectID, requestPath)
+	return roleRepoAPIGet(ctx, c.httpClient, "gitlab", fullURL, "PRIVATE-TOKEN", roleRepoTokenFor(c.token, source.BaseURL, c.tokenHosts), out)
+}
+
+func (c *RoleRepoGitLabClient) DiscoverRemoteRoles(ctx context.Context, source RoleRepoSource) ([]RoleRepoRemoteRole, error) {
+	tree, err := c.FetchTree(ctx, source, "")
+	if err != nil {
+		return nil, err
+	}
+	return discoverRoleRepoRoles(c, source, tree), nil
+}
+
+func (c *RoleRepoGitLabClient) defaultBranch(ctx context.Context, source RoleRepoSource) (string, error) {
+	var project struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if _, _, err := c.get(ctx, source, "", &project); err != nil {
+		return "", err
+	}
+	if project.DefaultBranch == "" {
+		return "", fmt.Errorf("repository %s has no default branch", source.FullName())
+	}
+	return project.DefaultBranch, nil
+}
+
+func (c *RoleRepoGitLabClient) ResolveRef(ctx context.Context, source RoleRepoSource, ref string) (string, error) {
+	if ref == "" {
+		defaultBranch, err := c.defaultBranch(ctx, source)
+		if err != nil {
+			return "", err
+		}
+		ref = defaultBranch
+	}
+	var commit struct {
+		ID string `json:"id"`
+	}
+	if _, _, err := c.get(ctx, source, "/repository/commits/"+url.PathEscape(ref), &commit); err != nil {
+		return "", err
+	}
+	if commit.ID == "" {
+		return "", fmt.Errorf("ref %q of %s has no commit", ref, source.FullName())
+	}
+	return commit.ID, nil
+}
+
+func (c *RoleRepoGitLabClient) FetchTree(ctx context.Context, source RoleRepoSource, ref string) ([]RoleRepoTreeEntry, error) {
+	if ref == "" {
+		defaultBranch, err := c.defaultBranch(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		ref = defaultBranch
+	}
+
+	tree := []RoleRepoTreeEntry{}
+	page := "1"
+	for page != "" {
+		q := url.Values{}
+		q.Set("recursive", "true")
+		q.Set("ref", ref)
+		q.Set("per_page", "100")
+		q.Set("page", page)
+		var payload []struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+			Path string `json:"path"`
+		}
+		_, header, err := c.get(ctx, source, "/repository/tree?"+q.Encode(), &payload)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range payload {
+			tree = append(tree, RoleRepoTreeEntry{Path: e.Path, Type: e.Type, SHA: e.ID})
+		}
+		page = strings.TrimSpace(header.Get("X-Next-Page"))
+	}
+	return tree, nil
+}
+
+func (c *RoleRepoGitLabClient) FetchBlob(ctx context.Context, source RoleRepoSource, sha string) ([]byte, error) {
+	body, _, err := c.get(ctx, source, "/repository/blobs/"+url.PathEscape(sha)+"/raw", nil)
+	return body, err
+}
+
+func (c *RoleRepoGitLabClient) FolderHash(files []RoleRepoTreeEntry) string {
+	return hashRoleRepoTreeFiles(files)
+}
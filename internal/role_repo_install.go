@@ -31,7 +31,7 @@ func SelectRoleRepoRemotes(all []RoleRepoRemoteRole, roleNames []string) ([]Role
 	return selected, nil
 }
 
-func InstallRoleRepoRemoteRole(ctx context.Context, client *RoleRepoGitHubClient, remote RoleRepoRemoteRole, installRoot string, overwrite bool) (string, error) {
+func InstallRoleRepoRemoteRole(ctx context.Context, provider RoleRepoProvider, remote RoleRepoRemoteRole, installRoot string, overwrite bool) (string, error) {
 	targetDir := filepath.Join(installRoot, remote.Candidate.Name)
 	if st, err := os.Stat(targetDir); err == nil && st.IsDir() {
 		if !overwrite {
@@ -43,7 +43,7 @@ func InstallRoleRepoRemoteRole(ctx context.Context, client *RoleRepoGitHubClient
 		if err := os.MkdirAll(targetDir, 0755); err != nil {
 			return "", err
 		}
-		if err := writeRoleRepoFiles(ctx, client, remote, targetDir); err != nil {
+		if err := writeRoleRepoFiles(ctx, provider, remote, targetDir); err != nil {
 			return "", err
 		}
 		return targetDir, nil
@@ -52,15 +52,15 @@ func InstallRoleRepoRemoteRole(ctx context.Context, client *RoleRepoGitHubClient
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
 		return "", err
 	}
-	if err := writeRoleRepoFiles(ctx, client, remote, targetDir); err != nil {
+	if err := writeRoleRepoFiles(ctx, provider, remote, targetDir); err != nil {
 		return "", err
 	}
 	return targetDir, nil
 }
 
-func writeRoleRepoFiles(ctx context.Context, client *RoleRepoGitHubClient, remote RoleRepoRemoteRole, targetDir string) error {
+func writeRoleRepoFiles(ctx context.Context, provider RoleRepoProvider, remote RoleRepoRemoteRole, targetDir string) error {
 	prefix := remote.Candidate.RolePath + "/"
-	files := append([]roleRepoTreeEntry(nil), remote.Files...)
+	files := append([]RoleRepoTreeEntry(nil), remote.Files...)
 	sort.Slice(files, func(i, j int) bool {
 		return files[i].Path < files[j].Path
 	})
@@ -75,7 +75,7 @@ func writeRoleRepoFiles(ctx context.Context, client *RoleRepoGitHubClient, remot
 		if rel == "" || strings.Contains(rel, "..") {
 			return fmt.Errorf("invalid role file path: %s", file.Path)
 		}
-		data, err := client.getBlobContent(ctx, remote.Candidate.Source, file.SHA)
+		data, err := provider.FetchBlob(ctx, remote.Candidate.Source, file.SHA)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// RoleRepoLocalProvider serves roles from a directory on disk, such as a
+// folder in a monorepo. Refs are not supported: the working tree is read.
+type RoleRepoLocalProvider struct {
+	// blobs maps source path + blob SHA seen by FetchTree to file paths.
+	blobs map[string]string
+}
+
+func NewRoleRepoLocalProvider() *RoleRepoLocalProvider {
+	return &RoleRepoLocalProvider{blobs: map[string]string{}}
+}
+
+func (p *RoleRepoLocalProvider) SourceType() string {
+	return RoleRepoSourceLocal
+}
+
+func (p *RoleRepoLocalProvider) DiscoverRemoteRoles(ctx context.Context, source RoleRepoSource) ([]RoleRepoRemoteRole, error) {
+	tree, err := p.FetchTree(ctx, source, "")
+	if err != nil {
+		return nil, err
+	}
+	return discoverRoleRepoRoles(p, source, tree), nil
+}
+
+func (p *RoleRepoLocalProvider) ResolveRef(ctx context.Context, source RoleRepoSource, ref string) (string, error) {
+	if ref != "" {
+		return "", fmt.Errorf("local source %s does not support refs (got %q)", source.FullName(), ref)
+	}
+	return "", nil
+}
+
+func (p *RoleRepoLocalProvider) FetchTree(ctx context.Context, source RoleRepoSource, ref string) ([]RoleRepoTreeEntry, error) {
+	if ref != "" {
+		return nil, fmt.Errorf("local source %s does not support refs (got %q)", source.FullName(), ref)
+	}
+	var tree []RoleRepoTreeEntry
+	err := filepath.WalkDir(source.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" && path != source.Path {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source.Path, path)
+		if err != nil {
+			return err
+		}
+		sha := gitBlobSHA(data)
+		p.blobs[source.Path+"\x00"+sha] = path
+		tree = append(tree, RoleRepoTreeEntry{Path: filepath.ToSlash(rel), Type: "blob", SHA: sha})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read local source %s: %w", source.FullName(), err)
+	}
+	sort.Slice(tree, func(i, j int) bool { return tree[i].Path < tree[j].Path })
+	return tree, nil
+}
+
+func (p *RoleRepoLocalProvider) FetchBlob(ctx context.Context, source RoleRepoSource, sha string) ([]byte, error) {
+	key := source.Path + "\x00" + sha
+	path, ok := p.blobs[key]
+	if !ok {
+		if _, err := p.FetchTree(ctx, source, ""); err != nil {
+			return nil, err
+		}
+		if path, ok = p.blobs[key]; !ok {
+			return nil, fmt.Errorf("blob %s not found in %s", sha, source.FullName())
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if gitBlobSHA(data) != sha {
+		return nil, fmt.Errorf("%s changed while reading", path)
+	}
+	return data, nil
+}
+
+func (p *RoleRepoLocalProvider) FolderHash(files []RoleRepoTreeEntry) string {
+	return hashRoleRepoTreeFiles(files)
+}
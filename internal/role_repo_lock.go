@@ -34,6 +34,31 @@ func ReadRoleRepoLock(path string) (RoleRepoLockFile, error) {
 }
 
 func WriteRoleRepoLock(path string, lock RoleRepoLockFile) error {
+	return WithSharedStateLock(path, func() error {
+		return writeRoleRepoLock(path, lock)
+	})
+}
+
+// UpdateRoleRepoLock re-reads the lock file at path while holding its
+// advisory lock, applies mutate and writes the result. Commands that prompt
+// or fetch between reading and writing the lock record their changes through
+// it, so concurrent runs merge instead of dropping each other's entries. A
+// corrupt file is replaced, as roleRepoLockForScope already warned about it.
+func UpdateRoleRepoLock(path string, mutate func(lock *RoleRepoLockFile)) (RoleRepoLockFile, error) {
+	var lock RoleRepoLockFile
+	err := WithSharedStateLock(path, func() error {
+		var err error
+		lock, err = ReadRoleRepoLock(path)
+		if err != nil && !errors.Is(err, ErrRoleRepoLockCorrupt) {
+			return err
+		}
+		mutate(&lock)
+		return writeRoleRepoLock(path, lock)
+	})
+	return lock, err
+}
+
+func writeRoleRepoLock(path string, lock RoleRepoLockFile) error {
 	if lock.Version == 0 {
 		lock.Version = RoleRepoLockVersion
 	}
@@ -51,7 +76,7 @@ func WriteRoleRepoLock(path string, lock RoleRepoLockFile) error {
 		return err
 	}
 	data = append(data, '\n')
-	return os.WriteFile(path, data, 0644)
+	return WriteFileAtomic(path, data, 0644)
 }
 
 func UpsertRoleRepoLockEntry(lock *RoleRepoLockFile, entry RoleRepoLockEntry) {
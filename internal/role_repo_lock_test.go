@@ -2,8 +2,10 @@ package internal
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -63,3 +65,47 @@ func TestRoleRepoLockUpsertRemove(t *testing.T) {
 		t.Fatalf("remove failed: removed=%d entries=%d", removed, len(lock.Entries))
 	}
 }
+
+func TestUpdateRoleRepoLockMergesConcurrentRuns(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "roles-lock.json")
+	if err := WriteRoleRepoLock(path, RoleRepoLockFile{Entries: []RoleRepoLockEntry{{Name: "stale"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := UpdateRoleRepoLock(path, func(lock *RoleRepoLockFile) {
+				UpsertRoleRepoLockEntry(lock, RoleRepoLockEntry{Name: fmt.Sprintf("role-%d", i)})
+			}); err != nil {
+				t.Errorf("UpdateRoleRepoLock: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	lock, err := UpdateRoleRepoLock(path, func(lock *RoleRepoLockFile) {
+		RemoveRoleRepoLockEntries(lock, []string{"stale"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reread, err := ReadRoleRepoLock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lock.Entries) != 8 || len(reread.Entries) != 8 {
+		t.Fatalf("expected 8 entries, got %+v", reread.Entries)
+	}
+	if _, ok := FindRoleRepoLockEntry(reread, "stale"); ok {
+		t.Fatal("removed entry is still present")
+	}
+	// The lock file sits in the user cache, not next to roles-lock.json.
+	if fileExists(path+".lock") || !fileExists(SharedStateLockPath(path)) {
+		t.Fatalf("unexpected lock file placement for %s", path)
+	}
+}
@@ -0,0 +1,215 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RoleRepoProvider is a backend that can list and read role files from one
+// kind of source. Tree entry SHAs are git blob IDs for every backend, so a
+// folder hash does not change when the same files are served from a mirror.
+type RoleRepoProvider interface {
+	// SourceType is the RoleRepoLockEntry.SourceType this backend serves.
+	SourceType() string
+	// DiscoverRemoteRoles lists every role on the source's default branch.
+	DiscoverRemoteRoles(ctx context.Context, source RoleRepoSource) ([]RoleRepoRemoteRole, error)
+	// ResolveRef returns the commit SHA a tag, branch or SHA points to; an
+	// empty ref means the default branch. Local sources resolve to "".
+	ResolveRef(ctx context.Context, source RoleRepoSource, ref string) (string, error)
+	// FetchTree lists all files at ref; an empty ref means the default branch.
+	FetchTree(ctx context.Context, source RoleRepoSource, ref string) ([]RoleRepoTreeEntry, error)
+	// FetchBlob returns the content of the blob with the given SHA.
+	FetchBlob(ctx context.Context, source RoleRepoSource, sha string) ([]byte, error)
+	// FolderHash summarizes a role folder's files for change detection.
+	FolderHash(files []RoleRepoTreeEntry) string
+}
+
+// RoleRepoProviderSet resolves sources to providers, reusing one provider
+// per source type. BaseDir anchors relative local sources from lock files.
+type RoleRepoProviderSet struct {
+	BaseDir    string
+	GitHub     *RoleRepoGitHubClient
+	HTTPClient *http.Client
+	CacheDir   string
+
+	providers map[string]RoleRepoProvider
+}
+
+// NewRoleRepoProviderSet creates a provider set for the project at baseDir.
+// A nil github client falls back to NewRoleRepoGitHubClient.
+func NewRoleRepoProviderSet(baseDir string, github *RoleRepoGitHubClient) *RoleRepoProviderSet {
+	if github == nil {
+		github = NewRoleRepoGitHubClient()
+	}
+	return &RoleRepoProviderSet{BaseDir: baseDir, GitHub: github, HTTPClient: http.DefaultClient}
+}
+
+// ForSource returns the provider for source.Type.
+func (s *RoleRepoProviderSet) ForSource(source RoleRepoSource) (RoleRepoProvider, error) {
+	if p, ok := s.providers[source.Type]; ok {
+		return p, nil
+	}
+	var p RoleRepoProvider
+	switch source.Type {
+	case RoleRepoSourceGitHub, "":
+		if s.GitHub == nil {
+			s.GitHub = NewRoleRepoGitHubClient()
+		}
+		p = s.GitHub
+	case RoleRepoSourceGitLab:
+		p = NewRoleRepoGitLabClient(s.HTTPClient)
+	case RoleRepoSourceGitea:
+		p = NewRoleRepoGiteaClient(s.HTTPClient)
+	case RoleRepoSourceGit:
+		p = NewRoleRepoGitProvider(s.CacheDir)
+	case RoleRepoSourceLocal:
+		p = NewRoleRepoLocalProvider()
+	default:
+		return nil, fmt.Errorf("unsupported source type %q", source.Type)
+	}
+	if s.providers == nil {
+		s.providers = map[string]RoleRepoProvider{}
+	}
+	s.providers[source.Type] = p
+	return p, nil
+}
+
+// LockSource returns the form of source recorded in a lock file. Local
+// directories inside BaseDir are stored relative to it so project locks
+// stay portable; other local directories are stored as absolute paths.
+func (s *RoleRepoProviderSet) LockSource(source RoleRepoSource) string {
+	if source.Type != RoleRepoSourceLocal {
+		return source.Canonical()
+	}
+	if s.BaseDir != "" {
+		if rel, err := filepath.Rel(s.BaseDir, source.Path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			if rel == "." {
+				return "."
+			}
+			return "./" + filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(source.Path)
+}
+
+// LockEntrySource rebuilds the source of a lock entry. Entries written
+// before SourceType was recorded are GitHub sources; an explicit hosted or
+// git type is honoured even when Source is a bare URL.
+func (s *RoleRepoProviderSet) LockEntrySource(entry RoleRepoLockEntry) (RoleRepoSource, error) {
+	raw := entry.Source
+	switch entry.SourceType {
+	case RoleRepoSourceGitLab, RoleRepoSourceGitea, RoleRepoSourceGit:
+		if !strings.HasPrefix(raw, entry.SourceType+"+") && strings.Contains(raw, "://") {
+			raw = entry.SourceType + "+" + raw
+		}
+	}
+	source, err := ParseRoleRepoSourceIn(s.BaseDir, raw)
+	if err != nil {
+		return RoleRepoSource{}, err
+	}
+	if entry.SourceType != "" && source.Type != entry.SourceType {
+		return RoleRepoSource{}, fmt.Errorf("lock source %q parses as %s, but sourceType is %s", entry.Source, source.Type, entry.SourceType)
+	}
+	if entry.Commit != "" && !roleRepoCommitPattern.MatchString(entry.Commit) {
+		return RoleRepoSource{}, fmt.Errorf("lock entry %s has invalid commit %q", entry.Name, entry.Commit)
+	}
+	if entry.Ref != "" {
+		if err := ValidateRoleRepoRef(entry.Ref); err != nil {
+			return RoleRepoSource{}, fmt.Errorf("lock entry %s: %w", entry.Name, err)
+		}
+		if source.Type == RoleRepoSourceLocal {
+			return RoleRepoSource{}, fmt.Errorf("local source %q does not support refs (got %s)", entry.Source, entry.Ref)
+		}
+		source.Ref = entry.Ref
+	}
+	return source, nil
+}
+
+// ResolveRoleRepoRoles lists the roles at source.Ref (the default branch
+// when empty), reading the tree at the resolved commit so the files and the
+// commit recorded for them cannot drift apart.
+func ResolveRoleRepoRoles(ctx context.Context, p RoleRepoProvider, source RoleRepoSource) ([]RoleRepoRemoteRole, error) {
+	commit, err := p.ResolveRef(ctx, source, source.Ref)
+	if err != nil {
+		if source.Ref != "" {
+			return nil, fmt.Errorf("resolve %s@%s: %w", source.FullName(), source.Ref, err)
+		}
+		return nil, err
+	}
+	tree, err := p.FetchTree(ctx, source, commit)
+	if err != nil {
+		return nil, err
+	}
+	roles := discoverRoleRepoRoles(p, source, tree)
+	for i := range roles {
+		roles[i].Commit = commit
+	}
+	return roles, nil
+}
+
+// discoverRoleRepoRoles finds role folders in tree by their strict role.yaml
+// path contracts and groups the files below each one.
+func discoverRoleRepoRoles(p RoleRepoProvider, source RoleRepoSource, tree []RoleRepoTreeEntry) []RoleRepoRemoteRole {
+	roleByPath := map[string]*RoleRepoRemoteRole{}
+	for _, e := range tree {
+		if e.Type != "blob" {
+			continue
+		}
+		roleName, rolePath, ok := ParseRolePathFromYAMLPath(e.Path)
+		if !ok {
+			continue
+		}
+		candidate := RoleRepoCandidate{
+			Name:       roleName,
+			RolePath:   rolePath,
+			YAMLPath:   e.Path,
+			Source:     source,
+			SourceType: p.SourceType(),
+			SourceURL:  source.URL(),
+		}
+		roleByPath[rolePath] = &RoleRepoRemoteRole{Candidate: candidate}
+	}
+
+	if len(roleByPath) == 0 {
+		return []RoleRepoRemoteRole{}
+	}
+
+	for _, e := range tree {
+		if e.Type != "blob" {
+			continue
+		}
+		for rolePath, role := range roleByPath {
+			prefix := rolePath + "/"
+			if strings.HasPrefix(e.Path, prefix) {
+				role.Files = append(role.Files, e)
+			}
+		}
+	}
+
+	roles := make([]RoleRepoRemoteRole, 0, len(roleByPath))
+	for _, role := range roleByPath {
+		role.FolderHash = p.FolderHash(role.Files)
+		sort.Slice(role.Files, func(i, j int) bool {
+			return role.Files[i].Path < role.Files[j].Path
+		})
+		roles = append(roles, *role)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Candidate.Name < roles[j].Candidate.Name
+	})
+	return roles
+}
+
+// gitBlobSHA computes the object ID git assigns to a blob with data.
+func gitBlobSHA(data []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(data))
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
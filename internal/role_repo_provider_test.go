@@ -0,0 +1,459 @@
+package internal
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeRoleRepoFixture(t *testing.T, dir, roleYAML string) {
+	t.Helper()
+	files := map[string]string{
+		"skills/frontend/references/role.yaml": roleYAML,
+		"skills/frontend/SKILL.md":             "# frontend\n",
+		"README.md":                            "roles\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRoleRepoLocalProviderInstallAndUpdate(t *testing.T) {
+	project := t.TempDir()
+	sourceDir := filepath.Join(project, "shared-roles")
+	writeRoleRepoFixture(t, sourceDir, "name: frontend\n")
+
+	providers := NewRoleRepoProviderSet(project, nil)
+	source, err := ParseRoleRepoSourceIn(project, "./shared-roles")
+	if err != nil {
+		t.Fatalf("ParseRoleRepoSourceIn: %v", err)
+	}
+	provider, err := providers.ForSource(source)
+	if err != nil {
+		t.Fatalf("ForSource: %v", err)
+	}
+	roles, err := provider.DiscoverRemoteRoles(context.Background(), source)
+	if err != nil {
+		t.Fatalf("DiscoverRemoteRoles: %v", err)
+	}
+	if len(roles) != 1 || roles[0].Candidate.Name != "frontend" || roles[0].Candidate.SourceType != RoleRepoSourceLocal {
+		t.Fatalf("unexpected roles: %+v", roles)
+	}
+	if got := roles[0].Files[0].SHA; got != gitBlobSHA([]byte("# frontend\n")) {
+		t.Fatalf("local tree SHA = %s, want git blob id", got)
+	}
+
+	installRoot := filepath.Join(project, ".agent-team", "teams")
+	if _, err := InstallRoleRepoRemoteRole(context.Background(), provider, roles[0], installRoot, false); err != nil {
+		t.Fatalf("InstallRoleRepoRemoteRole: %v", err)
+	}
+
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	lock := RoleRepoLockFile{Version: 1, Entries: []RoleRepoLockEntry{{
+		Name:       "frontend",
+		Source:     providers.LockSource(source),
+		SourceType: RoleRepoSourceLocal,
+		RolePath:   roles[0].Candidate.RolePath,
+		FolderHash: roles[0].FolderHash,
+	}}}
+	if lock.Entries[0].Source != "./shared-roles" {
+		t.Fatalf("lock source = %q, want ./shared-roles", lock.Entries[0].Source)
+	}
+
+	writeRoleRepoFixture(t, sourceDir, "name: frontend\nversion: 2\n")
+	fresh := NewRoleRepoProviderSet(project, nil)
+	statuses, _ := CheckRoleRepoUpdates(context.Background(), fresh, installRoot, lock)
+	if len(statuses) != 1 || statuses[0].State != "update_available" {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+	updated, _, failed := UpdateRoleRepoFromLock(context.Background(), fresh, installRoot, &lock, true, nil, "", false, func() time.Time { return now })
+	if len(updated) != 1 || len(failed) != 0 {
+		t.Fatalf("unexpected update: updated=%v failed=%v", updated, failed)
+	}
+	data, err := os.ReadFile(filepath.Join(installRoot, "frontend", "references", "role.yaml"))
+	if err != nil || !strings.Contains(string(data), "version: 2") {
+		t.Fatalf("expected updated role.yaml, got %q (%v)", data, err)
+	}
+	if lock.Entries[0].Source != "./shared-roles" || lock.Entries[0].SourceType != RoleRepoSourceLocal {
+		t.Fatalf("unexpected lock entry after update: %+v", lock.Entries[0])
+	}
+}
+
+func TestRoleRepoLockEntrySourceHonoursSourceType(t *testing.T) {
+	providers := NewRoleRepoProviderSet("", nil)
+	source, err := providers.LockEntrySource(RoleRepoLockEntry{Source: "https://git.example.com/team/roles", SourceType: RoleRepoSourceGitLab})
+	if err != nil {
+		t.Fatalf("LockEntrySource: %v", err)
+	}
+	if source.Type != RoleRepoSourceGitLab || source.Owner != "team" || source.Repo != "roles" {
+		t.Fatalf("unexpected source: %+v", source)
+	}
+
+	legacy, err := providers.LockEntrySource(RoleRepoLockEntry{Source: "acme/roles"})
+	if err != nil || legacy.Type != RoleRepoSourceGitHub {
+		t.Fatalf("legacy entry should be github: %+v, %v", legacy, err)
+	}
+
+	if _, err := providers.LockEntrySource(RoleRepoLockEntry{Source: "acme/roles", SourceType: RoleRepoSourceLocal}); err == nil {
+		t.Fatal("expected type mismatch error")
+	}
+
+	for _, entry := range []RoleRepoLockEntry{
+		{Name: "qa", Source: "acme/roles", Ref: "--upload-pack=touch /tmp/pwned"},
+		{Name: "qa", Source: "acme/roles", Commit: "--upload-pack=touch /tmp/pwned"},
+		{Name: "qa", Source: "git+--upload-pack=touch /tmp/pwned", SourceType: RoleRepoSourceGit},
+	} {
+		if _, err := providers.LockEntrySource(entry); err == nil {
+			t.Fatalf("LockEntrySource(%+v) should fail", entry)
+		}
+	}
+}
+
+func TestRoleRepoHostedTokensOnlyReachConfiguredHTTPSHosts(t *testing.T) {
+	var gotAuth string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization") + r.Header.Get("PRIVATE-TOKEN")
+		_ = json.NewEncoder(w).Encode(map[string]any{"default_branch": "main"})
+	})
+	tlsSrv := httptest.NewTLSServer(handler)
+	defer tlsSrv.Close()
+	plainSrv := httptest.NewServer(handler)
+	defer plainSrv.Close()
+	t.Setenv("GITEA_TOKEN", "gitea-secret")
+	t.Setenv("GITLAB_TOKEN", "gitlab-secret")
+
+	tests := []struct {
+		name, env, host string
+		srv             *httptest.Server
+		kind            string
+		wantAuth        string
+	}{
+		{"gitea without GITEA_HOST", "GITEA_HOST", "", tlsSrv, RoleRepoSourceGitea, ""},
+		{"gitea on other host", "GITEA_HOST", "git.example.com", tlsSrv, RoleRepoSourceGitea, ""},
+		{"gitea over http", "GITEA_HOST", strings.TrimPrefix(plainSrv.URL, "http://"), plainSrv, RoleRepoSourceGitea, ""},
+		{"gitea on configured host", "GITEA_HOST", tlsSrv.URL, tlsSrv, RoleRepoSourceGitea, "token gitea-secret"},
+		{"gitlab default host", "GITLAB_HOST", "", tlsSrv, RoleRepoSourceGitLab, ""},
+		{"gitlab on configured host", "GITLAB_HOST", "other.example.com," + strings.TrimPrefix(tlsSrv.URL, "https://"), tlsSrv, RoleRepoSourceGitLab, "gitlab-secret"},
+	}
+	for _, tt := range tests {
+		t.Setenv(tt.env, tt.host)
+		source := RoleRepoSource{Type: tt.kind, BaseURL: tt.srv.URL, Owner: "acme", Repo: "roles"}
+		gotAuth = ""
+		var err error
+		if tt.kind == RoleRepoSourceGitea {
+			_, err = NewRoleRepoGiteaClient(tt.srv.Client()).defaultBranch(context.Background(), source)
+		} else {
+			_, err = NewRoleRepoGitLabClient(tt.srv.Client()).defaultBranch(context.Background(), source)
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if gotAuth != tt.wantAuth {
+			t.Fatalf("%s: auth = %q, want %q", tt.name, gotAuth, tt.wantAuth)
+		}
+	}
+}
+
+func TestRoleRepoHostedProviders(t *testing.T) {
+	blobs := map[string]string{
+		"sha-role":  "name: frontend\n",
+		"sha-skill": "# frontend\n",
+	}
+	tree := []RoleRepoTreeEntry{
+		{Path: "skills", Type: "tree", SHA: "sha-dir"},
+		{Path: "skills/frontend/references/role.yaml", Type: "blob", SHA: "sha-role"},
+		{Path: "skills/frontend/SKILL.md", Type: "blob", SHA: "sha-skill"},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		// Gitea: two tree pages joined by "truncated".
+		case r.URL.Path == "/api/v1/repos/acme/roles":
+			_ = json.NewEncoder(w).Encode(map[string]any{"default_branch": "main"})
+		case r.URL.Path == "/api/v1/repos/acme/roles/git/trees/main":
+			if r.URL.Query().Get("page") == "1" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"tree": tree[:2], "truncated": true})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"tree": tree[2:], "truncated": false})
+		case strings.HasPrefix(r.URL.Path, "/api/v1/repos/acme/roles/git/blobs/"):
+			sha := strings.TrimPrefix(r.URL.Path, "/api/v1/repos/acme/roles/git/blobs/")
+			_ = json.NewEncoder(w).Encode(map[string]any{"encoding": "base64", "content": base64.StdEncoding.EncodeToString([]byte(blobs[sha]))})
+
+		// GitLab: project IDs are URL-encoded paths, pages via X-Next-Page.
+		case r.URL.EscapedPath() == "/api/v4/projects/group%2Fsub%2Froles":
+			_ = json.NewEncoder(w).Encode(map[string]any{"default_branch": "trunk"})
+		case r.URL.EscapedPath() == "/api/v4/projects/group%2Fsub%2Froles/repository/tree":
+			if r.URL.Query().Get("ref") != "trunk" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			items := []map[string]string{}
+			page := tree[:2]
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+			} else {
+				page = tree[2:]
+			}
+			for _, e := range page {
+				items = append(items, map[string]string{"id": e.SHA, "type": e.Type, "path": e.Path})
+			}
+			_ = json.NewEncoder(w).Encode(items)
+		case strings.HasPrefix(r.URL.EscapedPath(), "/api/v4/projects/group%2Fsub%2Froles/repository/blobs/"):
+			sha := strings.TrimSuffix(strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/projects/group%2Fsub%2Froles/repository/blobs/"), "/raw")
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(blobs[sha]))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"message": "unsupported path"})
+		}
+	}))
+	defer srv.Close()
+
+	providers := NewRoleRepoProviderSet("", nil)
+	providers.HTTPClient = srv.Client()
+	for _, raw := range []string{"gitea+" + srv.URL + "/acme/roles", "gitlab+" + srv.URL + "/group/sub/roles"} {
+		source, err := ParseRoleRepoSource(raw)
+		if err != nil {
+			t.Fatalf("ParseRoleRepoSource(%q): %v", raw, err)
+		}
+		provider, err := providers.ForSource(source)
+		if err != nil {
+			t.Fatalf("ForSource(%q): %v", raw, err)
+		}
+		roles, err := provider.DiscoverRemoteRoles(context.Background(), source)
+		if err != nil {
+			t.Fatalf("%s DiscoverRemoteRoles: %v", source.Type, err)
+		}
+		if len(roles) != 1 || len(roles[0].Files) != 2 {
+			t.Fatalf("%s roles = %+v", source.Type, roles)
+		}
+		installRoot := filepath.Join(t.TempDir(), "teams")
+		if _, err := InstallRoleRepoRemoteRole(context.Background(), provider, roles[0], installRoot, false); err != nil {
+			t.Fatalf("%s install: %v", source.Type, err)
+		}
+		data, err := os.ReadFile(filepath.Join(installRoot, "frontend", "SKILL.md"))
+		if err != nil || string(data) != "# frontend\n" {
+			t.Fatalf("%s installed SKILL.md = %q (%v)", source.Type, data, err)
+		}
+	}
+}
+
+func TestRoleRepoGitProvider(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	writeRoleRepoFixture(t, repo, "name: frontend\n")
+	for _, args := range [][]string{
+		{"init", "--quiet", "-b", "main"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "roles"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	providers := NewRoleRepoProviderSet("", nil)
+	providers.CacheDir = t.TempDir()
+	source, err := ParseRoleRepoSource("git+file://" + filepath.ToSlash(repo))
+	if err != nil {
+		t.Fatalf("ParseRoleRepoSource: %v", err)
+	}
+	provider, err := providers.ForSource(source)
+	if err != nil {
+		t.Fatalf("ForSource: %v", err)
+	}
+	roles, err := provider.DiscoverRemoteRoles(context.Background(), source)
+	if err != nil {
+		t.Fatalf("DiscoverRemoteRoles: %v", err)
+	}
+	if len(roles) != 1 || roles[0].Candidate.SourceType != RoleRepoSourceGit {
+		t.Fatalf("unexpected roles: %+v", roles)
+	}
+
+	// Served from a working tree, the same files hash identically.
+	local := NewRoleRepoLocalProvider()
+	localSource, err := ParseRoleRepoSource(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	localRoles, err := local.DiscoverRemoteRoles(context.Background(), localSource)
+	if err != nil {
+		t.Fatalf("local DiscoverRemoteRoles: %v", err)
+	}
+	if len(localRoles) != 1 || localRoles[0].FolderHash != roles[0].FolderHash {
+		t.Fatalf("git and local folder hashes differ: %+v vs %+v", localRoles, roles)
+	}
+
+	// A ref that git could read as an option must never reach git as one.
+	marker := filepath.Join(t.TempDir(), "pwned")
+	if _, err := provider.ResolveRef(context.Background(), source, "--upload-pack=touch "+marker+"; git-upload-pack"); err == nil {
+		t.Fatal("expected option-like ref to be rejected")
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("option-like ref ran a command")
+	}
+
+	installRoot := filepath.Join(t.TempDir(), "teams")
+	if _, err := InstallRoleRepoRemoteRole(context.Background(), provider, roles[0], installRoot, false); err != nil {
+		t.Fatalf("InstallRoleRepoRemoteRole: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(installRoot, "frontend", "references", "role.yaml"))
+	if err != nil || string(data) != "name: frontend\n" {
+		t.Fatalf("installed role.yaml = %q (%v)", data, err)
+	}
+
+	// A later run reuses the cached clone and fetches the remote's new
+	// default branch into it instead of serving the cached commit.
+	writeRoleRepoFixture(t, repo, "name: frontend\nversion: 2\n")
+	if out, err := exec.Command("git", "-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-am", "v2").CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v\n%s", err, out)
+	}
+	head, err := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := NewRoleRepoProviderSet("", nil)
+	other.CacheDir = providers.CacheDir
+	otherProvider, _ := other.ForSource(source)
+	commit, err := otherProvider.ResolveRef(context.Background(), source, "")
+	if err != nil {
+		t.Fatalf("second ResolveRef: %v", err)
+	}
+	if commit != strings.TrimSpace(string(head)) {
+		t.Fatalf("second run resolved %s, want new head %s", commit, head)
+	}
+	if _, err := provider.FetchBlob(context.Background(), source, roles[0].Files[0].SHA); err != nil {
+		t.Fatalf("FetchBlob after another run fetched: %v", err)
+	}
+	entries, _ := os.ReadDir(providers.CacheDir)
+	var clones []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			clones = append(clones, entry.Name())
+		}
+	}
+	if len(clones) != 1 {
+		t.Fatalf("expected one reused clone, got %v", clones)
+	}
+}
+
+func TestRoleRepoPinnedRefInstallUpdateAndRestore(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "--quiet", "-b", "main")
+	writeRoleRepoFixture(t, repo, "name: frontend\n")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "v1")
+	git("tag", "v1")
+	v1 := git("rev-parse", "HEAD")
+	writeRoleRepoFixture(t, repo, "name: frontend\nversion: 2\n")
+	git("commit", "--quiet", "-am", "v2")
+	v2 := git("rev-parse", "HEAD")
+
+	cacheDir := t.TempDir()
+	newProviders := func() *RoleRepoProviderSet {
+		providers := NewRoleRepoProviderSet("", nil)
+		providers.CacheDir = cacheDir
+		return providers
+	}
+
+	source, err := ParseRoleRepoSource("git+file://" + filepath.ToSlash(repo) + "@v1")
+	if err != nil {
+		t.Fatalf("ParseRoleRepoSource: %v", err)
+	}
+	if source.Ref != "v1" || source.CloneURL != "file://"+filepath.ToSlash(repo) {
+		t.Fatalf("unexpected pinned source: %+v", source)
+	}
+	providers := newProviders()
+	provider, err := providers.ForSource(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roles, err := ResolveRoleRepoRoles(context.Background(), provider, source)
+	if err != nil {
+		t.Fatalf("ResolveRoleRepoRoles: %v", err)
+	}
+	if len(roles) != 1 || roles[0].Commit != v1 {
+		t.Fatalf("expected role at v1 commit %s, got %+v", v1, roles)
+	}
+	installRoot := filepath.Join(t.TempDir(), "teams")
+	if _, err := InstallRoleRepoRemoteRole(context.Background(), provider, roles[0], installRoot, false); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	lock := RoleRepoLockFile{Version: 1, Entries: []RoleRepoLockEntry{{
+		Name:       "frontend",
+		Source:     providers.LockSource(source),
+		SourceType: RoleRepoSourceGit,
+		RolePath:   roles[0].Candidate.RolePath,
+		Ref:        source.Ref,
+		Commit:     roles[0].Commit,
+		FolderHash: roles[0].FolderHash,
+	}}}
+
+	// The pin holds although main has moved on.
+	statuses, _ := CheckRoleRepoUpdates(context.Background(), newProviders(), installRoot, lock)
+	if len(statuses) != 1 || statuses[0].State != "up_to_date" || statuses[0].Ref != "v1" {
+		t.Fatalf("pinned role should be up to date: %+v", statuses)
+	}
+
+	// Restoring from the lock puts back exactly the locked files.
+	roleYAML := filepath.Join(installRoot, "frontend", "references", "role.yaml")
+	if err := os.WriteFile(roleYAML, []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	installed, failed := InstallRoleRepoFromLock(context.Background(), newProviders(), installRoot, lock, nil)
+	if len(installed) != 1 || len(failed) != 0 {
+		t.Fatalf("InstallRoleRepoFromLock: installed=%v failed=%v", installed, failed)
+	}
+	if data, _ := os.ReadFile(roleYAML); string(data) != "name: frontend\n" {
+		t.Fatalf("restored role.yaml = %q", data)
+	}
+
+	updated, _, failed := UpdateRoleRepoFromLock(context.Background(), newProviders(), installRoot, &lock, true, []string{"frontend"}, "main", false, func() time.Time { return now })
+	if len(updated) != 1 || len(failed) != 0 {
+		t.Fatalf("update --to main: updated=%v failed=%v", updated, failed)
+	}
+	if lock.Entries[0].Ref != "main" || lock.Entries[0].Commit != v2 {
+		t.Fatalf("lock not repinned: %+v", lock.Entries[0])
+	}
+	if data, _ := os.ReadFile(roleYAML); !strings.Contains(string(data), "version: 2") {
+		t.Fatalf("role.yaml after move = %q", data)
+	}
+
+	tampered := lock
+	tampered.Entries = []RoleRepoLockEntry{lock.Entries[0]}
+	tampered.Entries[0].FolderHash = "0000"
+	if _, failed := InstallRoleRepoFromLock(context.Background(), newProviders(), installRoot, tampered, nil); failed["frontend"] == nil || !strings.Contains(failed["frontend"].Error(), "folder hash mismatch") {
+		t.Fatalf("expected folder hash mismatch, got %v", failed)
+	}
+}
@@ -3,23 +3,135 @@ package internal
 import (
 	"fmt"
 	"net/url"
+	"os"
 	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
 
-var roleRepoFullNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+var (
+	roleRepoFullNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+	roleRepoSCPPattern      = regexp.MustCompile(`^[A-Za-z0-9_.-]+@[A-Za-z0-9_.-]+:[^/].*$`)
+	roleRepoCommitPattern   = regexp.MustCompile(`^[0-9a-fA-F]{4,64}$`)
+)
 
-// ParseRoleRepoSource parses owner/repo and GitHub URL forms into normalized source.
+// ParseRoleRepoSource parses a role source, resolving relative local paths
+// against the working directory. See ParseRoleRepoSourceIn.
 func ParseRoleRepoSource(source string) (RoleRepoSource, error) {
+	return ParseRoleRepoSourceIn("", source)
+}
+
+// ParseRoleRepoSourceIn parses a role source into its normalized form:
+//   - owner/repo, git@github.com:owner/repo and GitHub URLs → github
+//   - GitLab.com URLs and gitlab+https://host/group/repo → gitlab
+//   - gitea+https://host/owner/repo → gitea
+//   - git+<url>, ssh:// or scp-style remotes, and other http(s) URLs → git
+//   - file://, ./, ../, / and ~/ paths, or an existing directory → local
+//
+// Any remote source may end in @<tag|branch|sha> to pin a ref. Relative
+// local paths are resolved against baseDir (the working directory when
+// empty).
+func ParseRoleRepoSourceIn(baseDir, source string) (RoleRepoSource, error) {
 	source = strings.TrimSpace(source)
 	if source == "" {
 		return RoleRepoSource{}, fmt.Errorf("empty source")
 	}
+	source, ref := splitRoleRepoRef(baseDir, source)
+	parsed, err := parseRoleRepoSource(baseDir, source)
+	if err != nil {
+		return parsed, err
+	}
+	if strings.HasPrefix(parsed.CloneURL, "-") {
+		return RoleRepoSource{}, fmt.Errorf("invalid git remote %q", parsed.CloneURL)
+	}
+	if ref == "" {
+		return parsed, nil
+	}
+	if err := ValidateRoleRepoRef(ref); err != nil {
+		return RoleRepoSource{}, err
+	}
+	if parsed.Type == RoleRepoSourceLocal {
+		return RoleRepoSource{}, fmt.Errorf("local source %q does not support refs (got @%s)", parsed.Original, ref)
+	}
+	parsed.Ref = ref
+	return parsed, nil
+}
+
+// ValidateRoleRepoRef accepts a commit SHA or a name that
+// `git check-ref-format --allow-onelevel` would accept. Refs reach git
+// commands as arguments, so anything that could be read as an option is
+// rejected.
+func ValidateRoleRepoRef(ref string) error {
+	if roleRepoCommitPattern.MatchString(ref) {
+		return nil
+	}
+	invalid := fmt.Errorf("invalid ref %q", ref)
+	if ref == "" || ref == "@" || strings.HasPrefix(ref, "-") || strings.HasPrefix(ref, "/") ||
+		strings.HasSuffix(ref, "/") || strings.HasSuffix(ref, ".") ||
+		strings.Contains(ref, "..") || strings.Contains(ref, "@{") || strings.Contains(ref, "//") ||
+		strings.ContainsAny(ref, " ~^:?*[\\\x7f") {
+		return invalid
+	}
+	for _, r := range ref {
+		if r < 0x20 {
+			return invalid
+		}
+	}
+	for _, part := range strings.Split(ref, "/") {
+		if strings.HasPrefix(part, ".") || strings.HasSuffix(part, ".lock") {
+			return invalid
+		}
+	}
+	return nil
+}
+
+// splitRoleRepoRef cuts a trailing @ref from source. An @ only starts a ref
+// when a path separator precedes it, so git@host:repo and ssh://git@host
+// user names are left alone, as are existing local paths containing @.
+func splitRoleRepoRef(baseDir, source string) (string, string) {
+	i := strings.LastIndex(source, "@")
+	if i <= 0 || i == len(source)-1 {
+		return source, ""
+	}
+	before := source[:i]
+	if _, after, ok := strings.Cut(before, "://"); ok {
+		before = after
+	}
+	if !strings.Contains(before, "/") {
+		return source, ""
+	}
+	dir := strings.TrimPrefix(source, "file://")
+	if baseDir != "" && !filepath.IsAbs(dir) {
+		dir = filepath.Join(baseDir, dir)
+	}
+	if st, err := os.Stat(dir); err == nil && st.IsDir() {
+		return source, ""
+	}
+	return source[:i], source[i+1:]
+}
+
+func parseRoleRepoSource(baseDir, source string) (RoleRepoSource, error) {
+
+	if p, ok := strings.CutPrefix(source, "file://"); ok {
+		return parseRoleRepoLocalSource(baseDir, source, p)
+	}
+	if source == "." || source == ".." || strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") || strings.HasPrefix(source, "/") || strings.HasPrefix(source, "~/") {
+		return parseRoleRepoLocalSource(baseDir, source, source)
+	}
+
+	if kind, rest, ok := strings.Cut(source, "+"); ok {
+		switch kind {
+		case RoleRepoSourceGitHub, RoleRepoSourceGitLab, RoleRepoSourceGitea:
+			return parseRoleRepoHostedSource(source, kind, rest)
+		case RoleRepoSourceGit:
+			return RoleRepoSource{Original: source, Type: RoleRepoSourceGit, CloneURL: rest}, nil
+		}
+	}
 
 	if roleRepoFullNamePattern.MatchString(source) {
 		parts := strings.SplitN(source, "/", 2)
-		return RoleRepoSource{Original: source, Owner: parts[0], Repo: parts[1]}, nil
+		return RoleRepoSource{Original: source, Type: RoleRepoSourceGitHub, Owner: parts[0], Repo: parts[1]}, nil
 	}
 
 	if strings.HasPrefix(source, "git@github.com:") {
@@ -27,24 +139,105 @@ func ParseRoleRepoSource(source string) (RoleRepoSource, error) {
 		repo = strings.TrimSuffix(repo, ".git")
 		if roleRepoFullNamePattern.MatchString(repo) {
 			parts := strings.SplitN(repo, "/", 2)
-			return RoleRepoSource{Original: source, Owner: parts[0], Repo: parts[1]}, nil
+			return RoleRepoSource{Original: source, Type: RoleRepoSourceGitHub, Owner: parts[0], Repo: parts[1]}, nil
 		}
 	}
+	if roleRepoSCPPattern.MatchString(source) {
+		return RoleRepoSource{Original: source, Type: RoleRepoSourceGit, CloneURL: source}, nil
+	}
 
 	u, err := url.Parse(source)
-	if err == nil && (u.Scheme == "https" || u.Scheme == "http") && strings.EqualFold(u.Host, "github.com") {
-		p := strings.Trim(u.Path, "/")
-		p = strings.TrimSuffix(p, ".git")
-		parts := strings.Split(p, "/")
-		if len(parts) >= 2 {
-			repo := parts[0] + "/" + parts[1]
-			if roleRepoFullNamePattern.MatchString(repo) {
-				return RoleRepoSource{Original: source, Owner: parts[0], Repo: parts[1]}, nil
+	if err == nil && u.Host != "" {
+		switch u.Scheme {
+		case "https", "http":
+			switch {
+			case strings.EqualFold(u.Host, "github.com"):
+				return parseRoleRepoHostedSource(source, RoleRepoSourceGitHub, source)
+			case strings.EqualFold(u.Host, "gitlab.com") && !strings.HasSuffix(u.Path, ".git"):
+				return parseRoleRepoHostedSource(source, RoleRepoSourceGitLab, source)
+			default:
+				return RoleRepoSource{Original: source, Type: RoleRepoSourceGit, CloneURL: source}, nil
 			}
+		case "ssh", "git":
+			return RoleRepoSource{Original: source, Type: RoleRepoSourceGit, CloneURL: source}, nil
 		}
 	}
 
-	return RoleRepoSource{}, fmt.Errorf("unsupported source %q (use owner/repo or GitHub URL)", source)
+	dir := source
+	if baseDir != "" {
+		dir = filepath.Join(baseDir, source)
+	}
+	if st, err := os.Stat(dir); err == nil && st.IsDir() {
+		return parseRoleRepoLocalSource(baseDir, source, source)
+	}
+
+	return RoleRepoSource{}, fmt.Errorf("unsupported source %q (use owner/repo, a GitHub or GitLab URL, gitea+https://host/owner/repo, a git remote, or a local path)", source)
+}
+
+// parseRoleRepoHostedSource splits an https URL of a hosted forge into base
+// URL, owner (the namespace, possibly nested) and repo.
+func parseRoleRepoHostedSource(original, kind, rawURL string) (RoleRepoSource, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return RoleRepoSource{}, fmt.Errorf("unsupported %s source %q (use https://host/owner/repo)", kind, original)
+	}
+	p := strings.Trim(u.Path, "/")
+	if i := strings.Index(p, "/-/"); i >= 0 {
+		p = p[:i]
+	}
+	p = strings.TrimSuffix(p, ".git")
+	parts := strings.Split(p, "/")
+	if kind == RoleRepoSourceGitHub && len(parts) > 2 {
+		parts = parts[:2]
+	}
+	if len(parts) < 2 || (kind != RoleRepoSourceGitLab && len(parts) != 2) {
+		return RoleRepoSource{}, fmt.Errorf("unsupported %s source %q (use https://host/owner/repo)", kind, original)
+	}
+	for _, part := range parts {
+		if part == "" || part == "." || part == ".." {
+			return RoleRepoSource{}, fmt.Errorf("unsupported %s source %q (use https://host/owner/repo)", kind, original)
+		}
+	}
+	source := RoleRepoSource{
+		Original: original,
+		Type:     kind,
+		Owner:    strings.Join(parts[:len(parts)-1], "/"),
+		Repo:     parts[len(parts)-1],
+	}
+	if kind != RoleRepoSourceGitHub {
+		source.BaseURL = u.Scheme + "://" + u.Host
+	}
+	return source, nil
+}
+
+func parseRoleRepoLocalSource(baseDir, original, p string) (RoleRepoSource, error) {
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return RoleRepoSource{}, fmt.Errorf("resolve home directory: %w", err)
+		}
+		p = filepath.Join(home, rest)
+	}
+	abs := filepath.FromSlash(p)
+	if !filepath.IsAbs(abs) {
+		if baseDir == "" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return RoleRepoSource{}, fmt.Errorf("resolve working directory: %w", err)
+			}
+			baseDir = wd
+		}
+		abs = filepath.Join(baseDir, abs)
+		original = path.Clean(filepath.ToSlash(p))
+		if original != "." && original != ".." && !strings.HasPrefix(original, "../") {
+			original = "./" + original
+		}
+	}
+	abs = filepath.Clean(abs)
+	if st, err := os.Stat(abs); err != nil || !st.IsDir() {
+		return RoleRepoSource{}, fmt.Errorf("local source %q is not a directory", original)
+	}
+	return RoleRepoSource{Original: original, Type: RoleRepoSourceLocal, Path: abs}, nil
 }
 
 // ParseRolePathFromYAMLPath validates strict role contracts and returns role metadata.
@@ -1,6 +1,10 @@
 package internal
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 func TestParseRoleRepoSource(t *testing.T) {
 	tests := []struct {
@@ -58,3 +62,106 @@ func TestParseRolePathFromYAMLPath(t *testing.T) {
 		}
 	}
 }
+
+func TestParseRoleRepoSourceTypes(t *testing.T) {
+	base := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(base, "roles"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		in            string
+		wantType      string
+		wantCanonical string
+	}{
+		{"owner/repo", RoleRepoSourceGitHub, "owner/repo"},
+		{"./roles", RoleRepoSourceLocal, "./roles"},
+		{"roles/", RoleRepoSourceLocal, "./roles"},
+		{"file://" + filepath.ToSlash(filepath.Join(base, "roles")), RoleRepoSourceLocal, "file://" + filepath.ToSlash(filepath.Join(base, "roles"))},
+		{"https://gitlab.com/group/sub/repo", RoleRepoSourceGitLab, "gitlab+https://gitlab.com/group/sub/repo"},
+		{"gitlab+https://git.example.com/team/roles", RoleRepoSourceGitLab, "gitlab+https://git.example.com/team/roles"},
+		{"gitea+https://codeberg.org/acme/roles", RoleRepoSourceGitea, "gitea+https://codeberg.org/acme/roles"},
+		{"https://git.example.com/acme/roles.git", RoleRepoSourceGit, "https://git.example.com/acme/roles.git"},
+		{"git@git.example.com:acme/roles.git", RoleRepoSourceGit, "git@git.example.com:acme/roles.git"},
+		{"git+https://gitlab.com/acme/roles", RoleRepoSourceGit, "git+https://gitlab.com/acme/roles"},
+	}
+	for _, tt := range tests {
+		s, err := ParseRoleRepoSourceIn(base, tt.in)
+		if err != nil {
+			t.Fatalf("ParseRoleRepoSourceIn(%q): %v", tt.in, err)
+		}
+		if s.Type != tt.wantType {
+			t.Fatalf("ParseRoleRepoSourceIn(%q).Type = %q, want %q", tt.in, s.Type, tt.wantType)
+		}
+		if got := s.Canonical(); got != tt.wantCanonical {
+			t.Fatalf("ParseRoleRepoSourceIn(%q).Canonical() = %q, want %q", tt.in, got, tt.wantCanonical)
+		}
+		if reparsed, err := ParseRoleRepoSourceIn(base, s.Canonical()); err != nil || reparsed.Type != s.Type {
+			t.Fatalf("canonical %q does not round-trip: %+v, %v", s.Canonical(), reparsed, err)
+		}
+	}
+
+	if _, err := ParseRoleRepoSourceIn(base, "./missing"); err == nil {
+		t.Fatal("expected error for missing local directory")
+	}
+}
+
+func TestParseRoleRepoSourceRef(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantName string
+		wantRef  string
+	}{
+		{"owner/repo@v1.2.0", "owner/repo", "v1.2.0"},
+		{"owner/repo@release/2026", "owner/repo", "release/2026"},
+		{"https://github.com/owner/repo@4f2c9e1", "owner/repo", "4f2c9e1"},
+		{"git@github.com:owner/repo.git@main", "owner/repo", "main"},
+		{"git@git.example.com:acme/roles.git", "git@git.example.com:acme/roles.git", ""},
+		{"ssh://git@git.example.com/acme/roles.git@v2", "ssh://git@git.example.com/acme/roles.git", "v2"},
+	}
+	for _, tt := range tests {
+		s, err := ParseRoleRepoSource(tt.in)
+		if err != nil {
+			t.Fatalf("ParseRoleRepoSource(%q): %v", tt.in, err)
+		}
+		if s.FullName() != tt.wantName || s.Ref != tt.wantRef {
+			t.Fatalf("ParseRoleRepoSource(%q) = (%q, ref %q), want (%q, ref %q)", tt.in, s.FullName(), s.Ref, tt.wantName, tt.wantRef)
+		}
+	}
+
+	base := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(base, "roles"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseRoleRepoSourceIn(base, "./roles@v1"); err == nil {
+		t.Fatal("expected error for ref on local source")
+	}
+}
+
+func TestParseRoleRepoSourceRejectsOptionLikeArguments(t *testing.T) {
+	for _, in := range []string{
+		"owner/repo@--upload-pack=touch /tmp/pwned; git-upload-pack",
+		"owner/repo@-v",
+		"owner/repo@main..dev",
+		"owner/repo@refs/heads/.hidden",
+		"owner/repo@main.lock",
+		"git+--upload-pack=touch /tmp/pwned",
+	} {
+		if _, err := ParseRoleRepoSource(in); err == nil {
+			t.Fatalf("ParseRoleRepoSource(%q) should fail", in)
+		}
+	}
+}
+
+func TestValidateRoleRepoRef(t *testing.T) {
+	for _, ref := range []string{"main", "v1.2.0", "release/2026", "4f2c9e1", "HEAD", "feature/a-b_c"} {
+		if err := ValidateRoleRepoRef(ref); err != nil {
+			t.Fatalf("ValidateRoleRepoRef(%q): %v", ref, err)
+		}
+	}
+	for _, ref := range []string{"", "-x", "--upload-pack=x", "a b", "a~1", "a^", "a:b", "a..b", "a@{1}", "/a", "a/", "a.", "a//b", "@"} {
+		if err := ValidateRoleRepoRef(ref); err == nil {
+			t.Fatalf("ValidateRoleRepoRef(%q) should fail", ref)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package internal
 
-import "time"
+import (
+	"path/filepath"
+	"time"
+)
 
 // RoleRepoScope controls install scope for role-repo commands.
 type RoleRepoScope string
@@ -10,19 +13,78 @@ const (
 	RoleRepoScopeGlobal  RoleRepoScope = "global"
 )
 
-// RoleRepoSource is a normalized remote source reference.
+// Role-repo source types, recorded as RoleRepoLockEntry.SourceType.
+const (
+	RoleRepoSourceGitHub = "github"
+	RoleRepoSourceGitLab = "gitlab"
+	RoleRepoSourceGitea  = "gitea"
+	RoleRepoSourceGit    = "git"
+	RoleRepoSourceLocal  = "local"
+)
+
+// RoleRepoSource is a normalized source reference. Owner/Repo are set for
+// hosted sources (Owner may contain "/" for GitLab subgroups); BaseURL is the
+// GitLab/Gitea web root, CloneURL the remote for plain git sources and Path
+// the absolute directory for local sources. Ref is the tag, branch or
+// commit requested with an @ref suffix; empty means the default branch.
 type RoleRepoSource struct {
 	Original string
+	Type     string
 	Owner    string
 	Repo     string
+	BaseURL  string
+	CloneURL string
+	Path     string
+	Ref      string
 }
 
+// FullName is the human-readable source name: owner/repo for hosted
+// sources, the remote URL for git sources and the path for local ones.
 func (s RoleRepoSource) FullName() string {
-	return s.Owner + "/" + s.Repo
+	switch s.Type {
+	case RoleRepoSourceGit:
+		return s.CloneURL
+	case RoleRepoSourceLocal:
+		return s.Original
+	default:
+		return s.Owner + "/" + s.Repo
+	}
 }
 
 func (s RoleRepoSource) HTTPSURL() string {
-	return "https://github.com/" + s.FullName()
+	return "https://github.com/" + s.Owner + "/" + s.Repo
+}
+
+// URL returns where the source can be browsed or cloned.
+func (s RoleRepoSource) URL() string {
+	switch s.Type {
+	case RoleRepoSourceGitLab, RoleRepoSourceGitea:
+		return s.BaseURL + "/" + s.Owner + "/" + s.Repo
+	case RoleRepoSourceGit:
+		return s.CloneURL
+	case RoleRepoSourceLocal:
+		return "file://" + filepath.ToSlash(s.Path)
+	default:
+		return s.HTTPSURL()
+	}
+}
+
+// Canonical is the form stored in roles-lock.json; parsing it again yields
+// the same source. The ref is stored separately and is not included.
+func (s RoleRepoSource) Canonical() string {
+	switch s.Type {
+	case RoleRepoSourceGitLab, RoleRepoSourceGitea:
+		return s.Type + "+" + s.URL()
+	case RoleRepoSourceGit:
+		if parsed, err := ParseRoleRepoSource(s.CloneURL); err == nil && parsed.Type == RoleRepoSourceGit {
+			return s.CloneURL
+		}
+		return "git+" + s.CloneURL
+	case RoleRepoSourceLocal:
+		return s.Original
+	default:
+		return s.Owner + "/" + s.Repo
+	}
 }
 
 // RoleRepoCandidate identifies a role path in a source repository.
@@ -45,13 +107,17 @@ type RoleRepoSearchResult struct {
 	SourceURL string
 }
 
-// RoleRepoLockEntry tracks installation metadata for one role.
+// RoleRepoLockEntry tracks installation metadata for one role. Ref is the
+// pinned tag, branch or commit (empty follows the default branch) and Commit
+// the commit the installed files were resolved from.
 type RoleRepoLockEntry struct {
 	Name        string    `json:"name"`
 	Source      string    `json:"source"`
 	SourceType  string    `json:"sourceType"`
 	SourceURL   string    `json:"sourceUrl"`
 	RolePath    string    `json:"rolePath"`
+	Ref         string    `json:"ref,omitempty"`
+	Commit      string    `json:"commit,omitempty"`
 	FolderHash  string    `json:"folderHash"`
 	InstalledAt time.Time `json:"installedAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
@@ -67,12 +133,14 @@ const RoleRepoLockVersion = 1
 
 // RoleRepoCheckStatus summarizes check result for one installed role.
 type RoleRepoCheckStatus struct {
-	Name         string
-	CurrentHash  string
-	RemoteHash   string
-	Source       string
-	RolePath     string
-	State        string // up_to_date | update_available | error
-	Err          error
-	RemoteExists bool
+	Name          string
+	CurrentHash   string
+	RemoteHash    string
+	Source        string
+	RolePath      string
+	Ref           string
+	State         string // up_to_date | update_available | error
+	Err           error
+	RemoteExists  bool
+	LocalModified bool
 }
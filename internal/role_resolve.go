@@ -0,0 +1,315 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RoleDefinition is a role's contract as read from references/role.yaml and
+// its system prompt. Resolved definitions have their extends/mixins parents
+// merged in; Chain lists the roles that contributed, base first.
+type RoleDefinition struct {
+	Name         string
+	Path         string
+	Description  string
+	Extends      string
+	Mixins       []string
+	InScope      []string
+	OutOfScope   []string
+	Skills       []RoleSkillSpec
+	Constraints  map[string]any
+	SystemPrompt string
+	Chain        []string
+}
+
+// roleDefinitionYAML holds the role.yaml fields the resolver merges. Skills
+// are read separately by readRoleSkillSpecs.
+type roleDefinitionYAML struct {
+	Description      string   `yaml:"description"`
+	Extends          string   `yaml:"extends"`
+	Mixins           []string `yaml:"mixins"`
+	SystemPromptFile string   `yaml:"system_prompt_file"`
+	Scope            struct {
+		InScope    []string `yaml:"in_scope"`
+		OutOfScope []string `yaml:"out_of_scope"`
+	} `yaml:"scope"`
+	Constraints map[string]any `yaml:"constraints"`
+}
+
+// ReadRoleDefinition reads the role at rolePath without resolving its
+// extends/mixins parents. A missing role.yaml yields a definition holding
+// only the name and system.md.
+func ReadRoleDefinition(rolePath string) (*RoleDefinition, error) {
+	def := &RoleDefinition{Name: filepath.Base(rolePath), Path: rolePath}
+	promptFile := "system.md"
+
+	data, err := os.ReadFile(filepath.Join(rolePath, "references", "role.yaml"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read role.yaml: %w", err)
+	}
+	if err == nil {
+		var ry roleDefinitionYAML
+		if err := yaml.Unmarshal(data, &ry); err != nil {
+			return nil, fmt.Errorf("parse role.yaml: %w", err)
+		}
+		skills, err := readRoleSkillSpecs(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse role.yaml: %w", err)
+		}
+		def.Description = strings.TrimSpace(ry.Description)
+		def.Extends = strings.TrimSpace(ry.Extends)
+		def.Mixins = DedupeKeepOrder(trimNonEmpty(ry.Mixins))
+		def.InScope = trimNonEmpty(ry.Scope.InScope)
+		def.OutOfScope = trimNonEmpty(ry.Scope.OutOfScope)
+		def.Skills = skills
+		def.Constraints = ry.Constraints
+		if f := strings.TrimSpace(ry.SystemPromptFile); f != "" {
+			promptFile = f
+		}
+	}
+
+	prompt, err := os.ReadFile(filepath.Join(rolePath, filepath.FromSlash(promptFile)))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read %s: %w", promptFile, err)
+	}
+	def.SystemPrompt = string(prompt)
+	return def, nil
+}
+
+// ResolveRoleFromPath reads the role at rolePath and merges in the roles it
+// extends and mixes in. Parents are looked up next to the role first, then
+// in the global roles directory. The extends parent is applied first, then
+// each mixin in order, then the role itself:
+//   - scope items are unioned; an item a later layer puts out of scope is
+//     dropped from in_scope (and the other way round)
+//   - skills merge by name, later descriptions win
+//   - constraints merge by key, later values win
+//   - system.md "## " sections with the same heading are replaced, new ones
+//     are appended, and a non-empty preamble replaces the inherited one
+func ResolveRoleFromPath(rolePath string) (*RoleDefinition, error) {
+	return resolveRoleDefinition(rolePath, nil)
+}
+
+func resolveRoleDefinition(rolePath string, stack []string) (*RoleDefinition, error) {
+	name := filepath.Base(rolePath)
+	for i, seen := range stack {
+		if seen == name {
+			return nil, fmt.Errorf("role inheritance cycle: %s", strings.Join(append(stack[i:], name), " -> "))
+		}
+	}
+	stack = append(stack[:len(stack):len(stack)], name)
+
+	def, err := ReadRoleDefinition(rolePath)
+	if err != nil {
+		if len(stack) > 1 {
+			return nil, fmt.Errorf("role %s: %w", name, err)
+		}
+		return nil, err
+	}
+	parents := def.Mixins
+	if def.Extends != "" {
+		parents = append([]string{def.Extends}, parents...)
+	}
+	if len(parents) == 0 {
+		def.Chain = []string{name}
+		return def, nil
+	}
+
+	resolved := &RoleDefinition{}
+	for _, parent := range parents {
+		parentPath, err := findRoleParentDir(rolePath, parent)
+		if err != nil {
+			return nil, fmt.Errorf("role %s: %w", name, err)
+		}
+		parentDef, err := resolveRoleDefinition(parentPath, stack)
+		if err != nil {
+			return nil, err
+		}
+		resolved = mergeRoleDefinitions(resolved, parentDef)
+	}
+	resolved = mergeRoleDefinitions(resolved, def)
+	resolved.Name = def.Name
+	resolved.Path = def.Path
+	resolved.Extends = def.Extends
+	resolved.Mixins = def.Mixins
+	resolved.Chain = DedupeKeepOrder(append(resolved.Chain, name))
+	return resolved, nil
+}
+
+// findRoleParentDir locates the role a definition extends or mixes in.
+func findRoleParentDir(rolePath, parent string) (string, error) {
+	if !IsKebabCase(parent) {
+		return "", fmt.Errorf("invalid parent role name %q", parent)
+	}
+	candidates := []string{filepath.Join(filepath.Dir(rolePath), parent)}
+	if globalDir, err := GlobalRolesDir(); err == nil {
+		candidates = append(candidates, filepath.Join(globalDir, parent))
+	}
+	for _, dir := range candidates {
+		if isRoleDir(dir) || fileExists(filepath.Join(dir, "references", "role.yaml")) {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("parent role %q not found next to the role or in ~/.agents/roles/", parent)
+}
+
+// mergeRoleDefinitions layers overlay on top of base.
+func mergeRoleDefinitions(base, overlay *RoleDefinition) *RoleDefinition {
+	out := &RoleDefinition{
+		Description:  base.Description,
+		InScope:      append(removeItems(base.InScope, overlay.OutOfScope), overlay.InScope...),
+		OutOfScope:   append(removeItems(base.OutOfScope, overlay.InScope), overlay.OutOfScope...),
+		SystemPrompt: mergeSystemPrompt(base.SystemPrompt, overlay.SystemPrompt),
+		Chain:        append(append([]string{}, base.Chain...), overlay.Chain...),
+	}
+	if overlay.Description != "" {
+		out.Description = overlay.Description
+	}
+	out.InScope = DedupeKeepOrder(out.InScope)
+	out.OutOfScope = DedupeKeepOrder(out.OutOfScope)
+
+	out.Skills = append([]RoleSkillSpec{}, base.Skills...)
+	for _, skill := range overlay.Skills {
+		replaced := false
+		for i := range out.Skills {
+			if out.Skills[i].Name == skill.Name {
+				out.Skills[i] = skill
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out.Skills = append(out.Skills, skill)
+		}
+	}
+
+	if len(base.Constraints) > 0 || len(overlay.Constraints) > 0 {
+		out.Constraints = map[string]any{}
+		for k, v := range base.Constraints {
+			out.Constraints[k] = v
+		}
+		for k, v := range overlay.Constraints {
+			out.Constraints[k] = v
+		}
+	}
+	return out
+}
+
+// ConstraintKeys returns the constraint names in sorted order.
+func (d *RoleDefinition) ConstraintKeys() []string {
+	keys := make([]string, 0, len(d.Constraints))
+	for k := range d.Constraints {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SkillNames returns the names of the role's skills.
+func (d *RoleDefinition) SkillNames() []string {
+	return roleSkillNames(d.Skills)
+}
+
+func removeItems(items, drop []string) []string {
+	if len(drop) == 0 {
+		return append([]string{}, items...)
+	}
+	dropSet := make(map[string]bool, len(drop))
+	for _, item := range drop {
+		dropSet[item] = true
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if !dropSet[item] {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func trimNonEmpty(items []string) []string {
+	var out []string
+	for _, item := range items {
+		if t := strings.TrimSpace(item); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// systemPromptSection is one "## " section of a system prompt, heading line
+// included.
+type systemPromptSection struct {
+	Heading string
+	Text    string
+}
+
+// splitSystemPromptSections splits a system prompt into the text before the
+// first "## " heading and its level-2 sections. Headings inside fenced code
+// blocks are ignored.
+func splitSystemPromptSections(prompt string) (string, []systemPromptSection) {
+	var preamble strings.Builder
+	var sections []systemPromptSection
+	inFence := false
+	for _, line := range strings.SplitAfter(prompt, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(line, "## ") {
+			sections = append(sections, systemPromptSection{Heading: strings.TrimSpace(strings.TrimPrefix(line, "## "))})
+		}
+		if len(sections) == 0 {
+			preamble.WriteString(line)
+		} else {
+			sections[len(sections)-1].Text += line
+		}
+	}
+	return preamble.String(), sections
+}
+
+// mergeSystemPrompt overlays one system prompt on another section by
+// section.
+func mergeSystemPrompt(base, overlay string) string {
+	if strings.TrimSpace(base) == "" {
+		return overlay
+	}
+	if strings.TrimSpace(overlay) == "" {
+		return base
+	}
+	basePre, baseSections := splitSystemPromptSections(base)
+	overPre, overSections := splitSystemPromptSections(overlay)
+
+	preamble := basePre
+	if strings.TrimSpace(overPre) != "" {
+		preamble = overPre
+	}
+	sections := append([]systemPromptSection{}, baseSections...)
+	for _, s := range overSections {
+		replaced := false
+		for i := range sections {
+			if sections[i].Heading == s.Heading {
+				sections[i] = s
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			sections = append(sections, s)
+		}
+	}
+
+	parts := make([]string, 0, len(sections)+1)
+	if t := strings.TrimRight(preamble, "\n"); strings.TrimSpace(t) != "" {
+		parts = append(parts, t)
+	}
+	for _, s := range sections {
+		parts = append(parts, strings.TrimRight(s.Text, "\n"))
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeRoleFiles(t *testing.T, teamsDir, name, roleYAML, systemMD string) string {
+	t.Helper()
+	dir := filepath.Join(teamsDir, name)
+	if err := os.MkdirAll(filepath.Join(dir, "references"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "references", "role.yaml"), []byte(roleYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: "+name+"\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if systemMD != "" {
+		if err := os.WriteFile(filepath.Join(dir, "system.md"), []byte(systemMD), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestResolveRoleFromPathMergesExtendsAndMixins(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	teams := filepath.Join(t.TempDir(), ".agent-team", "teams")
+	writeRoleFiles(t, teams, "react-dev", `name: react-dev
+description: "React developer"
+scope:
+  in_scope:
+    - "React components"
+    - "Server rendering"
+  out_of_scope:
+    - "Backend APIs"
+constraints:
+  single_role_focus: true
+  max_files: 10
+skills:
+  - name: "react"
+    description: "React patterns"
+  - name: "vite"
+    description: "Vite builds"
+`, "# System Prompt: react-dev\n\nYou build React UIs.\n\n## Stack\n\nReact with Vite.\n\n## Testing\n\nUse vitest.\n")
+	writeRoleFiles(t, teams, "a11y", `name: a11y
+scope:
+  in_scope:
+    - "Accessibility audits"
+skills:
+  - name: "axe"
+    description: "Accessibility checks"
+`, "## Accessibility\n\nFollow WCAG 2.2.\n")
+	leaf := writeRoleFiles(t, teams, "next-react-dev", `name: next-react-dev
+description: "Next.js developer"
+extends: react-dev
+mixins: [a11y]
+scope:
+  in_scope:
+    - "Next.js routing"
+  out_of_scope:
+    - "Server rendering"
+constraints:
+  max_files: 20
+skills:
+  - name: "vite"
+    description: "Not used; Next bundles"
+  - name: "next"
+    description: "Next.js patterns"
+`, "## Stack\n\nNext.js app router.\n")
+
+	role, err := ResolveRoleFromPath(leaf)
+	if err != nil {
+		t.Fatalf("ResolveRoleFromPath: %v", err)
+	}
+	if role.Name != "next-react-dev" || role.Description != "Next.js developer" || role.Extends != "react-dev" {
+		t.Fatalf("unexpected identity: %+v", role)
+	}
+	if want := []string{"react-dev", "a11y", "next-react-dev"}; !reflect.DeepEqual(role.Chain, want) {
+		t.Fatalf("Chain = %v, want %v", role.Chain, want)
+	}
+	if want := []string{"React components", "Accessibility audits", "Next.js routing"}; !reflect.DeepEqual(role.InScope, want) {
+		t.Fatalf("InScope = %v, want %v", role.InScope, want)
+	}
+	if want := []string{"Backend APIs", "Server rendering"}; !reflect.DeepEqual(role.OutOfScope, want) {
+		t.Fatalf("OutOfScope = %v, want %v", role.OutOfScope, want)
+	}
+	if want := []string{"react", "vite", "axe", "next"}; !reflect.DeepEqual(role.SkillNames(), want) {
+		t.Fatalf("skills = %v, want %v", role.SkillNames(), want)
+	}
+	if role.Skills[1].Description != "Not used; Next bundles" {
+		t.Fatalf("child skill description should win: %+v", role.Skills[1])
+	}
+	if role.Constraints["max_files"] != 20 || role.Constraints["single_role_focus"] != true {
+		t.Fatalf("Constraints = %v", role.Constraints)
+	}
+	wantPrompt := "# System Prompt: react-dev\n\nYou build React UIs.\n\n## Stack\n\nNext.js app router.\n\n## Testing\n\nUse vitest.\n\n## Accessibility\n\nFollow WCAG 2.2.\n"
+	if role.SystemPrompt != wantPrompt {
+		t.Fatalf("SystemPrompt =\n%s\nwant\n%s", role.SystemPrompt, wantPrompt)
+	}
+
+	skills, err := ReadRoleSkillsFromPath(leaf)
+	if err != nil || !reflect.DeepEqual(skills, []string{"react", "vite", "axe", "next"}) {
+		t.Fatalf("ReadRoleSkillsFromPath = %v, %v", skills, err)
+	}
+
+	wt := t.TempDir()
+	if err := InjectRolePromptWithPath(wt, "next-react-dev-001", "next-react-dev", leaf, filepath.Dir(filepath.Dir(teams))); err != nil {
+		t.Fatalf("InjectRolePromptWithPath: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(wt, "CLAUDE.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "You build React UIs.") || !strings.Contains(string(data), "Follow WCAG 2.2.") || strings.Contains(string(data), "React with Vite.") {
+		t.Fatalf("injected prompt not resolved:\n%s", data)
+	}
+}
+
+func TestResolveRoleFromPathErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	teams := t.TempDir()
+	writeRoleFiles(t, teams, "a", "name: a\nextends: b\n", "")
+	writeRoleFiles(t, teams, "b", "name: b\nmixins: [c]\n", "")
+	writeRoleFiles(t, teams, "c", "name: c\nextends: a\n", "")
+	if _, err := ResolveRoleFromPath(filepath.Join(teams, "a")); err == nil || !strings.Contains(err.Error(), "cycle: a -> b -> c -> a") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+
+	writeRoleFiles(t, teams, "orphan", "name: orphan\nextends: missing\n", "")
+	if _, err := ResolveRoleFromPath(filepath.Join(teams, "orphan")); err == nil || !strings.Contains(err.Error(), `parent role "missing" not found`) {
+		t.Fatalf("expected missing parent error, got %v", err)
+	}
+
+	// A role mixed in twice through different parents is not a cycle.
+	writeRoleFiles(t, teams, "base", "name: base\nskills:\n  - name: go\n    description: Go\n", "")
+	writeRoleFiles(t, teams, "left", "name: left\nextends: base\n", "")
+	writeRoleFiles(t, teams, "diamond", "name: diamond\nextends: left\nmixins: [base]\n", "")
+	role, err := ResolveRoleFromPath(filepath.Join(teams, "diamond"))
+	if err != nil {
+		t.Fatalf("diamond: %v", err)
+	}
+	if !reflect.DeepEqual(role.Chain, []string{"base", "left", "diamond"}) || !reflect.DeepEqual(role.SkillNames(), []string{"go"}) {
+		t.Fatalf("diamond resolved to %+v", role)
+	}
+}
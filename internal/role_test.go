@@ -68,7 +68,8 @@ func TestListWorkers(t *testing.T) {
 
 	duplicateDir := filepath.Join(dir, wtBase, "backend-001")
 	os.MkdirAll(duplicateDir, 0755)
-	duplicate := &WorkerConfig{WorkerID: "backend-001", Role: "legacy-duplicate", Provider: "claude"}
+	// Same file as the centralized config; replacing it requires its revision.
+	duplicate := &WorkerConfig{WorkerID: "backend-001", Role: "legacy-duplicate", Provider: "claude", Revision: central.Revision}
 	if err := duplicate.Save(WorkerYAMLPath(duplicateDir)); err != nil {
 		t.Fatalf("save duplicate legacy config: %v", err)
 	}
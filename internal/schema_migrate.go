@@ -0,0 +1,235 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaKind identifies a versioned .agent-team artifact type.
+type SchemaKind string
+
+const (
+	SchemaKindTask         SchemaKind = "task"
+	SchemaKindWorker       SchemaKind = "worker"
+	SchemaKindPlanning     SchemaKind = "planning"
+	SchemaKindWorkflowPlan SchemaKind = "workflow_plan"
+)
+
+// currentSchemaVersions is the schema version written for each artifact kind.
+var currentSchemaVersions = map[SchemaKind]int{
+	SchemaKindTask:         1,
+	SchemaKindWorker:       1,
+	SchemaKindPlanning:     2,
+	SchemaKindWorkflowPlan: 1,
+}
+
+// CurrentSchemaVersion returns the schema version written for kind.
+func CurrentSchemaVersion(kind SchemaKind) int {
+	return currentSchemaVersions[kind]
+}
+
+// SchemaMigration upgrades one artifact kind from version From to From+1.
+// Apply edits the top-level YAML mapping in place.
+type SchemaMigration struct {
+	Kind        SchemaKind
+	From        int
+	Description string
+	Apply       func(doc *yaml.Node) error
+}
+
+var schemaMigrations = []SchemaMigration{
+	{
+		Kind:        SchemaKindTask,
+		From:        0,
+		Description: "map legacy status 'done' to 'verifying' and done_at to verifying_at",
+		Apply: func(doc *yaml.Node) error {
+			doneAt := yamlMappingValue(doc, "done_at")
+			if status := yamlMappingValue(doc, "status"); status != nil && status.Value == "done" {
+				status.Value = string(TaskStatusVerifying)
+				if doneAt != nil && yamlMappingString(doc, "verifying_at") == "" {
+					setYAMLMappingString(doc, "verifying_at", doneAt.Value)
+				}
+			}
+			deleteYAMLMappingKey(doc, "done_at")
+			return nil
+		},
+	},
+	{
+		Kind:        SchemaKindWorker,
+		From:        0,
+		Description: "map legacy worker status 'done' to 'verifying'",
+		Apply: func(doc *yaml.Node) error {
+			if status := yamlMappingValue(doc, "status"); status != nil && status.Value == "done" {
+				status.Value = string(TaskStatusVerifying)
+			}
+			return nil
+		},
+	},
+	{
+		Kind:        SchemaKindPlanning,
+		From:        0,
+		Description: "default missing status to 'proposed'",
+		Apply: func(doc *yaml.Node) error {
+			// A missing lifecycle is left for LoadPlanningRecord, which takes
+			// it from the directory the record lives in.
+			if yamlMappingString(doc, "status") == "" {
+				setYAMLMappingString(doc, "status", "proposed")
+			}
+			return nil
+		},
+	},
+	{
+		Kind:        SchemaKindPlanning,
+		From:        1,
+		Description: "normalize free-form status to proposed/active/done/cancelled",
+		Apply: func(doc *yaml.Node) error {
+			status := yamlMappingValue(doc, "status")
+			if status == nil {
+				return nil
+			}
+			status.Value = string(normalizeLegacyPlanningStatus(status.Value))
+			return nil
+		},
+	},
+	{
+		Kind:        SchemaKindWorkflowPlan,
+		From:        0,
+		Description: "stamp schema version",
+		Apply:       func(doc *yaml.Node) error { return nil },
+	},
+}
+
+// SchemaMigrationResult describes the upgrade applied to one document.
+type SchemaMigrationResult struct {
+	Kind        SchemaKind
+	FromVersion int
+	ToVersion   int
+	Applied     []string
+	Data        []byte
+}
+
+// Changed reports whether the document needed any migration.
+func (r SchemaMigrationResult) Changed() bool {
+	return r.FromVersion != r.ToVersion
+}
+
+// MigrateSchema upgrades raw YAML for kind to the current schema version.
+// When no migration is needed the original bytes are returned unchanged.
+func MigrateSchema(kind SchemaKind, data []byte) (SchemaMigrationResult, error) {
+	target, ok := currentSchemaVersions[kind]
+	if !ok {
+		return SchemaMigrationResult{}, fmt.Errorf("unknown schema kind: %s", kind)
+	}
+	result := SchemaMigrationResult{Kind: kind, Data: data}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return result, fmt.Errorf("parse %s: %w", kind, err)
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return result, fmt.Errorf("parse %s: expected a mapping document", kind)
+	}
+	doc := root.Content[0]
+
+	version := 0
+	if raw := yamlMappingString(doc, "schema_version"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return result, fmt.Errorf("invalid %s schema_version: %s", kind, raw)
+		}
+		version = v
+	}
+	result.FromVersion = version
+	result.ToVersion = version
+	if version > target {
+		return result, fmt.Errorf("%s schema_version %d is newer than supported version %d; upgrade agent-team", kind, version, target)
+	}
+	if version == target {
+		return result, nil
+	}
+
+	for version < target {
+		migration, ok := findSchemaMigration(kind, version)
+		if !ok {
+			return result, fmt.Errorf("no %s migration registered from schema_version %d", kind, version)
+		}
+		if err := migration.Apply(doc); err != nil {
+			return result, fmt.Errorf("migrate %s from schema_version %d: %w", kind, version, err)
+		}
+		result.Applied = append(result.Applied, migration.Description)
+		version++
+	}
+	setYAMLMappingInt(doc, "schema_version", version)
+	result.ToVersion = version
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(&root); err != nil {
+		return result, fmt.Errorf("encode migrated %s: %w", kind, err)
+	}
+	if err := enc.Close(); err != nil {
+		return result, fmt.Errorf("encode migrated %s: %w", kind, err)
+	}
+	result.Data = buf.Bytes()
+	return result, nil
+}
+
+// decodeMigratedYAML migrates data for kind and decodes it into out.
+func decodeMigratedYAML(kind SchemaKind, data []byte, out any) error {
+	result, err := MigrateSchema(kind, data)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(result.Data, out)
+}
+
+func findSchemaMigration(kind SchemaKind, from int) (SchemaMigration, bool) {
+	for _, migration := range schemaMigrations {
+		if migration.Kind == kind && migration.From == from {
+			return migration, true
+		}
+	}
+	return SchemaMigration{}, false
+}
+
+func yamlMappingString(doc *yaml.Node, key string) string {
+	if node := yamlMappingValue(doc, key); node != nil && node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	return ""
+}
+
+func setYAMLMappingString(doc *yaml.Node, key, value string) {
+	setYAMLMappingScalar(doc, key, value, "!!str")
+}
+
+func setYAMLMappingInt(doc *yaml.Node, key string, value int) {
+	setYAMLMappingScalar(doc, key, strconv.Itoa(value), "!!int")
+}
+
+func setYAMLMappingScalar(doc *yaml.Node, key, value, tag string) {
+	if node := yamlMappingValue(doc, key); node != nil {
+		node.Kind = yaml.ScalarNode
+		node.Tag = tag
+		node.Value = value
+		node.Content = nil
+		return
+	}
+	doc.Content = append(doc.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value},
+	)
+}
+
+func deleteYAMLMappingKey(doc *yaml.Node, key string) {
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value == key {
+			doc.Content = append(doc.Content[:i], doc.Content[i+2:]...)
+			return
+		}
+	}
+}
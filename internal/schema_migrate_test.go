@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTaskRecordMigratesLegacyDone(t *testing.T) {
+	root := t.TempDir()
+	dir := TaskDir(root, "legacy-task")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	legacy := "task_id: legacy-task\ntitle: Legacy\nrole: backend\nstatus: done\ntask_path: .agent-team/task/legacy-task\ncreated_at: \"2026-03-01T00:00:00Z\"\ndone_at: \"2026-03-02T00:00:00Z\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "task.yaml"), []byte(legacy), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	record, _, err := LoadTaskRecord(root, "legacy-task")
+	if err != nil {
+		t.Fatalf("LoadTaskRecord: %v", err)
+	}
+	if record.Status != TaskStatusVerifying || record.VerifyingAt != "2026-03-02T00:00:00Z" {
+		t.Fatalf("record = %+v", record)
+	}
+	if record.SchemaVersion != CurrentSchemaVersion(SchemaKindTask) {
+		t.Fatalf("SchemaVersion = %d", record.SchemaVersion)
+	}
+}
+
+func TestMigrateSchemaRejectsNewerVersion(t *testing.T) {
+	_, err := MigrateSchema(SchemaKindTask, []byte("schema_version: 99\ntask_id: x\n"))
+	if err == nil || !strings.Contains(err.Error(), "newer than supported") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestMigrateStateTreeDryRunAndApply(t *testing.T) {
+	root := t.TempDir()
+	wtBase := ".worktrees"
+	taskDir := TaskDir(root, "legacy-task")
+	if err := os.MkdirAll(taskDir, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	taskPath := filepath.Join(taskDir, "task.yaml")
+	if err := os.WriteFile(taskPath, []byte("task_id: legacy-task\nstatus: done\n"), 0644); err != nil {
+		t.Fatalf("WriteFile task: %v", err)
+	}
+	workerPath := WorkerYAMLPath(filepath.Join(root, wtBase, "backend-001"))
+	if err := os.MkdirAll(filepath.Dir(workerPath), 0755); err != nil {
+		t.Fatalf("MkdirAll worker: %v", err)
+	}
+	if err := os.WriteFile(workerPath, []byte("# local worker\nworker_id: backend-001\nrole: backend\n"), 0644); err != nil {
+		t.Fatalf("WriteFile worker: %v", err)
+	}
+
+	report, err := MigrateStateTree(root, wtBase, true)
+	if err != nil {
+		t.Fatalf("MigrateStateTree dry-run: %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("dry-run report = %+v", report)
+	}
+	data, _ := os.ReadFile(taskPath)
+	if strings.Contains(string(data), "schema_version") {
+		t.Fatalf("dry-run rewrote task.yaml: %s", data)
+	}
+
+	if _, err := MigrateStateTree(root, wtBase, false); err != nil {
+		t.Fatalf("MigrateStateTree: %v", err)
+	}
+	data, _ = os.ReadFile(taskPath)
+	if !strings.Contains(string(data), "schema_version: 1") || !strings.Contains(string(data), "status: verifying") {
+		t.Fatalf("task.yaml not migrated: %s", data)
+	}
+	data, _ = os.ReadFile(workerPath)
+	if !strings.HasPrefix(string(data), "# local worker") {
+		t.Fatalf("worker.yaml lost its comment: %s", data)
+	}
+
+	report, err = MigrateStateTree(root, wtBase, true)
+	if err != nil {
+		t.Fatalf("MigrateStateTree rerun: %v", err)
+	}
+	if len(report) != 0 {
+		t.Fatalf("expected no pending migrations, got %+v", report)
+	}
+}
+
+func TestMigrateStateTreeKeepsLegacyPlanningLifecycle(t *testing.T) {
+	root := t.TempDir()
+	path := PlanningYAMLPath(root, PlanningKindRoadmap, "legacy-roadmap", PlanningLifecycleArchived)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("id: legacy-roadmap\nkind: roadmap\ntitle: Legacy\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := MigrateStateTree(root, ".worktrees", false); err != nil {
+		t.Fatalf("MigrateStateTree: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "lifecycle: planning") {
+		t.Fatalf("archived record moved back to planning: %s", data)
+	}
+	record, err := LoadPlanningRecord(root, "legacy-roadmap")
+	if err != nil {
+		t.Fatalf("LoadPlanningRecord: %v", err)
+	}
+	if record.Lifecycle != PlanningLifecycleArchived || record.Status != "proposed" {
+		t.Fatalf("record = %+v", record)
+	}
+}
@@ -0,0 +1,403 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SearchKind is the type of artifact a search document belongs to.
+type SearchKind string
+
+const (
+	SearchKindTask     SearchKind = "task"
+	SearchKindPlanning SearchKind = "planning"
+	SearchKindRule     SearchKind = "rule"
+)
+
+const searchIndexVersion = 1
+
+// SearchDoc is one indexed file. Metadata fields come from the owning task or
+// planning record and are refreshed on every index update; Terms is only
+// recomputed when the file's size or mtime changes.
+type SearchDoc struct {
+	Path    string     `json:"path"`
+	Kind    SearchKind `json:"kind"`
+	ID      string     `json:"id"`
+	Title   string     `json:"title,omitempty"`
+	Status  string     `json:"status,omitempty"`
+	Role    string     `json:"role,omitempty"`
+	ModTime int64      `json:"mod_time"`
+	Size    int64      `json:"size"`
+	Length  int        `json:"length"`
+	Terms   []string   `json:"terms"`
+}
+
+// SearchIndex is the on-disk inverted index: Postings maps a term to the
+// term frequency in each document path.
+type SearchIndex struct {
+	Version  int                       `json:"version"`
+	Docs     map[string]*SearchDoc     `json:"docs"`
+	Postings map[string]map[string]int `json:"postings"`
+}
+
+// SearchIndexStats reports what an index update touched.
+type SearchIndexStats struct {
+	Indexed int
+	Removed int
+	Total   int
+}
+
+// SearchQuery filters and limits a search. Zero values match everything.
+type SearchQuery struct {
+	Text   string
+	Kind   SearchKind
+	Status string
+	Role   string
+	Since  time.Time
+	Limit  int
+}
+
+// SearchResult is a ranked match with the first matching line as snippet.
+type SearchResult struct {
+	Doc     *SearchDoc
+	Score   float64
+	Line    int
+	Snippet string
+}
+
+// SearchIndexPath returns .agent-team/.cache/search-index.json.
+func SearchIndexPath(root string) string {
+	return filepath.Join(AgentTeamDir(root), ".cache", "search-index.json")
+}
+
+// UpdateSearchIndex loads the index, re-tokenizes files whose size or mtime
+// changed, drops files that no longer exist, and saves it. rebuild discards
+// the stored index first.
+func UpdateSearchIndex(root string, rebuild bool) (*SearchIndex, SearchIndexStats, error) {
+	idx := &SearchIndex{Version: searchIndexVersion, Docs: map[string]*SearchDoc{}, Postings: map[string]map[string]int{}}
+	if !rebuild {
+		if data, err := os.ReadFile(SearchIndexPath(root)); err == nil {
+			var stored SearchIndex
+			if json.Unmarshal(data, &stored) == nil && stored.Version == searchIndexVersion && stored.Docs != nil && stored.Postings != nil {
+				idx = &stored
+			}
+		}
+	}
+
+	var stats SearchIndexStats
+	seen := map[string]bool{}
+	for _, doc := range collectSearchDocs(root) {
+		seen[doc.Path] = true
+		info, err := os.Stat(filepath.Join(root, doc.Path))
+		if err != nil {
+			continue
+		}
+		doc.ModTime, doc.Size = info.ModTime().UnixNano(), info.Size()
+		if old := idx.Docs[doc.Path]; old != nil && old.ModTime == doc.ModTime && old.Size == doc.Size {
+			doc.Length, doc.Terms = old.Length, old.Terms
+			idx.Docs[doc.Path] = doc
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, doc.Path))
+		if err != nil {
+			continue
+		}
+		idx.remove(doc.Path)
+		tokens := tokenizeSearchText(string(data))
+		freq := map[string]int{}
+		for _, token := range tokens {
+			freq[token]++
+		}
+		doc.Length = len(tokens)
+		doc.Terms = make([]string, 0, len(freq))
+		for term, n := range freq {
+			doc.Terms = append(doc.Terms, term)
+			if idx.Postings[term] == nil {
+				idx.Postings[term] = map[string]int{}
+			}
+			idx.Postings[term][doc.Path] = n
+		}
+		sort.Strings(doc.Terms)
+		idx.Docs[doc.Path] = doc
+		stats.Indexed++
+	}
+	for path := range idx.Docs {
+		if !seen[path] {
+			idx.remove(path)
+			stats.Removed++
+		}
+	}
+	stats.Total = len(idx.Docs)
+
+	if stats.Indexed > 0 || stats.Removed > 0 || rebuild {
+		if err := idx.save(root); err != nil {
+			return idx, stats, err
+		}
+	}
+	return idx, stats, nil
+}
+
+func (idx *SearchIndex) remove(path string) {
+	doc := idx.Docs[path]
+	if doc == nil {
+		return
+	}
+	for _, term := range doc.Terms {
+		delete(idx.Postings[term], path)
+		if len(idx.Postings[term]) == 0 {
+			delete(idx.Postings, term)
+		}
+	}
+	delete(idx.Docs, path)
+}
+
+func (idx *SearchIndex) save(root string) error {
+	path := SearchIndexPath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create search cache directory: %w", err)
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("marshal search index: %w", err)
+	}
+	if err := WriteFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("write search index: %w", err)
+	}
+	return ensureStateIgnoreEntry(root, ".cache/")
+}
+
+// Search ranks documents containing every query term with BM25, boosting
+// title matches, and attaches the first matching line as a snippet.
+func (idx *SearchIndex) Search(root string, q SearchQuery) []SearchResult {
+	terms := uniqueStrings(tokenizeSearchText(q.Text))
+	if len(terms) == 0 {
+		return nil
+	}
+	candidates := map[string]bool{}
+	for path := range idx.Postings[terms[0]] {
+		candidates[path] = true
+	}
+	for _, term := range terms[1:] {
+		for path := range candidates {
+			if _, ok := idx.Postings[term][path]; !ok {
+				delete(candidates, path)
+			}
+		}
+	}
+
+	total, avgLen := float64(len(idx.Docs)), 0.0
+	for _, doc := range idx.Docs {
+		avgLen += float64(doc.Length)
+	}
+	if total > 0 {
+		avgLen /= total
+	}
+	const k1, b = 1.2, 0.75
+
+	var results []SearchResult
+	for path := range candidates {
+		doc := idx.Docs[path]
+		if doc == nil || !q.matches(doc) {
+			continue
+		}
+		titleTerms := uniqueStrings(tokenizeSearchText(doc.Title))
+		score := 0.0
+		for _, term := range terms {
+			postings := idx.Postings[term]
+			tf := float64(postings[path])
+			idf := math.Log(1 + (total-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+			norm := 1.0
+			if avgLen > 0 {
+				norm = 1 - b + b*float64(doc.Length)/avgLen
+			}
+			score += idf * tf * (k1 + 1) / (tf + k1*norm)
+			if containsString(titleTerms, term) {
+				score += idf
+			}
+		}
+		line, snippet := searchSnippet(filepath.Join(root, path), terms)
+		results = append(results, SearchResult{Doc: doc, Score: score, Line: line, Snippet: snippet})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Doc.Path < results[j].Doc.Path
+	})
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+	return results
+}
+
+func (q SearchQuery) matches(doc *SearchDoc) bool {
+	if q.Kind != "" && doc.Kind != q.Kind {
+		return false
+	}
+	if q.Status != "" && !strings.EqualFold(doc.Status, q.Status) {
+		return false
+	}
+	if q.Role != "" && doc.Role != q.Role {
+		return false
+	}
+	if !q.Since.IsZero() && time.Unix(0, doc.ModTime).Before(q.Since) {
+		return false
+	}
+	return true
+}
+
+// collectSearchDocs lists every indexable file with its owner's metadata.
+func collectSearchDocs(root string) []*SearchDoc {
+	var docs []*SearchDoc
+	for _, base := range []string{TasksRootDir(root), TasksArchiveRootDir(root), TasksDeprecatedRootDir(root)} {
+		entries, _ := os.ReadDir(base)
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			dir := filepath.Join(base, entry.Name())
+			record, err := loadTaskRecordFromDir(dir)
+			if err != nil {
+				continue
+			}
+			meta := SearchDoc{Kind: SearchKindTask, ID: record.TaskID, Title: record.Title, Status: string(record.Status), Role: record.Role}
+			docs = append(docs, searchDocsInDir(root, dir, meta)...)
+		}
+	}
+	for _, lifecycle := range []PlanningLifecycle{PlanningLifecycleActive, PlanningLifecycleArchived, PlanningLifecycleDeprecated} {
+		for _, kind := range []PlanningKind{PlanningKindRoadmap, PlanningKindMilestone, PlanningKindPhase} {
+			base := PlanningKindRootDir(root, kind, lifecycle)
+			entries, _ := os.ReadDir(base)
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					continue
+				}
+				record, err := LoadPlanningRecord(root, entry.Name())
+				if err != nil {
+					continue
+				}
+				meta := SearchDoc{Kind: SearchKindPlanning, ID: record.ID, Title: record.Title, Status: string(record.Status)}
+				docs = append(docs, searchDocsInDir(root, filepath.Join(base, entry.Name()), meta)...)
+			}
+		}
+	}
+	_ = filepath.WalkDir(RulesRootDir(root), func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return nil
+		}
+		ruleID, _ := filepath.Rel(RulesRootDir(root), path)
+		docs = append(docs, &SearchDoc{Path: filepath.ToSlash(rel), Kind: SearchKindRule, ID: filepath.ToSlash(ruleID), Title: markdownTitle(path)})
+		return nil
+	})
+	return docs
+}
+
+func searchDocsInDir(root, dir string, meta SearchDoc) []*SearchDoc {
+	entries, _ := os.ReadDir(dir)
+	var docs []*SearchDoc
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".md" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		rel, err := filepath.Rel(root, filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		doc := meta
+		doc.Path = filepath.ToSlash(rel)
+		docs = append(docs, &doc)
+	}
+	return docs
+}
+
+func markdownTitle(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if title, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "# "); ok {
+			return strings.TrimSpace(title)
+		}
+	}
+	return ""
+}
+
+// tokenizeSearchText lowercases text and splits it into words; Han, Hiragana,
+// Katakana and Hangul characters are indexed one character per token.
+func tokenizeSearchText(text string) []string {
+	var tokens []string
+	var word []rune
+	flush := func() {
+		if len(word) > 1 {
+			tokens = append(tokens, string(word))
+		}
+		word = word[:0]
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			flush()
+			tokens = append(tokens, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+// searchSnippet returns the first line containing any of terms.
+func searchSnippet(path string, terms []string) (int, string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, ""
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		for _, token := range tokenizeSearchText(text) {
+			if containsString(terms, token) {
+				return line, truncateSnippet(text, 160)
+			}
+		}
+	}
+	return 0, ""
+}
+
+func truncateSnippet(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
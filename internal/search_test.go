@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTokenizeSearchText(t *testing.T) {
+	got := tokenizeSearchText("Fix the OAuth2 login-flow, 修复登录 a")
+	want := []string{"fix", "the", "oauth2", "login", "flow", "修", "复", "登", "录"}
+	if len(got) != len(want) {
+		t.Fatalf("tokens = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("tokens = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSearchIndexRanksAndFilters(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	login, err := CreateTaskPackage(root, "Login rate limiting", "backend", "", now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	other, err := CreateTaskPackage(root, "Dashboard charts", "frontend", "", now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	if err := os.WriteFile(TaskContextPath(root, other.TaskID), []byte("# Context\n\nCharts must respect login rate limiting errors.\n"), 0644); err != nil {
+		t.Fatalf("write context: %v", err)
+	}
+	if err := os.MkdirAll(RulesRootDir(root), 0755); err != nil {
+		t.Fatalf("mkdir rules: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(RulesRootDir(root), "security.md"), []byte("# Security\n\nAlways apply rate limiting to login endpoints.\n"), 0644); err != nil {
+		t.Fatalf("write rule: %v", err)
+	}
+
+	idx, stats, err := UpdateSearchIndex(root, false)
+	if err != nil {
+		t.Fatalf("UpdateSearchIndex: %v", err)
+	}
+	if stats.Indexed == 0 || stats.Indexed != stats.Total {
+		t.Fatalf("first update stats = %+v", stats)
+	}
+
+	results := idx.Search(root, SearchQuery{Text: "login rate limiting"})
+	if len(results) < 3 {
+		t.Fatalf("results = %d, want at least 3", len(results))
+	}
+	if results[0].Doc.ID != login.TaskID {
+		t.Fatalf("top result = %s, want title match %s", results[0].Doc.ID, login.TaskID)
+	}
+
+	ruleOnly := idx.Search(root, SearchQuery{Text: "login", Kind: SearchKindRule})
+	if len(ruleOnly) != 1 || ruleOnly[0].Doc.ID != "security.md" || ruleOnly[0].Line != 3 {
+		t.Fatalf("rule results = %+v", ruleOnly)
+	}
+	frontend := idx.Search(root, SearchQuery{Text: "login", Role: "frontend"})
+	if len(frontend) == 0 || frontend[0].Doc.ID != other.TaskID || frontend[0].Snippet != "Charts must respect login rate limiting errors." {
+		t.Fatalf("frontend results = %+v", frontend)
+	}
+	if got := idx.Search(root, SearchQuery{Text: "login", Status: "archived"}); len(got) != 0 {
+		t.Fatalf("archived results = %+v", got)
+	}
+	if got := idx.Search(root, SearchQuery{Text: "login", Since: time.Now().Add(time.Hour)}); len(got) != 0 {
+		t.Fatalf("future --since results = %+v", got)
+	}
+}
+
+func TestUpdateSearchIndexIsIncremental(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(RulesRootDir(root), 0755); err != nil {
+		t.Fatalf("mkdir rules: %v", err)
+	}
+	keep := filepath.Join(RulesRootDir(root), "keep.md")
+	drop := filepath.Join(RulesRootDir(root), "drop.md")
+	for _, path := range []string{keep, drop} {
+		if err := os.WriteFile(path, []byte("# Rule\n\noriginal wording\n"), 0644); err != nil {
+			t.Fatalf("write rule: %v", err)
+		}
+	}
+	if _, _, err := UpdateSearchIndex(root, false); err != nil {
+		t.Fatalf("UpdateSearchIndex: %v", err)
+	}
+	if _, stats, err := UpdateSearchIndex(root, false); err != nil || stats.Indexed != 0 {
+		t.Fatalf("unchanged update stats = %+v, err = %v", stats, err)
+	}
+
+	if err := os.WriteFile(keep, []byte("# Rule\n\nrevised wording\n"), 0644); err != nil {
+		t.Fatalf("rewrite rule: %v", err)
+	}
+	if err := os.Remove(drop); err != nil {
+		t.Fatalf("remove rule: %v", err)
+	}
+	idx, stats, err := UpdateSearchIndex(root, false)
+	if err != nil {
+		t.Fatalf("UpdateSearchIndex: %v", err)
+	}
+	if stats.Indexed != 1 || stats.Removed != 1 || stats.Total != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if got := idx.Search(root, SearchQuery{Text: "original"}); len(got) != 0 {
+		t.Fatalf("stale results = %+v", got)
+	}
+	if got := idx.Search(root, SearchQuery{Text: "revised"}); len(got) != 1 {
+		t.Fatalf("revised results = %+v", got)
+	}
+	data, err := os.ReadFile(filepath.Join(AgentTeamDir(root), ".gitignore"))
+	if err != nil || string(data) != ".cache/\n" {
+		t.Fatalf(".gitignore = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultServeAddr binds the controller API to loopback only.
+const DefaultServeAddr = "127.0.0.1:8788"
+
+// ServeConfig is the structure of .agent-team/serve.yaml.
+type ServeConfig struct {
+	Version int    `yaml:"version"`
+	Addr    string `yaml:"addr"`
+	Token   string `yaml:"token"`
+	// AllowedOrigins lists browser origins (e.g. "http://localhost:5173")
+	// that may call the API cross-origin.
+	AllowedOrigins []string `yaml:"allowed_origins,omitempty"`
+}
+
+// ServeConfigPath returns the config path under .agent-team/.
+func ServeConfigPath(root string) string {
+	return filepath.Join(AgentTeamDir(root), "serve.yaml")
+}
+
+// LoadServeConfig loads serve.yaml or returns defaults (without a token) if missing.
+func LoadServeConfig(root string) (*ServeConfig, error) {
+	data, err := os.ReadFile(ServeConfigPath(root))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &ServeConfig{Version: 1, Addr: DefaultServeAddr}, nil
+		}
+		return nil, fmt.Errorf("read serve config: %w", err)
+	}
+	var cfg ServeConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse serve config: %w", err)
+	}
+	if cfg.Version == 0 {
+		cfg.Version = 1
+	}
+	if strings.TrimSpace(cfg.Addr) == "" {
+		cfg.Addr = DefaultServeAddr
+	}
+	return &cfg, nil
+}
+
+// EnsureServeConfig writes serve.yaml with a freshly generated token if it is
+// missing, and keeps the file out of git since it holds a credential.
+func EnsureServeConfig(root string) (*ServeConfig, error) {
+	path := ServeConfigPath(root)
+	if _, err := os.Stat(path); err == nil {
+		return LoadServeConfig(root)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("stat serve config: %w", err)
+	}
+
+	token, err := generateServeToken()
+	if err != nil {
+		return nil, err
+	}
+	cfg := ServeConfig{Version: 1, Addr: DefaultServeAddr, Token: token}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create serve config dir: %w", err)
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal serve config: %w", err)
+	}
+	if err := WriteFileAtomic(path, data, 0600); err != nil {
+		return nil, fmt.Errorf("write serve config: %w", err)
+	}
+	if err := ensureStateIgnoreEntry(root, "serve.yaml"); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func generateServeToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate serve token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// ensureStateIgnoreEntry appends entry to .agent-team/.gitignore when absent.
+func ensureStateIgnoreEntry(root, entry string) error {
+	path := filepath.Join(AgentTeamDir(root), ".gitignore")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read .agent-team/.gitignore: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == entry {
+			return nil
+		}
+	}
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += entry + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("write .agent-team/.gitignore: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,563 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// skillNamePattern matches names that are safe as a directory under the
+// project skill cache: no separators, no leading dot or dash.
+var skillNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func validateSkillName(name string) error {
+	if !skillNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid skill name %q", name)
+	}
+	return nil
+}
+
+// SkillSpec identifies a skill package: a role-repo style source plus the
+// skill to take from it. Name is the directory the skill is installed as.
+type SkillSpec struct {
+	Spec   string
+	Source RoleRepoSource
+	Name   string
+	Ref    string
+	// Path is the skill directory inside the source, when already known
+	// (from skills-lock.json); otherwise the skill is located by Name.
+	Path string
+	// Explicit is set when the skill was named with @<skill>. A source
+	// holding a single skill only satisfies an implicit name.
+	Explicit bool
+}
+
+// ParseSkillSpec parses <source>[@<skill>], where source is anything
+// role-repo accepts (owner/repo, a git remote, a GitLab/Gitea URL or a local
+// path). Without @<skill> the skill is named after the repository or
+// directory, so "antfu/skills@vite" and "better-auth/better-icons" both
+// work as in `npx skills`. Refs are passed separately because @ selects
+// the skill here; ref overrides one embedded as <source>@<ref>@<skill>.
+func ParseSkillSpec(baseDir, spec, ref string) (SkillSpec, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return SkillSpec{}, fmt.Errorf("empty skill spec")
+	}
+	sourcePart, name := spec, ""
+	if i := strings.LastIndex(spec, "@"); i > 0 && !skillSpecIsDir(baseDir, spec) {
+		before, after := spec[:i], spec[i+1:]
+		if _, rest, ok := strings.Cut(before, "://"); ok {
+			before = rest
+		}
+		if after != "" && !strings.ContainsAny(after, "/:") && strings.Contains(before, "/") {
+			sourcePart, name = spec[:i], after
+		}
+	}
+	source, err := ParseRoleRepoSourceIn(baseDir, sourcePart)
+	if err != nil {
+		return SkillSpec{}, err
+	}
+	if ref == "" {
+		ref = source.Ref
+	}
+	if ref != "" && source.Type == RoleRepoSourceLocal {
+		return SkillSpec{}, fmt.Errorf("local source %s does not support refs", source.FullName())
+	}
+	if ref != "" {
+		if err := ValidateRoleRepoRef(ref); err != nil {
+			return SkillSpec{}, err
+		}
+	}
+	source.Ref = ""
+	explicit := name != ""
+	if !explicit {
+		name = skillSourceBaseName(source)
+	}
+	if err := validateSkillName(name); err != nil {
+		if explicit {
+			return SkillSpec{}, err
+		}
+		return SkillSpec{}, fmt.Errorf("cannot derive a skill name from %q; use <source>@<skill>", spec)
+	}
+	return SkillSpec{Spec: spec, Source: source, Name: name, Ref: ref, Explicit: explicit}, nil
+}
+
+func skillSpecIsDir(baseDir, spec string) bool {
+	dir := strings.TrimPrefix(spec, "file://")
+	if baseDir != "" && !filepath.IsAbs(dir) {
+		dir = filepath.Join(baseDir, dir)
+	}
+	st, err := os.Stat(dir)
+	return err == nil && st.IsDir()
+}
+
+// skillSourceBaseName is the repository or directory name of a source.
+func skillSourceBaseName(source RoleRepoSource) string {
+	switch source.Type {
+	case RoleRepoSourceLocal:
+		return filepath.Base(source.Path)
+	case RoleRepoSourceGit:
+		url := strings.TrimSuffix(strings.TrimRight(source.CloneURL, "/"), ".git")
+		if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+			url = url[i+1:]
+		}
+		return url
+	default:
+		return path.Base(source.Repo)
+	}
+}
+
+// SkillSpecFromLock rebuilds the spec a lock entry was installed from.
+func SkillSpecFromLock(providers *RoleRepoProviderSet, name string, entry SkillsLockEntry) (SkillSpec, error) {
+	if err := validateSkillName(name); err != nil {
+		return SkillSpec{}, fmt.Errorf("%s: %w", SkillsLockFileName, err)
+	}
+	if entry.Source == "" {
+		return SkillSpec{}, fmt.Errorf("skill %s has no source in %s", name, SkillsLockFileName)
+	}
+	if entry.Ref != "" {
+		if err := ValidateRoleRepoRef(entry.Ref); err != nil {
+			return SkillSpec{}, fmt.Errorf("skill %s: %w", name, err)
+		}
+	}
+	source, err := providers.LockEntrySource(RoleRepoLockEntry{Name: name, Source: entry.Source, SourceType: entry.SourceType})
+	if err != nil {
+		return SkillSpec{}, fmt.Errorf("skill %s: %w", name, err)
+	}
+	return SkillSpec{Spec: entry.Source + "@" + name, Source: source, Name: name, Ref: entry.Ref, Path: entry.SkillPath}, nil
+}
+
+// SkillInstallResult describes an installed skill.
+type SkillInstallResult struct {
+	Name        string
+	Source      string
+	SourceType  string
+	Ref         string
+	Commit      string
+	SkillPath   string // directory of the skill inside the source
+	Dir         string // installed directory
+	ContentHash string
+	Files       int
+	Unchanged   bool
+}
+
+// remoteSkill is a skill package located in a source tree.
+type remoteSkill struct {
+	provider RoleRepoProvider
+	commit   string
+	dir      string
+	files    []RoleRepoTreeEntry // paths relative to dir
+	hash     string
+}
+
+// locateRemoteSkill resolves spec's ref and finds the skill directory in the
+// source tree: a directory named spec.Name containing SKILL.md or, when the
+// name was not given explicitly or the source itself is the skill, the only
+// SKILL.md in the source.
+func locateRemoteSkill(ctx context.Context, providers *RoleRepoProviderSet, spec SkillSpec) (*remoteSkill, error) {
+	provider, err := providers.ForSource(spec.Source)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := provider.ResolveRef(ctx, spec.Source, spec.Ref)
+	if err != nil {
+		return nil, err
+	}
+	treeRef := commit
+	if treeRef == "" {
+		treeRef = spec.Ref
+	}
+	tree, err := provider.FetchTree(ctx, spec.Source, treeRef)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, e := range tree {
+		if e.Type == "blob" && path.Base(e.Path) == "SKILL.md" && !strings.HasPrefix(e.Path, ".git/") {
+			dir := path.Dir(e.Path)
+			if dir == "." {
+				dir = ""
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	dir, found := "", false
+	if spec.Path != "" {
+		for _, d := range dirs {
+			if d == spec.Path {
+				dir, found = d, true
+			}
+		}
+	}
+	if !found {
+		var named []string
+		for _, d := range dirs {
+			base := path.Base(d)
+			if d == "" {
+				base = skillSourceBaseName(spec.Source)
+			}
+			if base == spec.Name {
+				named = append(named, d)
+			}
+		}
+		switch {
+		case len(named) == 1:
+			dir, found = named[0], true
+		case len(named) > 1:
+			return nil, fmt.Errorf("skill %s is ambiguous in %s: %s", spec.Name, spec.Source.FullName(), strings.Join(named, ", "))
+		case len(dirs) == 1 && (!spec.Explicit || dirs[0] == ""):
+			dir, found = dirs[0], true
+		}
+	}
+	if !found {
+		if len(dirs) == 0 {
+			return nil, fmt.Errorf("no SKILL.md found in %s", spec.Source.FullName())
+		}
+		return nil, fmt.Errorf("skill %s not found in %s (available: %s)", spec.Name, spec.Source.FullName(), strings.Join(skillDirNames(dirs), ", "))
+	}
+
+	remote := &remoteSkill{provider: provider, commit: commit, dir: dir}
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+	for _, e := range tree {
+		if e.Type != "blob" || !strings.HasPrefix(e.Path, prefix) || strings.HasPrefix(e.Path, ".git/") {
+			continue
+		}
+		remote.files = append(remote.files, RoleRepoTreeEntry{Path: strings.TrimPrefix(e.Path, prefix), Type: e.Type, SHA: e.SHA})
+	}
+	remote.hash = hashRoleRepoTreeFiles(remote.files)
+	return remote, nil
+}
+
+func skillDirNames(dirs []string) []string {
+	names := make([]string, 0, len(dirs))
+	for _, d := range dirs {
+		if d == "" {
+			d = "."
+		}
+		names = append(names, d)
+	}
+	return names
+}
+
+// validateSkillMD checks that SKILL.md has frontmatter with a name and a
+// description, which every provider needs to load the skill.
+func validateSkillMD(data []byte) error {
+	frontmatter, ok := splitSkillFrontmatter(string(data))
+	if !ok {
+		return fmt.Errorf("SKILL.md has no YAML frontmatter")
+	}
+	var fm struct {
+		Name        string `yaml:"name"`
+		Description string `yaml:"description"`
+	}
+	if err := yaml.Unmarshal([]byte(frontmatter), &fm); err != nil {
+		return fmt.Errorf("SKILL.md frontmatter: %w", err)
+	}
+	if strings.TrimSpace(fm.Name) == "" {
+		return fmt.Errorf("SKILL.md frontmatter name is required")
+	}
+	if strings.TrimSpace(fm.Description) == "" {
+		return fmt.Errorf("SKILL.md frontmatter description is required")
+	}
+	return nil
+}
+
+// InstallSkill fetches the skill package spec names into the project skill
+// cache (.agent-team/.cache/skills/<name>), validates its SKILL.md and
+// records its provenance in skills-lock.json. The cache copy is replaced
+// atomically, so a failed fetch leaves the previous install in place.
+func InstallSkill(ctx context.Context, root string, providers *RoleRepoProviderSet, spec SkillSpec, nowFn func() time.Time) (*SkillInstallResult, error) {
+	if err := validateSkillName(spec.Name); err != nil {
+		return nil, err
+	}
+	remote, err := locateRemoteSkill(ctx, providers, spec)
+	if err != nil {
+		return nil, err
+	}
+	dest := projectSkillPath(root, "", spec.Name)
+	result := &SkillInstallResult{
+		Name:        spec.Name,
+		Source:      providers.LockSource(spec.Source),
+		SourceType:  remote.provider.SourceType(),
+		Ref:         spec.Ref,
+		Commit:      remote.commit,
+		SkillPath:   remote.dir,
+		Dir:         dest,
+		ContentHash: remote.hash,
+		Files:       len(remote.files),
+	}
+
+	if current, err := HashSkillDir(dest); err == nil && current == remote.hash {
+		result.Unchanged = true
+	} else {
+		files := make(map[string][]byte, len(remote.files))
+		for _, f := range remote.files {
+			data, err := remote.provider.FetchBlob(ctx, spec.Source, f.SHA)
+			if err != nil {
+				return nil, fmt.Errorf("fetch %s: %w", f.Path, err)
+			}
+			files[f.Path] = data
+		}
+		skillMD, ok := files["SKILL.md"]
+		if !ok {
+			return nil, fmt.Errorf("skill %s has no SKILL.md", spec.Name)
+		}
+		if err := validateSkillMD(skillMD); err != nil {
+			return nil, fmt.Errorf("skill %s: %w", spec.Name, err)
+		}
+		if err := replaceSkillDir(dest, files); err != nil {
+			return nil, err
+		}
+	}
+
+	lock, err := ReadSkillsLock(root)
+	if err != nil {
+		return nil, err
+	}
+	now := nowFn().UTC()
+	entry := lock.Skills[spec.Name]
+	if !result.Unchanged || entry.ContentHash != result.ContentHash {
+		// The npx hash no longer describes the installed copy.
+		entry.ComputedHash = ""
+	}
+	entry.Source = result.Source
+	entry.SourceType = result.SourceType
+	entry.Ref = result.Ref
+	entry.Commit = result.Commit
+	entry.SkillPath = result.SkillPath
+	entry.Path = skillsLockPath(root, dest)
+	entry.Layer = "project-cache"
+	entry.ContentHash = result.ContentHash
+	if entry.InstalledAt.IsZero() {
+		entry.InstalledAt = now
+	}
+	entry.UpdatedAt = now
+	lock.Skills[spec.Name] = entry
+	if err := WriteSkillsLock(root, lock); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// replaceSkillDir writes files to a sibling temporary directory and swaps
+// it in for dest. File paths come from the remote tree and must stay inside
+// that directory.
+func replaceSkillDir(dest string, files map[string][]byte) error {
+	parent := filepath.Dir(dest)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(parent, "."+filepath.Base(dest)+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	for rel, data := range files {
+		target := filepath.Join(tmp, filepath.FromSlash(rel))
+		if r, err := filepath.Rel(tmp, target); err != nil || r == "." || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid skill file path: %s", rel)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(dest); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+// Skill check statuses.
+const (
+	SkillCheckUpToDate        = "up-to-date"
+	SkillCheckUpdateAvailable = "update-available"
+	SkillCheckLocalChanges    = "local-changes"
+	SkillCheckNotInstalled    = "not-installed"
+	SkillCheckNoSource        = "no-source"
+	SkillCheckError           = "error"
+)
+
+// SkillCheckResult compares an installed skill with its source.
+type SkillCheckResult struct {
+	Name         string `json:"name"`
+	Source       string `json:"source,omitempty"`
+	Status       string `json:"status"`
+	Commit       string `json:"commit,omitempty"`
+	RemoteCommit string `json:"remoteCommit,omitempty"`
+	LocalHash    string `json:"localHash,omitempty"`
+	RemoteHash   string `json:"remoteHash,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// skillsLockAbsPath expands a skills-lock.json path to an absolute path.
+func skillsLockAbsPath(root, p string) string {
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, filepath.FromSlash(rest))
+		}
+	}
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(root, filepath.FromSlash(p))
+}
+
+// installedSkillDir is where a locked skill lives: the recorded path, or
+// the project cache for entries written by `npx skills`.
+func installedSkillDir(root, name string, entry SkillsLockEntry) string {
+	if entry.Path != "" {
+		return skillsLockAbsPath(root, entry.Path)
+	}
+	return projectSkillPath(root, "", name)
+}
+
+// CheckSkill compares one locked skill with the latest version of its
+// source at the locked ref, without downloading file contents.
+func CheckSkill(ctx context.Context, root string, providers *RoleRepoProviderSet, name string, entry SkillsLockEntry) SkillCheckResult {
+	result := SkillCheckResult{Name: name, Source: entry.Source, Commit: entry.Commit}
+	if entry.Source == "" {
+		result.Status = SkillCheckNoSource
+		return result
+	}
+	spec, err := SkillSpecFromLock(providers, name, entry)
+	if err != nil {
+		result.Status, result.Error = SkillCheckError, err.Error()
+		return result
+	}
+	remote, err := locateRemoteSkill(ctx, providers, spec)
+	if err != nil {
+		result.Status, result.Error = SkillCheckError, err.Error()
+		return result
+	}
+	result.RemoteCommit, result.RemoteHash = remote.commit, remote.hash
+
+	local, err := HashSkillDir(installedSkillDir(root, name, entry))
+	if err != nil {
+		result.Status = SkillCheckNotInstalled
+		return result
+	}
+	result.LocalHash = local
+	switch {
+	case entry.ContentHash != "" && local != entry.ContentHash:
+		result.Status = SkillCheckLocalChanges
+	case local != remote.hash:
+		result.Status = SkillCheckUpdateAvailable
+	default:
+		result.Status = SkillCheckUpToDate
+	}
+	return result
+}
+
+// CheckSkills checks the named skills, or every skill in skills-lock.json
+// when names is empty, sorted by name.
+func CheckSkills(ctx context.Context, root string, providers *RoleRepoProviderSet, lock SkillsLockFile, names []string) []SkillCheckResult {
+	names = skillsLockNames(lock, names)
+	results := make([]SkillCheckResult, 0, len(names))
+	for _, name := range names {
+		entry, ok := lock.Skills[name]
+		if !ok {
+			results = append(results, SkillCheckResult{Name: name, Status: SkillCheckError, Error: "not in " + SkillsLockFileName})
+			continue
+		}
+		results = append(results, CheckSkill(ctx, root, providers, name, entry))
+	}
+	return results
+}
+
+func skillsLockNames(lock SkillsLockFile, names []string) []string {
+	if len(names) > 0 {
+		out := make([]string, 0, len(names))
+		for _, n := range names {
+			out = append(out, parseSkillName(n))
+		}
+		return DedupeKeepOrder(out)
+	}
+	out := make([]string, 0, len(lock.Skills))
+	for name := range lock.Skills {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// SkillUpdateResult reports what UpdateSkills did for one skill.
+type SkillUpdateResult struct {
+	Name    string
+	Status  string // updated, up-to-date, skipped or failed
+	Reason  string
+	Install *SkillInstallResult
+}
+
+// UpdateSkills reinstalls the named skills (or every skill with a source)
+// from the ref recorded in skills-lock.json. Skills edited since install are
+// skipped unless force is set.
+func UpdateSkills(ctx context.Context, root string, providers *RoleRepoProviderSet, names []string, force bool, nowFn func() time.Time) ([]SkillUpdateResult, error) {
+	lock, err := ReadSkillsLock(root)
+	if err != nil {
+		return nil, err
+	}
+	explicit := len(names) > 0
+	var results []SkillUpdateResult
+	for _, name := range skillsLockNames(lock, names) {
+		entry, ok := lock.Skills[name]
+		switch {
+		case !ok:
+			results = append(results, SkillUpdateResult{Name: name, Status: "failed", Reason: "not in " + SkillsLockFileName})
+			continue
+		case entry.Source == "":
+			if explicit {
+				results = append(results, SkillUpdateResult{Name: name, Status: "skipped", Reason: "no source recorded"})
+			}
+			continue
+		}
+		if !force && entry.ContentHash != "" {
+			if local, err := HashSkillDir(installedSkillDir(root, name, entry)); err == nil && local != entry.ContentHash {
+				results = append(results, SkillUpdateResult{Name: name, Status: "skipped", Reason: "local changes (use --force to overwrite)"})
+				continue
+			}
+		}
+		spec, err := SkillSpecFromLock(providers, name, entry)
+		if err != nil {
+			results = append(results, SkillUpdateResult{Name: name, Status: "failed", Reason: err.Error()})
+			continue
+		}
+		installed, err := InstallSkill(ctx, root, providers, spec, nowFn)
+		if err != nil {
+			results = append(results, SkillUpdateResult{Name: name, Status: "failed", Reason: err.Error()})
+			continue
+		}
+		status := "updated"
+		if installed.Unchanged {
+			status = "up-to-date"
+		}
+		results = append(results, SkillUpdateResult{Name: name, Status: status, Install: installed})
+	}
+	return results, nil
+}
+
+// SkillsNpxFallbackEnabled reports whether `npx skills` may be used when the
+// native installer fails (AGENT_TEAM_SKILLS_NPX=1 and npx on PATH).
+func SkillsNpxFallbackEnabled() bool {
+	if os.Getenv("AGENT_TEAM_SKILLS_NPX") != "1" {
+		return false
+	}
+	_, err := exec.LookPath("npx")
+	return err == nil
+}
@@ -0,0 +1,217 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSkillSpec(t *testing.T) {
+	local := t.TempDir()
+	tests := []struct {
+		spec, ref             string
+		wantType, wantName    string
+		wantRef, wantFullName string
+	}{
+		{"antfu/skills@vite", "", RoleRepoSourceGitHub, "vite", "", "antfu/skills"},
+		{"better-auth/better-icons", "", RoleRepoSourceGitHub, "better-icons", "", "better-auth/better-icons"},
+		{"antfu/skills@v1.2@vite", "", RoleRepoSourceGitHub, "vite", "v1.2", "antfu/skills"},
+		{"antfu/skills@vite", "main", RoleRepoSourceGitHub, "vite", "main", "antfu/skills"},
+		{"git@gitlab.example.com:team/skills.git@lint", "", RoleRepoSourceGit, "lint", "", "git@gitlab.example.com:team/skills.git"},
+		{"https://git.example.com/team/review-skill.git", "", RoleRepoSourceGit, "review-skill", "", "https://git.example.com/team/review-skill.git"},
+		{local, "", RoleRepoSourceLocal, filepath.Base(local), "", local},
+	}
+	for _, tt := range tests {
+		spec, err := ParseSkillSpec("", tt.spec, tt.ref)
+		if err != nil {
+			t.Fatalf("ParseSkillSpec(%q): %v", tt.spec, err)
+		}
+		if spec.Source.Type != tt.wantType || spec.Name != tt.wantName || spec.Ref != tt.wantRef || spec.Source.FullName() != tt.wantFullName {
+			t.Errorf("ParseSkillSpec(%q) = %s %s name=%s ref=%s", tt.spec, spec.Source.Type, spec.Source.FullName(), spec.Name, spec.Ref)
+		}
+	}
+	if _, err := ParseSkillSpec("", local, "v1"); err == nil {
+		t.Error("expected refs to be rejected for local sources")
+	}
+}
+
+func writeSkillSource(t *testing.T, dir, name, description, body string) {
+	t.Helper()
+	writeSkillFixture(t, dir, "---\nname: "+name+"\ndescription: "+description+"\n---\n\n"+body)
+}
+
+func TestInstallSkillFromLocalSourceCheckAndUpdate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	source := t.TempDir()
+	writeSkillSource(t, filepath.Join(source, "skills", "vite"), "vite", "Vite support", "v1\n")
+	writeSkillSource(t, filepath.Join(source, "skills", "vitest"), "vitest", "Vitest support", "v1\n")
+	writeSkillFixture(t, filepath.Join(source, "skills", "broken"), "no frontmatter\n")
+
+	ctx := context.Background()
+	providers := NewRoleRepoProviderSet(root, nil)
+	now := func() time.Time { return time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC) }
+	spec, err := ParseSkillSpec(root, source+"@vite", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := InstallSkill(ctx, root, providers, spec, now)
+	if err != nil {
+		t.Fatalf("InstallSkill: %v", err)
+	}
+	cacheDir := filepath.Join(root, ".agent-team", ".cache", "skills", "vite")
+	if result.Dir != cacheDir || result.SkillPath != "skills/vite" || result.Unchanged {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if data, _ := os.ReadFile(filepath.Join(cacheDir, "SKILL.md")); !strings.Contains(string(data), "v1") {
+		t.Fatalf("skill not installed:\n%s", data)
+	}
+	lock, err := ReadSkillsLock(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := lock.Skills["vite"]
+	if entry.Source != source || entry.SourceType != RoleRepoSourceLocal || entry.SkillPath != "skills/vite" ||
+		entry.Path != ".agent-team/.cache/skills/vite" || entry.Layer != "project-cache" || entry.ContentHash != result.ContentHash || !entry.InstalledAt.Equal(now()) {
+		t.Fatalf("unexpected lock entry: %+v", entry)
+	}
+	if hash, _ := HashSkillDir(cacheDir); hash != entry.ContentHash {
+		t.Fatalf("content hash %s does not match installed files %s", entry.ContentHash, hash)
+	}
+	if got := VerifySkill(root, "vite", entry); got.Status != SkillVerifyOK {
+		t.Fatalf("installed skill should verify: %+v", got)
+	}
+
+	spec, _ = ParseSkillSpec(root, source+"@broken", "")
+	if _, err := InstallSkill(ctx, root, providers, spec, now); err == nil || !strings.Contains(err.Error(), "frontmatter") {
+		t.Fatalf("expected SKILL.md validation error, got %v", err)
+	}
+	spec, _ = ParseSkillSpec(root, source+"@missing", "")
+	if _, err := InstallSkill(ctx, root, providers, spec, now); err == nil || !strings.Contains(err.Error(), "available: skills/broken, skills/vite, skills/vitest") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+
+	if got := CheckSkills(ctx, root, providers, lock, nil); len(got) != 1 || got[0].Status != SkillCheckUpToDate {
+		t.Fatalf("expected up to date, got %+v", got)
+	}
+	writeSkillSource(t, filepath.Join(source, "skills", "vite"), "vite", "Vite support", "v2\n")
+	if got := CheckSkills(ctx, root, providers, lock, []string{"vite"}); got[0].Status != SkillCheckUpdateAvailable {
+		t.Fatalf("expected update available, got %+v", got)
+	}
+
+	// Local edits block updates unless forced.
+	if err := os.WriteFile(filepath.Join(cacheDir, "NOTES.md"), []byte("mine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := CheckSkill(ctx, root, providers, "vite", entry); got.Status != SkillCheckLocalChanges {
+		t.Fatalf("expected local changes, got %+v", got)
+	}
+	updates, err := UpdateSkills(ctx, root, providers, nil, false, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 || updates[0].Status != "skipped" {
+		t.Fatalf("expected skipped update, got %+v", updates)
+	}
+	updates, err = UpdateSkills(ctx, root, providers, []string{"vite"}, true, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updates[0].Status != "updated" || fileExists(filepath.Join(cacheDir, "NOTES.md")) {
+		t.Fatalf("forced update should replace the skill: %+v", updates)
+	}
+	if data, _ := os.ReadFile(filepath.Join(cacheDir, "SKILL.md")); !strings.Contains(string(data), "v2") {
+		t.Fatalf("skill not updated:\n%s", data)
+	}
+	updates, _ = UpdateSkills(ctx, root, providers, nil, false, now)
+	if updates[0].Status != "up-to-date" {
+		t.Fatalf("expected up to date, got %+v", updates)
+	}
+}
+
+func TestInstallSkillFromGitRecordsCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	repo := t.TempDir()
+	writeSkillSource(t, repo, "review", "Code review", "v1\n")
+	git := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "--quiet", "-b", "main")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "v1")
+	git("tag", "v1")
+	commit := git("rev-parse", "HEAD")
+
+	root := t.TempDir()
+	providers := NewRoleRepoProviderSet(root, nil)
+	providers.CacheDir = t.TempDir()
+	spec, err := ParseSkillSpec(root, "git+file://"+filepath.ToSlash(repo)+"@review", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := InstallSkill(context.Background(), root, providers, spec, time.Now)
+	if err != nil {
+		t.Fatalf("InstallSkill: %v", err)
+	}
+	if result.Commit != commit || result.SkillPath != "" || result.Ref != "v1" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	lock, _ := ReadSkillsLock(root)
+	if entry := lock.Skills["review"]; entry.Commit != commit || entry.Ref != "v1" || entry.SourceType != RoleRepoSourceGit {
+		t.Fatalf("unexpected lock entry: %+v", entry)
+	}
+}
+
+func TestUpdateSkillsRejectsUnsafeLockNames(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	source := t.TempDir()
+	writeSkillSource(t, source, "solo", "Single skill", "v1\n")
+	sentinel := filepath.Join(root, "keep.txt")
+	if err := os.WriteFile(sentinel, []byte("keep\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lock := SkillsLockFile{Skills: map[string]SkillsLockEntry{
+		"../../..": {Source: source, SourceType: RoleRepoSourceLocal},
+	}}
+	if err := WriteSkillsLock(root, lock); err != nil {
+		t.Fatal(err)
+	}
+
+	providers := NewRoleRepoProviderSet(root, nil)
+	now := func() time.Time { return time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC) }
+	updates, _ := UpdateSkills(context.Background(), root, providers, nil, true, now)
+	if len(updates) != 1 || updates[0].Status == "updated" {
+		t.Fatalf("unsafe lock name should not update: %+v", updates)
+	}
+	if !fileExists(sentinel) {
+		t.Fatal("update removed files outside the skill cache")
+	}
+	if _, err := ParseSkillSpec(root, source+"@..", ""); err == nil {
+		t.Fatal("expected invalid skill name error")
+	}
+}
+
+func TestReplaceSkillDirRejectsEscapingPaths(t *testing.T) {
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "cache", "vite")
+	err := replaceSkillDir(dest, map[string][]byte{"SKILL.md": []byte("x"), "../../escape.txt": []byte("x")})
+	if err == nil || !strings.Contains(err.Error(), "invalid skill file path") {
+		t.Fatalf("expected invalid path error, got %v", err)
+	}
+	if fileExists(filepath.Join(parent, "escape.txt")) || fileExists(dest) {
+		t.Fatal("escaping file was written")
+	}
+}
@@ -0,0 +1,215 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Resolution statuses of a skill declared by a role.
+const (
+	SkillReportLocal   = "local"   // resolved from a plugin, project or user skill directory
+	SkillReportCached  = "cached"  // resolved from the project skill cache
+	SkillReportMissing = "missing" // not available locally
+)
+
+// SkillReportDeclared is one skill declared in a role's references/role.yaml.
+type SkillReportDeclared struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Layer  string `json:"layer,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// SkillReportRole lists the skills a role declares after inheritance.
+type SkillReportRole struct {
+	Role   string                `json:"role"`
+	Scope  string                `json:"scope"`
+	Skills []SkillReportDeclared `json:"skills"`
+	Error  string                `json:"error,omitempty"`
+}
+
+// SkillReportLink is one entry in a worktree's provider skill directory.
+type SkillReportLink struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Kind     string `json:"kind"` // symlink or copy
+	Target   string `json:"target,omitempty"`
+	Broken   bool   `json:"broken,omitempty"`
+}
+
+// SkillReportWorker lists the skills linked into a worker's worktree.
+type SkillReportWorker struct {
+	Worker string            `json:"worker"`
+	Role   string            `json:"role"`
+	Skills []SkillReportLink `json:"skills"`
+}
+
+// SkillReportMissingSkill is a declared skill that does not resolve locally.
+type SkillReportMissingSkill struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+// SkillReportDuplicate is a skill present in more than one search location.
+// The first path is the one workers get.
+type SkillReportDuplicate struct {
+	Name      string           `json:"name"`
+	Locations []SkillCandidate `json:"locations"`
+}
+
+// SkillReportBrokenLink is a worktree skill symlink whose target is gone.
+type SkillReportBrokenLink struct {
+	Worker string `json:"worker"`
+	Name   string `json:"name"`
+	Link   string `json:"link"`
+	Target string `json:"target"`
+}
+
+// SkillReport relates roles, workers and the skills available on disk.
+type SkillReport struct {
+	Roles       []SkillReportRole         `json:"roles"`
+	Workers     []SkillReportWorker       `json:"workers"`
+	Missing     []SkillReportMissingSkill `json:"missing"`
+	Unused      []string                  `json:"unused"`
+	Duplicates  []SkillReportDuplicate    `json:"duplicates"`
+	BrokenLinks []SkillReportBrokenLink   `json:"brokenLinks"`
+}
+
+// BuildSkillReport reports, for every project role (and every global role a
+// worker uses), the declared skills and where they resolve; for every
+// worker, the skills linked into its worktree, flagging broken symlinks;
+// cached skills nobody declares or links; and skills found in more than one
+// search location.
+func BuildSkillReport(root, wtBase string) SkillReport {
+	report := SkillReport{
+		Roles:       []SkillReportRole{},
+		Workers:     []SkillReportWorker{},
+		Missing:     []SkillReportMissingSkill{},
+		Unused:      []string{},
+		Duplicates:  []SkillReportDuplicate{},
+		BrokenLinks: []SkillReportBrokenLink{},
+	}
+
+	type roleRef struct{ name, scope, path string }
+	var roles []roleRef
+	seenRoles := map[string]bool{}
+	for _, name := range ListAvailableRoles(root) {
+		roles = append(roles, roleRef{name, "project", RoleDir(root, name)})
+		seenRoles[name] = true
+	}
+	workers := ListWorkers(root, wtBase)
+	for _, w := range workers {
+		if seenRoles[w.Role] {
+			continue
+		}
+		if match, err := ResolveRole(root, w.Role); err == nil {
+			roles = append(roles, roleRef{match.RoleName, match.Scope, match.Path})
+			seenRoles[w.Role] = true
+		}
+	}
+
+	declared := map[string]bool{} // short names
+	missing := map[string][]string{}
+	resolutions := map[string]SkillResolution{}
+	for _, r := range roles {
+		entry := SkillReportRole{Role: r.name, Scope: r.scope, Skills: []SkillReportDeclared{}}
+		skills, err := ReadRoleSkillsFromPath(r.path)
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		for _, name := range skills {
+			res, ok := resolutions[name]
+			if !ok {
+				res = ResolveSkill(root, name)
+				resolutions[name] = res
+			}
+			declared[res.ShortName] = true
+			item := SkillReportDeclared{Name: name, Status: SkillReportMissing}
+			switch {
+			case res.Path == "":
+				missing[name] = append(missing[name], r.name)
+			case res.Layer == "project-cache":
+				item.Status, item.Layer, item.Path = SkillReportCached, res.Layer, skillsLockPath(root, res.Path)
+			default:
+				item.Status, item.Layer, item.Path = SkillReportLocal, res.Layer, skillsLockPath(root, res.Path)
+			}
+			entry.Skills = append(entry.Skills, item)
+		}
+		report.Roles = append(report.Roles, entry)
+	}
+	for name, roleNames := range missing {
+		report.Missing = append(report.Missing, SkillReportMissingSkill{Name: name, Roles: roleNames})
+	}
+	sort.Slice(report.Missing, func(i, j int) bool { return report.Missing[i].Name < report.Missing[j].Name })
+
+	linked := map[string]bool{}
+	for _, w := range workers {
+		entry := SkillReportWorker{Worker: w.WorkerID, Role: w.Role, Skills: []SkillReportLink{}}
+		wtPath := WtPath(root, wtBase, w.WorkerID)
+		for _, provider := range []string{"claude", "codex", "opencode", "gemini"} {
+			dir := skillTargetDir(wtPath, provider)
+			items, err := os.ReadDir(dir)
+			if err != nil {
+				continue
+			}
+			for _, item := range items {
+				link := SkillReportLink{Name: item.Name(), Provider: provider, Kind: "copy"}
+				linkPath := filepath.Join(dir, item.Name())
+				if isSymlink(linkPath) {
+					link.Kind = "symlink"
+					link.Target, _ = os.Readlink(linkPath)
+					if _, err := os.Stat(linkPath); err != nil {
+						link.Broken = true
+						report.BrokenLinks = append(report.BrokenLinks, SkillReportBrokenLink{Worker: w.WorkerID, Name: item.Name(), Link: linkPath, Target: link.Target})
+					}
+				}
+				linked[item.Name()] = true
+				entry.Skills = append(entry.Skills, link)
+			}
+		}
+		report.Workers = append(report.Workers, entry)
+	}
+
+	if entries, err := os.ReadDir(filepath.Join(ResolveAgentsDir(root), ".cache", "skills")); err == nil {
+		for _, e := range entries {
+			name := e.Name()
+			if name[0] == '.' {
+				continue
+			}
+			if !declared[name] && !linked[name] {
+				report.Unused = append(report.Unused, name)
+			}
+			if _, ok := resolutions[name]; !ok && !declared[name] {
+				resolutions[name] = ResolveSkill(root, name)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(resolutions))
+	for name := range resolutions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seenDup := map[string]bool{}
+	for _, name := range names {
+		res := resolutions[name]
+		if seenDup[res.ShortName] {
+			continue
+		}
+		var locations []SkillCandidate
+		seenPaths := map[string]bool{}
+		for _, c := range res.Candidates {
+			if !c.Exists || seenPaths[c.Path] {
+				continue
+			}
+			seenPaths[c.Path] = true
+			locations = append(locations, SkillCandidate{Layer: c.Layer, Path: skillsLockPath(root, c.Path), Exists: true})
+		}
+		if len(locations) > 1 {
+			seenDup[res.ShortName] = true
+			report.Duplicates = append(report.Duplicates, SkillReportDuplicate{Name: res.ShortName, Locations: locations})
+		}
+	}
+	return report
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuildSkillReport(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CLAUDE_PLUGIN_ROOT", "")
+	root := t.TempDir()
+	teamsDir := filepath.Join(root, ".agent-team", "teams")
+	cacheDir := filepath.Join(root, ".agent-team", ".cache", "skills")
+
+	writeRoleFiles(t, teamsDir, "dev", "name: dev\nskills:\n  - name: \"tdd\"\n    description: \"TDD\"\n  - name: \"antfu/skills@vite\"\n    description: \"Vite\"\n  - name: \"ghost\"\n    description: \"Not installed\"\n", "")
+	writeSkillFixture(t, filepath.Join(root, "skills", "tdd"), "project copy\n")
+	writeSkillFixture(t, filepath.Join(cacheDir, "tdd"), "cached copy\n")
+	writeSkillFixture(t, filepath.Join(cacheDir, "vite"), "vite\n")
+	writeSkillFixture(t, filepath.Join(cacheDir, "stale"), "stale\n")
+
+	wtPath := WtPath(root, ".worktrees", "dev-001")
+	cfg := &WorkerConfig{WorkerID: "dev-001", Role: "dev"}
+	if err := cfg.Save(WorkerYAMLPath(wtPath)); err != nil {
+		t.Fatal(err)
+	}
+	linkDir := skillTargetDir(wtPath, "claude")
+	if err := os.MkdirAll(linkDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(cacheDir, "vite"), filepath.Join(linkDir, "vite")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(cacheDir, "gone"), filepath.Join(linkDir, "gone")); err != nil {
+		t.Fatal(err)
+	}
+
+	report := BuildSkillReport(root, ".worktrees")
+
+	if len(report.Roles) != 1 || report.Roles[0].Role != "dev" || report.Roles[0].Scope != "project" || report.Roles[0].Error != "" {
+		t.Fatalf("unexpected roles: %+v", report.Roles)
+	}
+	var statuses []string
+	for _, s := range report.Roles[0].Skills {
+		statuses = append(statuses, s.Name+"="+s.Status)
+	}
+	// The project cache wins over skills/, as it does for worker open.
+	want := []string{"tdd=cached", "antfu/skills@vite=cached", "ghost=missing"}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Fatalf("statuses = %v, want %v", statuses, want)
+	}
+	if len(report.Missing) != 1 || report.Missing[0].Name != "ghost" || !reflect.DeepEqual(report.Missing[0].Roles, []string{"dev"}) {
+		t.Fatalf("unexpected missing: %+v", report.Missing)
+	}
+
+	if len(report.Workers) != 1 || report.Workers[0].Worker != "dev-001" || len(report.Workers[0].Skills) != 2 {
+		t.Fatalf("unexpected workers: %+v", report.Workers)
+	}
+	if len(report.BrokenLinks) != 1 || report.BrokenLinks[0].Name != "gone" || report.BrokenLinks[0].Worker != "dev-001" {
+		t.Fatalf("unexpected broken links: %+v", report.BrokenLinks)
+	}
+
+	if !reflect.DeepEqual(report.Unused, []string{"stale"}) {
+		t.Fatalf("unused = %v", report.Unused)
+	}
+	if len(report.Duplicates) != 1 || report.Duplicates[0].Name != "tdd" || len(report.Duplicates[0].Locations) != 2 {
+		t.Fatalf("unexpected duplicates: %+v", report.Duplicates)
+	}
+	if got := report.Duplicates[0].Locations[0].Path; got != ".agent-team/.cache/skills/tdd" {
+		t.Fatalf("first duplicate location = %s", got)
+	}
+}
+
+func TestBuildSkillReportEmptyProject(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CLAUDE_PLUGIN_ROOT", "")
+	report := BuildSkillReport(t.TempDir(), ".worktrees")
+	if report.Roles == nil || report.Workers == nil || report.Missing == nil || report.Unused == nil || report.Duplicates == nil || report.BrokenLinks == nil {
+		t.Fatalf("report lists should be empty, not nil: %+v", report)
+	}
+}
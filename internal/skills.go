@@ -89,21 +89,17 @@ func roleSkillNames(skills []RoleSkillSpec) []string {
 	return names
 }
 
-// ReadRoleSkillsFromPath reads the skill names from a role's references/role.yaml at the given path.
+// ReadRoleSkillsFromPath reads the skill names from a role's references/role.yaml at the given path,
+// including skills inherited through extends and mixins.
 func ReadRoleSkillsFromPath(rolePath string) ([]string, error) {
-	yamlPath := filepath.Join(rolePath, "references", "role.yaml")
-	data, err := os.ReadFile(yamlPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("read role.yaml: %w", err)
+	if _, err := os.Stat(filepath.Join(rolePath, "references", "role.yaml")); os.IsNotExist(err) {
+		return nil, nil
 	}
-	skills, err := readRoleSkillSpecs(data)
+	role, err := ResolveRoleFromPath(rolePath)
 	if err != nil {
-		return nil, fmt.Errorf("parse role.yaml: %w", err)
+		return nil, err
 	}
-	return roleSkillNames(skills), nil
+	return role.SkillNames(), nil
 }
 
 // ReadRoleSkills reads the skills list from a role's references/role.yaml.
@@ -185,6 +181,24 @@ func parseSkillName(skillName string) string {
 // findSkillPath searches for a skill in known locations.
 // Supports both plain names ("vite") and scoped names ("antfu/skills@vite").
 func findSkillPath(root, skillName string) string {
+	if p := findLocalSkillPath(root, skillName); p != "" {
+		return p
+	}
+
+	// 远程下载回退（仅 scoped 格式）
+	shortName := parseSkillName(skillName)
+	if shortName != skillName {
+		targetDir := filepath.Join(ResolveAgentsDir(root), ".cache", "skills")
+		if downloaded := tryRemoteDownload(root, skillName, targetDir, shortName); downloaded != "" {
+			return downloaded
+		}
+	}
+
+	return ""
+}
+
+// findLocalSkillPath searches the local skill locations only, without the remote download fallback.
+func findLocalSkillPath(root, skillName string) string {
 	// Build candidate names: full name first, then short name (after @) if different
 	candidates := []string{skillName}
 	shortName := parseSkillName(skillName)
@@ -202,15 +216,6 @@ func findSkillPath(root, skillName string) string {
 			}
 		}
 	}
-
-	// 远程下载回退（仅 scoped 格式）
-	if shortName != skillName {
-		targetDir := filepath.Join(ResolveAgentsDir(root), ".cache", "skills")
-		if downloaded := tryRemoteDownload(skillName, targetDir, shortName); downloaded != "" {
-			return downloaded
-		}
-	}
-
 	return ""
 }
 
@@ -222,28 +227,56 @@ func pluginSkillsDir() string {
 	return ""
 }
 
-// buildSearchDirs 构建 5 层技能搜索目录
-func buildSearchDirs(root string) []string {
-	var dirs []string
+// SkillSearchLayer is one directory in the skill search order.
+type SkillSearchLayer struct {
+	Name string
+	Dir  string
+}
+
+// skillSearchLayers 构建 5 层技能搜索目录（带层名，供 skill why / skills-lock 使用）
+func skillSearchLayers(root string) []SkillSearchLayer {
+	var layers []SkillSearchLayer
 	if d := pluginSkillsDir(); d != "" {
-		dirs = append(dirs, d) // 层 1: Plugin 内置
+		layers = append(layers, SkillSearchLayer{"plugin", d}) // 层 1: Plugin 内置
 	}
-	dirs = append(dirs, filepath.Join(ResolveAgentsDir(root), "teams")) // 层 2: .agent-team/teams
-	dirs = append(dirs, filepath.Join(root, "skills"))                  // 层 3: project/skills
-	dirs = append(dirs, filepath.Join(ResolveAgentsDir(root), ".cache", "skills")) // 层 4: project cache
+	layers = append(layers, SkillSearchLayer{"project-roles", filepath.Join(ResolveAgentsDir(root), "teams")})            // 层 2: .agent-team/teams
+	layers = append(layers, SkillSearchLayer{"project-skills", filepath.Join(root, "skills")})                            // 层 3: project/skills
+	layers = append(layers, SkillSearchLayer{"project-cache", filepath.Join(ResolveAgentsDir(root), ".cache", "skills")}) // 层 4: project cache
 	if home, err := os.UserHomeDir(); err == nil {
-		dirs = append(dirs, filepath.Join(home, ".claude", "skills")) // 层 5: ~/.claude/skills
+		layers = append(layers, SkillSearchLayer{"user", filepath.Join(home, ".claude", "skills")}) // 层 5: ~/.claude/skills
+	}
+	return layers
+}
+
+// buildSearchDirs 构建 5 层技能搜索目录
+func buildSearchDirs(root string) []string {
+	var dirs []string
+	for _, layer := range skillSearchLayers(root) {
+		dirs = append(dirs, layer.Dir)
 	}
 	return dirs
 }
 
-// tryRemoteDownload 尝试通过 npx skills install 下载远程技能（非阻塞，失败只 warn）
-func tryRemoteDownload(skillName, targetDir, shortName string) string {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// tryRemoteDownload 通过原生安装器下载远程技能到项目缓存（失败只 warn）；
+// 设置 AGENT_TEAM_SKILLS_NPX=1 时回退到 npx skills install
+func tryRemoteDownload(root, skillName, targetDir, shortName string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
+	spec, err := ParseSkillSpec(root, skillName, "")
+	if err == nil {
+		spec.Name = shortName
+		var result *SkillInstallResult
+		if result, err = InstallSkill(ctx, root, NewRoleRepoProviderSet(root, nil), spec, time.Now); err == nil {
+			return result.Dir
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Warning: remote skill download failed '%s': %v\n", skillName, err)
+	if !SkillsNpxFallbackEnabled() {
+		return ""
+	}
 	cmd := exec.CommandContext(ctx, "npx", "skills", "install", skillName, "--target", targetDir)
 	if err := cmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: remote skill download failed '%s': %v\n", skillName, err)
+		fmt.Fprintf(os.Stderr, "Warning: npx skills install '%s' failed: %v\n", skillName, err)
 		return ""
 	}
 	downloaded := filepath.Join(targetDir, shortName)
@@ -289,28 +322,58 @@ func runNpxSkillsAdd(cwd, skillName, provider string) error {
 // backgroundCheckOnce ensures only one background skill check runs per process lifetime.
 var backgroundCheckOnce sync.Once
 
-// backgroundSkillCheck silently runs "npx skills check" in the project root.
-// Outputs update hints to stderr without blocking the caller.
+// backgroundSkillCheck checks the given cached skills against the sources
+// recorded in skills-lock.json and prints update hints and check failures to
+// stderr without blocking the caller. With AGENT_TEAM_SKILLS_NPX=1 it runs
+// "npx skills check" instead.
 // Uses sync.Once to avoid duplicate checks from concurrent worker creation.
-func backgroundSkillCheck(root, provider string) {
+func backgroundSkillCheck(root, provider string, skills []string) {
 	backgroundCheckOnce.Do(func() {
-		agent, ok := providerToAgent[provider]
-		if !ok {
-			agent = "claude-code"
-		}
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
-		cmd := exec.CommandContext(ctx, "npx", "skills", "check", "-a", agent)
-		cmd.Dir = root
+		if SkillsNpxFallbackEnabled() {
+			agent, ok := providerToAgent[provider]
+			if !ok {
+				agent = "claude-code"
+			}
+			cmd := exec.CommandContext(ctx, "npx", "skills", "check", "-a", agent)
+			cmd.Dir = root
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: npx skills check failed: %v\n", err)
+				return
+			}
+			if output := strings.TrimSpace(string(out)); output != "" {
+				fmt.Fprintf(os.Stderr, "\n[skill update check]\n%s\n", output)
+			}
+			return
+		}
 
-		out, err := cmd.CombinedOutput()
+		lock, err := ReadSkillsLock(root)
 		if err != nil {
-			// Silently ignore errors — this is a best-effort check
+			fmt.Fprintf(os.Stderr, "Warning: skill update check: %v\n", err)
+			return
+		}
+		var names []string
+		for _, name := range skills {
+			if entry, ok := lock.Skills[parseSkillName(name)]; ok && entry.Source != "" {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
 			return
 		}
-		output := strings.TrimSpace(string(out))
-		if output != "" {
-			fmt.Fprintf(os.Stderr, "\n[skill update check]\n%s\n", output)
+		var lines []string
+		for _, r := range CheckSkills(ctx, root, NewRoleRepoProviderSet(root, nil), lock, names) {
+			switch r.Status {
+			case SkillCheckUpdateAvailable:
+				lines = append(lines, fmt.Sprintf("  %s: update available, run 'agent-team skill update %s'", r.Name, r.Name))
+			case SkillCheckError:
+				lines = append(lines, fmt.Sprintf("  %s: check failed: %s", r.Name, r.Error))
+			}
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(os.Stderr, "\n[skill update check]\n%s\n", strings.Join(lines, "\n"))
 		}
 	})
 }
@@ -331,6 +394,12 @@ func InstallSkillsForWorkerFromPath(wtPath, root, roleName, rolePath, provider s
 		return err
 	}
 
+	// skills-lock.json records which copy of each skill was locked; warn on drift
+	lock, err := ReadSkillsLock(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	var cachedSkills []string
 
 	for _, skillName := range skills {
@@ -354,6 +423,7 @@ func InstallSkillsForWorkerFromPath(wtPath, root, roleName, rolePath, provider s
 
 		if cacheHit {
 			cachedSkills = append(cachedSkills, skillName)
+			warnSkillLockDrift(root, lock, shortName, cachePath)
 			// Cache hit: symlink worktree → project cache
 			if err := symlinkSkill(wtPath, provider, shortName, cachePath); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: symlink cached skill '%s' failed: %v\n", shortName, err)
@@ -364,6 +434,7 @@ func InstallSkillsForWorkerFromPath(wtPath, root, roleName, rolePath, provider s
 		// Plain: try local search first
 		skillPath := findSkillPath(root, skillName)
 		if skillPath != "" {
+			warnSkillLockDrift(root, lock, shortName, skillPath)
 			// Found locally: symlink directly to source
 			if err := symlinkSkill(wtPath, provider, shortName, skillPath); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: symlink skill '%s' failed: %v\n", shortName, err)
@@ -376,7 +447,7 @@ func InstallSkillsForWorkerFromPath(wtPath, root, roleName, rolePath, provider s
 
 	// Background update check: if any skills were served from cache, silently check for updates
 	if len(cachedSkills) > 0 && !fresh {
-		go backgroundSkillCheck(root, provider)
+		go backgroundSkillCheck(root, provider, cachedSkills)
 	}
 
 	return nil
@@ -0,0 +1,292 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SkillsLockFileName is the project skills lockfile. It is shared with
+// `npx skills`, which writes source, sourceType and computedHash; agent-team
+// adds where the skill resolves locally and a hash of that content.
+const SkillsLockFileName = "skills-lock.json"
+
+const SkillsLockVersion = 1
+
+// SkillsLockFile is the on-disk skills-lock.json, keyed by skill directory name.
+type SkillsLockFile struct {
+	Version int                        `json:"version"`
+	Skills  map[string]SkillsLockEntry `json:"skills"`
+}
+
+// SkillsLockEntry records one skill. ComputedHash belongs to `npx skills` and
+// is preserved as is; Path, Layer and ContentHash are written by skill lock.
+// Ref, Commit, SkillPath and the timestamps record where skill install
+// fetched the package from.
+type SkillsLockEntry struct {
+	Source       string    `json:"source,omitempty"`
+	SourceType   string    `json:"sourceType,omitempty"`
+	ComputedHash string    `json:"computedHash,omitempty"`
+	Ref          string    `json:"ref,omitempty"`
+	Commit       string    `json:"commit,omitempty"`
+	SkillPath    string    `json:"skillPath,omitempty"`
+	Path         string    `json:"path,omitempty"`
+	Layer        string    `json:"layer,omitempty"`
+	ContentHash  string    `json:"contentHash,omitempty"`
+	InstalledAt  time.Time `json:"installedAt,omitzero"`
+	UpdatedAt    time.Time `json:"updatedAt,omitzero"`
+}
+
+// ResolveSkillsLockPath returns <root>/skills-lock.json.
+func ResolveSkillsLockPath(root string) string {
+	return filepath.Join(root, SkillsLockFileName)
+}
+
+// ReadSkillsLock reads skills-lock.json, returning an empty lock when the
+// file does not exist.
+func ReadSkillsLock(root string) (SkillsLockFile, error) {
+	lock := SkillsLockFile{Version: SkillsLockVersion, Skills: map[string]SkillsLockEntry{}}
+	data, err := os.ReadFile(ResolveSkillsLockPath(root))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lock, nil
+		}
+		return lock, err
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return SkillsLockFile{Version: SkillsLockVersion, Skills: map[string]SkillsLockEntry{}}, fmt.Errorf("parse %s: %w", SkillsLockFileName, err)
+	}
+	if lock.Version == 0 {
+		lock.Version = SkillsLockVersion
+	}
+	if lock.Skills == nil {
+		lock.Skills = map[string]SkillsLockEntry{}
+	}
+	return lock, nil
+}
+
+// WriteSkillsLock writes skills-lock.json with two-space indentation, the
+// format `npx skills` uses.
+func WriteSkillsLock(root string, lock SkillsLockFile) error {
+	if lock.Version == 0 {
+		lock.Version = SkillsLockVersion
+	}
+	if lock.Skills == nil {
+		lock.Skills = map[string]SkillsLockEntry{}
+	}
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ResolveSkillsLockPath(root), append(data, '\n'), 0644)
+}
+
+// SkillCandidate is one location checked while resolving a skill.
+type SkillCandidate struct {
+	Layer  string `json:"layer"`
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+}
+
+// SkillResolution explains where a skill resolves on this machine. Path is
+// empty when no candidate exists.
+type SkillResolution struct {
+	Name       string           `json:"name"`
+	ShortName  string           `json:"shortName"`
+	Path       string           `json:"path,omitempty"`
+	Layer      string           `json:"layer,omitempty"`
+	Candidates []SkillCandidate `json:"candidates"`
+}
+
+// ResolveSkill resolves a skill the way InstallSkillsForWorkerFromPath does
+// for a worker: the project cache (by short name) first, then the search
+// layers for the full name and then the short name. Every candidate is
+// recorded so callers can explain the choice. No remote download is tried.
+func ResolveSkill(root, skillName string) SkillResolution {
+	res := SkillResolution{Name: skillName, ShortName: parseSkillName(skillName)}
+	check := func(layer, path string) {
+		_, err := os.Stat(path)
+		exists := err == nil
+		res.Candidates = append(res.Candidates, SkillCandidate{Layer: layer, Path: path, Exists: exists})
+		if exists && res.Path == "" {
+			res.Path, res.Layer = path, layer
+		}
+	}
+	check("project-cache", projectSkillPath(root, "", res.ShortName))
+
+	names := []string{skillName}
+	if res.ShortName != skillName {
+		names = append(names, res.ShortName)
+	}
+	for _, name := range names {
+		for _, layer := range skillSearchLayers(root) {
+			check(layer.Name, filepath.Join(layer.Dir, name))
+		}
+	}
+	return res
+}
+
+// HashSkillDir hashes the regular files under a skill directory (following
+// a symlinked root) in the same path/blob-SHA format role-repo uses for
+// folder hashes.
+func HashSkillDir(dir string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	files, ok, err := readRoleRepoInstalledFiles(resolved)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	entries := make([]RoleRepoTreeEntry, 0, len(files))
+	for rel, data := range files {
+		entries = append(entries, RoleRepoTreeEntry{Path: rel, Type: "blob", SHA: gitBlobSHA(data)})
+	}
+	return hashRoleRepoTreeFiles(entries), nil
+}
+
+// skillsLockPath stores a resolved path relative to the project root, or
+// relative to the home directory as ~/..., so the lock is portable.
+func skillsLockPath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return "~/" + filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// SkillsLockEntryFor builds the lock entry for a resolved skill, keeping the
+// `npx skills` fields of an existing entry.
+func SkillsLockEntryFor(root string, res SkillResolution, existing SkillsLockEntry) (SkillsLockEntry, error) {
+	if res.Path == "" {
+		return existing, fmt.Errorf("skill %s not found locally", res.Name)
+	}
+	hash, err := HashSkillDir(res.Path)
+	if err != nil {
+		return existing, fmt.Errorf("hash skill %s: %w", res.Name, err)
+	}
+	entry := existing
+	if entry.Source == "" {
+		switch {
+		case strings.Contains(res.Name, "@"):
+			entry.Source, entry.SourceType = res.Name[:strings.LastIndex(res.Name, "@")], RoleRepoSourceGitHub
+		case isScopedSkill(res.Name):
+			entry.Source, entry.SourceType = res.Name, RoleRepoSourceGitHub
+		default:
+			entry.SourceType = RoleRepoSourceLocal
+		}
+	}
+	entry.Path = skillsLockPath(root, res.Path)
+	entry.Layer = res.Layer
+	entry.ContentHash = hash
+	return entry, nil
+}
+
+// ProjectRoleSkills returns the skills referenced by project roles after
+// inheritance, sorted and de-duplicated.
+func ProjectRoleSkills(root string) ([]string, error) {
+	var skills []string
+	for _, role := range ListAvailableRoles(root) {
+		names, err := ReadRoleSkillsFromPath(RoleDir(root, role))
+		if err != nil {
+			return nil, fmt.Errorf("role %s: %w", role, err)
+		}
+		skills = append(skills, names...)
+	}
+	skills = DedupeKeepOrder(skills)
+	sort.Strings(skills)
+	return skills, nil
+}
+
+// Skill verification statuses.
+const (
+	SkillVerifyOK       = "ok"
+	SkillVerifyDrift    = "drift"    // content hash differs from the lock
+	SkillVerifyMoved    = "moved"    // same content, resolved from a different path
+	SkillVerifyMissing  = "missing"  // locked but not found locally
+	SkillVerifyUnlocked = "unlocked" // entry has no agent-team contentHash
+)
+
+// SkillVerifyResult compares a lock entry with the current resolution.
+type SkillVerifyResult struct {
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	LockedPath   string `json:"lockedPath,omitempty"`
+	LockedHash   string `json:"lockedHash,omitempty"`
+	ResolvedPath string `json:"resolvedPath,omitempty"`
+	ResolvedHash string `json:"resolvedHash,omitempty"`
+}
+
+// VerifySkill checks one skill against its lock entry.
+func VerifySkill(root, name string, entry SkillsLockEntry) SkillVerifyResult {
+	result := SkillVerifyResult{Name: name, LockedPath: entry.Path, LockedHash: entry.ContentHash}
+	if entry.ContentHash == "" {
+		result.Status = SkillVerifyUnlocked
+		return result
+	}
+	res := ResolveSkill(root, name)
+	if res.Path == "" {
+		result.Status = SkillVerifyMissing
+		return result
+	}
+	result.ResolvedPath = skillsLockPath(root, res.Path)
+	hash, err := HashSkillDir(res.Path)
+	if err != nil {
+		result.Status = SkillVerifyMissing
+		return result
+	}
+	result.ResolvedHash = hash
+	switch {
+	case hash != entry.ContentHash:
+		result.Status = SkillVerifyDrift
+	case entry.Path != "" && result.ResolvedPath != entry.Path:
+		result.Status = SkillVerifyMoved
+	default:
+		result.Status = SkillVerifyOK
+	}
+	return result
+}
+
+// VerifySkillsLock checks every entry in the lock, sorted by name.
+func VerifySkillsLock(root string, lock SkillsLockFile) []SkillVerifyResult {
+	names := make([]string, 0, len(lock.Skills))
+	for name := range lock.Skills {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	results := make([]SkillVerifyResult, 0, len(names))
+	for _, name := range names {
+		results = append(results, VerifySkill(root, name, lock.Skills[name]))
+	}
+	return results
+}
+
+// warnSkillLockDrift prints a warning when the skill a worker is about to
+// use differs from the copy recorded in skills-lock.json.
+func warnSkillLockDrift(root string, lock SkillsLockFile, shortName, path string) {
+	entry, ok := lock.Skills[shortName]
+	if !ok || entry.ContentHash == "" {
+		return
+	}
+	hash, err := HashSkillDir(path)
+	if err != nil {
+		return
+	}
+	resolved := skillsLockPath(root, path)
+	if hash != entry.ContentHash {
+		fmt.Fprintf(os.Stderr, "Warning: skill '%s' at %s does not match skills-lock.json (locked %s from %s); run 'agent-team skill why %s'\n", shortName, resolved, shortRoleRepoHash(entry.ContentHash), entry.Path, shortName)
+	} else if entry.Path != "" && resolved != entry.Path {
+		fmt.Fprintf(os.Stderr, "Warning: skill '%s' resolved from %s, but skills-lock.json records %s\n", shortName, resolved, entry.Path)
+	}
+}
@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSkillFixture(t *testing.T, dir, body string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveSkillFollowsWorkerOrder(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CLAUDE_PLUGIN_ROOT", "")
+	root := t.TempDir()
+	writeSkillFixture(t, filepath.Join(home, ".claude", "skills", "vite"), "user copy\n")
+	writeSkillFixture(t, filepath.Join(root, "skills", "vite"), "project copy\n")
+
+	res := ResolveSkill(root, "antfu/skills@vite")
+	if res.Layer != "project-skills" || res.Path != filepath.Join(root, "skills", "vite") {
+		t.Fatalf("resolved %s (%s)", res.Path, res.Layer)
+	}
+	if res.Candidates[0].Layer != "project-cache" || res.Candidates[0].Exists {
+		t.Fatalf("project cache should be checked first: %+v", res.Candidates[0])
+	}
+
+	// A project cache entry wins over every search layer, as in worker open.
+	writeSkillFixture(t, filepath.Join(root, ".agent-team", ".cache", "skills", "vite"), "cached copy\n")
+	if res := ResolveSkill(root, "antfu/skills@vite"); res.Layer != "project-cache" {
+		t.Fatalf("expected project cache, got %s", res.Layer)
+	}
+	if res := ResolveSkill(root, "missing"); res.Path != "" || len(res.Candidates) == 0 {
+		t.Fatalf("unexpected resolution for missing skill: %+v", res)
+	}
+}
+
+func TestSkillsLockKeepsNpxFieldsAndDetectsDrift(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CLAUDE_PLUGIN_ROOT", "")
+	root := t.TempDir()
+	npxLock := `{
+  "version": 1,
+  "skills": {
+    "vite": {
+      "source": "antfu/skills",
+      "sourceType": "github",
+      "computedHash": "abc123"
+    }
+  }
+}
+`
+	if err := os.WriteFile(ResolveSkillsLockPath(root), []byte(npxLock), 0644); err != nil {
+		t.Fatal(err)
+	}
+	skillDir := filepath.Join(root, "skills", "vite")
+	writeSkillFixture(t, skillDir, "v1\n")
+
+	lock, err := ReadSkillsLock(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := VerifySkillsLock(root, lock); len(got) != 1 || got[0].Status != SkillVerifyUnlocked {
+		t.Fatalf("npx-only entry should be unlocked: %+v", got)
+	}
+	res := ResolveSkill(root, "antfu/skills@vite")
+	entry, err := SkillsLockEntryFor(root, res, lock.Skills["vite"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock.Skills["vite"] = entry
+	if err := WriteSkillsLock(root, lock); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(ResolveSkillsLockPath(root))
+	for _, want := range []string{`"computedHash": "abc123"`, `"path": "skills/vite"`, `"layer": "project-skills"`, `"contentHash": "`} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("skills-lock.json missing %s:\n%s", want, data)
+		}
+	}
+
+	lock, _ = ReadSkillsLock(root)
+	if got := VerifySkill(root, "vite", lock.Skills["vite"]); got.Status != SkillVerifyOK {
+		t.Fatalf("expected ok, got %+v", got)
+	}
+
+	// Same content in a higher-priority layer: moved, not drifted.
+	writeSkillFixture(t, filepath.Join(root, ".agent-team", ".cache", "skills", "vite"), "v1\n")
+	if got := VerifySkill(root, "vite", lock.Skills["vite"]); got.Status != SkillVerifyMoved || got.ResolvedPath != ".agent-team/.cache/skills/vite" {
+		t.Fatalf("expected moved, got %+v", got)
+	}
+	writeSkillFixture(t, filepath.Join(root, ".agent-team", ".cache", "skills", "vite"), "v2\n")
+	if got := VerifySkill(root, "vite", lock.Skills["vite"]); got.Status != SkillVerifyDrift {
+		t.Fatalf("expected drift, got %+v", got)
+	}
+	os.RemoveAll(filepath.Join(root, ".agent-team"))
+	os.RemoveAll(skillDir)
+	if got := VerifySkill(root, "vite", lock.Skills["vite"]); got.Status != SkillVerifyMissing {
+		t.Fatalf("expected missing, got %+v", got)
+	}
+}
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// StateMigrationEntry reports one artifact upgraded by MigrateStateTree.
+type StateMigrationEntry struct {
+	Path        string
+	Kind        SchemaKind
+	FromVersion int
+	ToVersion   int
+	Applied     []string
+}
+
+type stateArtifact struct {
+	path string
+	kind SchemaKind
+}
+
+// MigrateStateTree upgrades every versioned artifact under the project's
+// .agent-team directory and in each worker worktree to the current schema.
+// With dryRun set, nothing is written and the report lists pending changes.
+func MigrateStateTree(root, wtBase string, dryRun bool) ([]StateMigrationEntry, error) {
+	artifacts, err := collectStateArtifacts(root)
+	if err != nil {
+		return nil, err
+	}
+	wtDir := filepath.Join(root, wtBase)
+	if entries, err := os.ReadDir(wtDir); err == nil {
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			wtPath := filepath.Join(wtDir, entry.Name())
+			if fileExists(WorkerYAMLPath(wtPath)) {
+				artifacts = append(artifacts, stateArtifact{path: WorkerYAMLPath(wtPath), kind: SchemaKindWorker})
+			}
+			nested, err := collectStateArtifacts(wtPath)
+			if err != nil {
+				return nil, err
+			}
+			artifacts = append(artifacts, nested...)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read worktree directory %s: %w", wtDir, err)
+	}
+
+	var report []StateMigrationEntry
+	for _, artifact := range artifacts {
+		entry, changed, err := migrateStateArtifact(artifact, dryRun)
+		if err != nil {
+			return report, err
+		}
+		if changed {
+			report = append(report, entry)
+		}
+	}
+	return report, nil
+}
+
+func migrateStateArtifact(artifact stateArtifact, dryRun bool) (StateMigrationEntry, bool, error) {
+	entry := StateMigrationEntry{Path: artifact.path, Kind: artifact.kind}
+	unlock := func() {}
+	if !dryRun {
+		var err error
+		unlock, err = LockState(artifact.path)
+		if err != nil {
+			return entry, false, err
+		}
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(artifact.path)
+	if err != nil {
+		return entry, false, fmt.Errorf("read %s: %w", artifact.path, err)
+	}
+	result, err := MigrateSchema(artifact.kind, data)
+	if err != nil {
+		return entry, false, fmt.Errorf("%s: %w", artifact.path, err)
+	}
+	if !result.Changed() {
+		return entry, false, nil
+	}
+	entry.FromVersion = result.FromVersion
+	entry.ToVersion = result.ToVersion
+	entry.Applied = result.Applied
+	if dryRun {
+		return entry, true, nil
+	}
+	if err := WriteFileAtomic(artifact.path, result.Data, 0644); err != nil {
+		return entry, false, fmt.Errorf("write %s: %w", artifact.path, err)
+	}
+	return entry, true, nil
+}
+
+// collectStateArtifacts lists task, planning and workflow plan files under
+// root/.agent-team in a stable order.
+func collectStateArtifacts(root string) ([]stateArtifact, error) {
+	var artifacts []stateArtifact
+
+	for _, base := range []string{TasksRootDir(root), TasksArchiveRootDir(root), TasksDeprecatedRootDir(root)} {
+		dirs, err := listSubdirs(base)
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			path := filepath.Join(dir, "task.yaml")
+			if fileExists(path) {
+				artifacts = append(artifacts, stateArtifact{path: path, kind: SchemaKindTask})
+			}
+		}
+	}
+
+	for _, lifecycle := range []PlanningLifecycle{PlanningLifecycleActive, PlanningLifecycleArchived, PlanningLifecycleDeprecated} {
+		for _, kind := range []PlanningKind{PlanningKindRoadmap, PlanningKindMilestone, PlanningKindPhase} {
+			dirs, err := listSubdirs(PlanningKindRootDir(root, kind, lifecycle))
+			if err != nil {
+				return nil, err
+			}
+			for _, dir := range dirs {
+				path := filepath.Join(dir, planningFileName(kind))
+				if fileExists(path) {
+					artifacts = append(artifacts, stateArtifact{path: path, kind: SchemaKindPlanning})
+				}
+			}
+		}
+	}
+
+	plans, err := filepath.Glob(filepath.Join(ResolveAgentsDir(root), "workflow", "plans", "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(plans)
+	for _, path := range plans {
+		artifacts = append(artifacts, stateArtifact{path: path, kind: SchemaKindWorkflowPlan})
+	}
+	return artifacts, nil
+}
+
+func listSubdirs(base string) ([]string, error) {
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read directory %s: %w", base, err)
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(base, entry.Name()))
+		}
+	}
+	return dirs, nil
+}
@@ -0,0 +1,217 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrStateConflict is returned when an artifact was modified by another
+// process between load and save.
+var ErrStateConflict = errors.New("state conflict")
+
+// StateLockPath returns the advisory lock file guarding an artifact.
+func StateLockPath(path string) string {
+	return path + ".lock"
+}
+
+// SharedStateLockPath returns a lock file in the user cache directory for an
+// artifact outside .agent-team/, such as roles-lock.json at the project root,
+// so locking it leaves no untracked file next to it.
+func SharedStateLockPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+		if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+			path = filepath.Join(dir, filepath.Base(abs))
+		}
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(dir, "agent-team", "locks", hex.EncodeToString(sum[:8])+"-"+filepath.Base(path)+".lock")
+}
+
+// stateLockIgnoreEntry keeps advisory lock files, which are per-machine,
+// out of git.
+const stateLockIgnoreEntry = "*.lock"
+
+// StateLocksIgnored reports whether .agent-team/.gitignore ignores lock files.
+func StateLocksIgnored(root string) bool {
+	data, err := os.ReadFile(filepath.Join(AgentTeamDir(root), ".gitignore"))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == stateLockIgnoreEntry {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureStateLocksIgnored adds *.lock to .agent-team/.gitignore, creating
+// the file when needed.
+func EnsureStateLocksIgnored(root string) error {
+	return ensureStateIgnoreEntry(root, stateLockIgnoreEntry)
+}
+
+// LockState acquires an exclusive advisory lock for the artifact at path.
+// The lock blocks until it is available; callers must invoke the returned
+// unlock function.
+func LockState(path string) (func(), error) {
+	return lockStateFile(StateLockPath(path), filepath.Base(path))
+}
+
+// WithStateLock runs fn while holding the artifact lock for path, so a
+// load-modify-save sequence in fn cannot interleave with another writer.
+func WithStateLock(path string, fn func() error) error {
+	unlock, err := LockState(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
+
+// WithSharedStateLock is WithStateLock for artifacts outside .agent-team/;
+// the lock file lives at SharedStateLockPath.
+func WithSharedStateLock(path string, fn func() error) error {
+	unlock, err := lockStateFile(SharedStateLockPath(path), filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
+
+func lockStateFile(lockPath, name string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, fmt.Errorf("create lock directory: %w", err)
+	}
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("lock %s: %w", name, err)
+	}
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
+
+// WriteFileAtomic writes data to a temp file in the target directory and
+// renames it over path, so readers never observe a partially written file.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	cleanup := func() {
+		_ = os.Remove(tmpPath)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		cleanup()
+		return err
+	}
+	return nil
+}
+
+// WriteFileLocked writes data atomically while holding the artifact lock.
+func WriteFileLocked(path string, data []byte, perm os.FileMode) error {
+	unlock, err := LockState(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return WriteFileAtomic(path, data, perm)
+}
+
+// stateRevision is the minimal shape used to read the revision stored on disk.
+type stateRevision struct {
+	Revision int `yaml:"revision"`
+}
+
+// saveYAMLState persists v to path with optimistic concurrency. revision
+// points at the revision the caller loaded; the save fails with
+// ErrStateConflict if the file on disk has moved past it. A zero revision
+// marks a record that was not loaded from a versioned file (new records and
+// legacy artifacts); it may create a file or replace an unversioned one, but
+// never overwrite a versioned file. On success the revision is incremented
+// to match what was written.
+func saveYAMLState(path string, revision *int, v any) error {
+	unlock, err := LockState(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := readYAMLRevision(path)
+	if err != nil {
+		return err
+	}
+	if current != *revision {
+		return fmt.Errorf("%w: %s was modified by another process (on disk revision %d, loaded revision %d)", ErrStateConflict, path, current, *revision)
+	}
+
+	loaded := *revision
+	*revision = current + 1
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		*revision = loaded
+		return fmt.Errorf("marshal %s: %w", filepath.Base(path), err)
+	}
+	if err := WriteFileAtomic(path, data, 0644); err != nil {
+		*revision = loaded
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func readYAMLRevision(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+	var rev stateRevision
+	if err := yaml.Unmarshal(data, &rev); err != nil {
+		return 0, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	return rev.Revision, nil
+}
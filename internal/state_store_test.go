@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteFileAtomicReplacesContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "task.yaml")
+	if err := WriteFileAtomic(path, []byte("first\n"), 0644); err != nil {
+		t.Fatalf("WriteFileAtomic first: %v", err)
+	}
+	if err := WriteFileAtomic(path, []byte("second\n"), 0644); err != nil {
+		t.Fatalf("WriteFileAtomic second: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "second\n" {
+		t.Fatalf("content = %q", string(data))
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected no temp files left behind, got %d entries", len(entries))
+	}
+}
+
+func TestSaveTaskRecordDetectsStaleRevision(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	record, err := CreateTaskPackage(root, "Conflict Task", "backend", "", now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	if record.Revision != 1 {
+		t.Fatalf("Revision = %d, want 1", record.Revision)
+	}
+
+	first, _, err := LoadTaskRecord(root, record.TaskID)
+	if err != nil {
+		t.Fatalf("LoadTaskRecord first: %v", err)
+	}
+	stale, _, err := LoadTaskRecord(root, record.TaskID)
+	if err != nil {
+		t.Fatalf("LoadTaskRecord stale: %v", err)
+	}
+
+	first.Title = "Updated"
+	if err := SaveTaskRecord(root, first); err != nil {
+		t.Fatalf("SaveTaskRecord first: %v", err)
+	}
+	stale.Title = "Lost update"
+	err = SaveTaskRecord(root, stale)
+	if !errors.Is(err, ErrStateConflict) {
+		t.Fatalf("SaveTaskRecord stale err = %v, want ErrStateConflict", err)
+	}
+
+	loaded, _, err := LoadTaskRecord(root, record.TaskID)
+	if err != nil {
+		t.Fatalf("LoadTaskRecord: %v", err)
+	}
+	if loaded.Title != "Updated" || loaded.Revision != 2 {
+		t.Fatalf("loaded = %+v", loaded)
+	}
+}
+
+func TestSaveYAMLStateRejectsBlindOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "worker.yaml")
+	if err := os.WriteFile(path, []byte("worker_id: legacy\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	// An unversioned legacy file may be replaced by a record built from scratch.
+	first := &WorkerConfig{WorkerID: "first"}
+	if err := first.Save(path); err != nil {
+		t.Fatalf("Save over legacy file: %v", err)
+	}
+	second := &WorkerConfig{WorkerID: "second"}
+	if err := second.Save(path); !errors.Is(err, ErrStateConflict) {
+		t.Fatalf("Save over versioned file = %v, want ErrStateConflict", err)
+	}
+	cfg, err := LoadWorkerConfig(path)
+	if err != nil {
+		t.Fatalf("LoadWorkerConfig: %v", err)
+	}
+	if cfg.WorkerID != "first" || cfg.Revision != 1 {
+		t.Fatalf("config = %+v", cfg)
+	}
+}
+
+func TestParallelMarkTaskDoneSerializes(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	record, err := CreateTaskPackage(root, "Parallel Task", "backend", "", now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	bound, err := BindTaskToWorker(root, record.TaskID, "backend-001", "", now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("BindTaskToWorker: %v", err)
+	}
+	// A caller that loads the record after the winner saved fails the
+	// transition check rather than conflicting.
+	transitionErr := ValidateTaskTransition(TaskStatusVerifying, TaskStatusVerifying)
+	if transitionErr == nil {
+		t.Fatal("verifying -> verifying should be an invalid transition")
+	}
+
+	const callers = 8
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = MarkTaskDone(root, record.TaskID, "", now.Add(2*time.Minute))
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ErrStateConflict), err.Error() == transitionErr.Error():
+		default:
+			t.Errorf("caller %d: unexpected error %v", i, err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%d MarkTaskDone calls succeeded, want exactly 1 (errors: %v)", succeeded, errs)
+	}
+	loaded, _, err := LoadTaskRecord(root, record.TaskID)
+	if err != nil {
+		t.Fatalf("LoadTaskRecord after parallel done: %v", err)
+	}
+	if loaded.Status != TaskStatusVerifying || loaded.Revision != bound.Revision+1 {
+		t.Fatalf("Status = %s, Revision = %d, want %s at revision %d", loaded.Status, loaded.Revision, TaskStatusVerifying, bound.Revision+1)
+	}
+}
@@ -4,10 +4,10 @@ package internal
 type TaskStatus string
 
 const (
-	TaskStatusDraft     TaskStatus = "draft"
-	TaskStatusAssigned  TaskStatus = "assigned"
-	TaskStatusVerifying TaskStatus = "verifying"
-	TaskStatusArchived  TaskStatus = "archived"
+	TaskStatusDraft      TaskStatus = "draft"
+	TaskStatusAssigned   TaskStatus = "assigned"
+	TaskStatusVerifying  TaskStatus = "verifying"
+	TaskStatusArchived   TaskStatus = "archived"
 	TaskStatusDeprecated TaskStatus = "deprecated"
 )
 
@@ -22,19 +22,26 @@ const (
 
 // TaskRecord is the structured record stored in task.yaml.
 type TaskRecord struct {
-	TaskID        string     `yaml:"task_id"`
-	Title         string     `yaml:"title"`
-	Role          string     `yaml:"role"`
-	Status        TaskStatus `yaml:"status"`
-	WorkerID      string     `yaml:"worker_id,omitempty"`
-	TaskPath      string     `yaml:"task_path"`
-	CreatedAt     string     `yaml:"created_at"`
-	AssignedAt    string     `yaml:"assigned_at,omitempty"`
-	VerifyingAt   string     `yaml:"verifying_at,omitempty"`
-	ArchivedAt    string     `yaml:"archived_at,omitempty"`
-	DeprecatedAt  string     `yaml:"deprecated_at,omitempty"`
-	MergedSHA     string     `yaml:"merged_sha,omitempty"`
-	LegacyDoneAt  string     `yaml:"done_at,omitempty"`
+	SchemaVersion int               `yaml:"schema_version"`
+	TaskID        string            `yaml:"task_id"`
+	Title         string            `yaml:"title"`
+	Role          string            `yaml:"role"`
+	Status        TaskStatus        `yaml:"status"`
+	WorkerID      string            `yaml:"worker_id,omitempty"`
+	TaskPath      string            `yaml:"task_path"`
+	CreatedAt     string            `yaml:"created_at"`
+	AssignedAt    string            `yaml:"assigned_at,omitempty"`
+	VerifyingAt   string            `yaml:"verifying_at,omitempty"`
+	ArchivedAt    string            `yaml:"archived_at,omitempty"`
+	DeprecatedAt  string            `yaml:"deprecated_at,omitempty"`
+	MergedSHA     string            `yaml:"merged_sha,omitempty"`
+	Requirement   string            `yaml:"requirement,omitempty"`
+	SubTaskID     int               `yaml:"sub_task_id,omitempty"`
+	ParentIDs     []string          `yaml:"parent_ids,omitempty"`
+	DependsOn     []string          `yaml:"depends_on,omitempty"`
+	ImportKey     string            `yaml:"import_key,omitempty"`
+	ExternalRefs  map[string]string `yaml:"external_refs,omitempty"`
+	Revision      int               `yaml:"revision,omitempty"`
 }
 
 func ValidTaskStatus(status TaskStatus) bool {
@@ -0,0 +1,371 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// External systems tracked in TaskRecord.ExternalRefs.
+const (
+	ExternalSystemGitHub = "github"
+	ExternalSystemJira   = "jira"
+)
+
+// Task export formats.
+const (
+	TaskExportGitHubIssues = "github-issues"
+	TaskExportJiraCSV      = "jira-csv"
+	TaskExportMarkdown     = "markdown"
+)
+
+// taskMarkerRe matches the hidden marker that ties an exported issue body back
+// to its task package.
+var taskMarkerRe = regexp.MustCompile(`<!--\s*agent-team-task:\s*(\S+)\s*-->`)
+
+func taskMarker(taskID string) string {
+	return "<!-- agent-team-task: " + taskID + " -->"
+}
+
+// gitHubIssue mirrors the fields of `gh issue list --json number,title,body,state,labels,url`.
+type gitHubIssue struct {
+	Number int           `json:"number,omitempty"`
+	Title  string        `json:"title"`
+	Body   string        `json:"body"`
+	State  string        `json:"state"`
+	Labels []gitHubLabel `json:"labels"`
+	URL    string        `json:"url,omitempty"`
+}
+
+type gitHubLabel struct {
+	Name string `json:"name"`
+}
+
+// ExternalIssue is an issue read from a tracker export.
+type ExternalIssue struct {
+	System string
+	Ref    string
+	Title  string
+	Body   string
+	Closed bool
+	Labels []string
+	TaskID string // from an agent-team marker in the body, if present
+}
+
+// ParseGitHubIssuesJSON reads the output of
+// `gh issue list --json number,title,body,state,labels,url`.
+func ParseGitHubIssuesJSON(data []byte) ([]ExternalIssue, error) {
+	var raw []gitHubIssue
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse github issues json: %w", err)
+	}
+	issues := make([]ExternalIssue, 0, len(raw))
+	for i, item := range raw {
+		ref := item.URL
+		if ref == "" && item.Number > 0 {
+			ref = "#" + strconv.Itoa(item.Number)
+		}
+		if ref == "" {
+			return nil, fmt.Errorf("github issue %d has neither url nor number", i+1)
+		}
+		issue := ExternalIssue{
+			System: ExternalSystemGitHub,
+			Ref:    ref,
+			Title:  strings.TrimSpace(item.Title),
+			Body:   item.Body,
+			Closed: strings.EqualFold(item.State, "closed"),
+		}
+		for _, label := range item.Labels {
+			issue.Labels = append(issue.Labels, label.Name)
+		}
+		if m := taskMarkerRe.FindStringSubmatch(item.Body); m != nil {
+			issue.TaskID = m[1]
+			issue.Body = strings.TrimSpace(taskMarkerRe.ReplaceAllString(item.Body, ""))
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// roleFromLabels returns the role named by a "role:<name>" label.
+func roleFromLabels(labels []string) string {
+	for _, label := range labels {
+		if role, ok := strings.CutPrefix(label, "role:"); ok {
+			return strings.TrimSpace(role)
+		}
+	}
+	return ""
+}
+
+// TaskImportUpdate is reported for issues that refreshed an existing task.
+const TaskImportUpdate TaskImportAction = "update"
+
+// ImportExternalIssues syncs tracker issues into task packages. An issue is
+// matched to a task through ExternalRefs, then through the agent-team marker
+// in its body; matched tasks get their title and reference refreshed, other
+// open issues become new tasks whose design is the issue body. Closed issues
+// without a task are skipped. The role comes from a "role:<name>" label or
+// opts.Role.
+func ImportExternalIssues(root string, issues []ExternalIssue, opts TaskImportOptions, now time.Time) ([]TaskImportResult, error) {
+	tasks, err := ListTasks(root, false)
+	if err != nil {
+		return nil, err
+	}
+	byRef := map[string]string{}
+	byID := map[string]bool{}
+	for _, task := range tasks {
+		byID[task.TaskID] = true
+		for system, ref := range task.ExternalRefs {
+			byRef[system+"\x00"+ref] = task.TaskID
+		}
+	}
+
+	var results []TaskImportResult
+	var toCreate []ExternalIssue
+	for _, issue := range issues {
+		item := TaskImportItem{Key: issue.Ref, Title: issue.Title, Role: roleFromLabels(issue.Labels), Phase: opts.Phase}
+		if item.Role == "" {
+			item.Role = opts.Role
+		}
+		taskID := byRef[issue.System+"\x00"+issue.Ref]
+		if taskID == "" && issue.TaskID != "" && byID[issue.TaskID] {
+			taskID = issue.TaskID
+		}
+		if taskID != "" {
+			action, err := refreshExternalTask(root, taskID, issue, opts.DryRun)
+			if err != nil {
+				return results, err
+			}
+			results = append(results, TaskImportResult{Item: item, Action: action, TaskID: taskID})
+			continue
+		}
+		if issue.Closed {
+			continue
+		}
+		if item.Title == "" {
+			return results, fmt.Errorf("%s issue %s: title is required", issue.System, issue.Ref)
+		}
+		if item.Role == "" {
+			return results, fmt.Errorf("%s issue %s: role is required (add a role:<name> label or pass --role)", issue.System, issue.Ref)
+		}
+		if _, err := ResolveRole(root, item.Role); err != nil {
+			return results, fmt.Errorf("%s issue %s: %w", issue.System, issue.Ref, err)
+		}
+		results = append(results, TaskImportResult{Item: item, Action: TaskImportCreate})
+		toCreate = append(toCreate, issue)
+	}
+	if opts.Phase != "" && len(toCreate) > 0 {
+		phase, err := LoadPlanningRecord(root, opts.Phase)
+		if err != nil {
+			return results, err
+		}
+		if phase.Kind != PlanningKindPhase {
+			return results, fmt.Errorf("'%s' is a %s, not a phase", opts.Phase, phase.Kind)
+		}
+	}
+	if opts.DryRun {
+		return results, nil
+	}
+
+	next := 0
+	for i := range results {
+		if results[i].Action != TaskImportCreate {
+			continue
+		}
+		issue := toCreate[next]
+		next++
+		record, err := CreateTaskPackageWithOptions(root, TaskCreateOptions{
+			Title:        results[i].Item.Title,
+			Role:         results[i].Item.Role,
+			Design:       issue.Body,
+			DesignPath:   issue.Ref,
+			Phase:        opts.Phase,
+			ExternalRefs: map[string]string{issue.System: issue.Ref},
+		}, now)
+		if err != nil {
+			return results[:i], fmt.Errorf("%s issue %s: %w", issue.System, issue.Ref, err)
+		}
+		results[i].TaskID = record.TaskID
+		if opts.Phase != "" {
+			if _, err := LinkPlanning(root, opts.Phase, record.TaskID, now); err != nil {
+				return results[:i+1], fmt.Errorf("%s issue %s: link phase: %w", issue.System, issue.Ref, err)
+			}
+		}
+	}
+	return results, nil
+}
+
+func refreshExternalTask(root, taskID string, issue ExternalIssue, dryRun bool) (TaskImportAction, error) {
+	record, _, err := LoadTaskRecord(root, taskID)
+	if err != nil {
+		return "", err
+	}
+	changed := false
+	if issue.Title != "" && record.Title != issue.Title {
+		record.Title = issue.Title
+		changed = true
+	}
+	if record.ExternalRefs[issue.System] != issue.Ref {
+		if record.ExternalRefs == nil {
+			record.ExternalRefs = map[string]string{}
+		}
+		record.ExternalRefs[issue.System] = issue.Ref
+		changed = true
+	}
+	if !changed {
+		return TaskImportExists, nil
+	}
+	if !dryRun {
+		if err := SaveTaskRecord(root, record); err != nil {
+			return "", fmt.Errorf("update task '%s': %w", taskID, err)
+		}
+	}
+	return TaskImportUpdate, nil
+}
+
+// ExportTasks renders tasks in one of the TaskExport* formats. Task bodies
+// come from each package's context.md.
+func ExportTasks(root string, tasks []*TaskRecord, format string) ([]byte, error) {
+	switch format {
+	case TaskExportGitHubIssues:
+		return exportGitHubIssues(root, tasks)
+	case TaskExportJiraCSV:
+		return exportJiraCSV(root, tasks)
+	case TaskExportMarkdown:
+		return exportTaskMarkdown(tasks), nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q: use %s, %s, or %s", format, TaskExportGitHubIssues, TaskExportJiraCSV, TaskExportMarkdown)
+	}
+}
+
+func exportGitHubIssues(root string, tasks []*TaskRecord) ([]byte, error) {
+	issues := make([]gitHubIssue, 0, len(tasks))
+	for _, task := range tasks {
+		body, err := readTaskContext(root, task)
+		if err != nil {
+			return nil, err
+		}
+		issue := gitHubIssue{
+			Title:  task.Title,
+			Body:   strings.TrimRight(body, "\n") + "\n\n" + taskMarker(task.TaskID) + "\n",
+			State:  "OPEN",
+			Labels: []gitHubLabel{{Name: "role:" + task.Role}, {Name: "status:" + string(task.Status)}},
+		}
+		if taskClosed(task) {
+			issue.State = "CLOSED"
+		}
+		if ref := task.ExternalRefs[ExternalSystemGitHub]; ref != "" {
+			issue.URL = ref
+			if n, ok := gitHubIssueNumber(ref); ok {
+				issue.Number = n
+			}
+		}
+		issues = append(issues, issue)
+	}
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+var jiraStatusByTaskStatus = map[TaskStatus]string{
+	TaskStatusDraft:      "To Do",
+	TaskStatusAssigned:   "In Progress",
+	TaskStatusVerifying:  "In Review",
+	TaskStatusArchived:   "Done",
+	TaskStatusDeprecated: "Won't Do",
+}
+
+func exportJiraCSV(root string, tasks []*TaskRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"Summary", "Issue key", "Issue Type", "Status", "Labels", "Description", "External ID"})
+	for _, task := range tasks {
+		body, err := readTaskContext(root, task)
+		if err != nil {
+			return nil, err
+		}
+		if err := w.Write([]string{
+			task.Title,
+			task.ExternalRefs[ExternalSystemJira],
+			"Task",
+			jiraStatusByTaskStatus[task.Status],
+			"role-" + task.Role,
+			body,
+			task.TaskID,
+		}); err != nil {
+			return nil, fmt.Errorf("write jira csv: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("write jira csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// exportTaskMarkdown writes a checklist that `task import` reads back.
+func exportTaskMarkdown(tasks []*TaskRecord) []byte {
+	var sb strings.Builder
+	sb.WriteString("# Tasks\n\n")
+	for _, task := range tasks {
+		check := " "
+		if taskClosed(task) {
+			check = "x"
+		}
+		key := task.ImportKey
+		if key == "" {
+			key = task.TaskID
+		}
+		fmt.Fprintf(&sb, "- [%s] %s\n", check, task.Title)
+		fmt.Fprintf(&sb, "  - key: %s\n", key)
+		fmt.Fprintf(&sb, "  - role: %s\n", task.Role)
+		if len(task.DependsOn) > 0 {
+			fmt.Fprintf(&sb, "  - depends_on: %s\n", strings.Join(task.DependsOn, ", "))
+		}
+	}
+	return []byte(sb.String())
+}
+
+func readTaskContext(root string, task *TaskRecord) (string, error) {
+	path := taskDocPath(root, task.TaskID, taskLocationForStatus(task.Status), "context.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+func taskLocationForStatus(status TaskStatus) TaskRecordLocation {
+	switch status {
+	case TaskStatusArchived:
+		return TaskRecordLocationArchived
+	case TaskStatusDeprecated:
+		return TaskRecordLocationDeprecated
+	default:
+		return TaskRecordLocationActive
+	}
+}
+
+func taskClosed(task *TaskRecord) bool {
+	return task.Status == TaskStatusArchived || task.Status == TaskStatusDeprecated
+}
+
+func gitHubIssueNumber(ref string) (int, bool) {
+	idx := strings.LastIndexAny(ref, "/#")
+	if idx < 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(ref[idx+1:])
+	return n, err == nil
+}
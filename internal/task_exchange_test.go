@@ -0,0 +1,174 @@
+package internal
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupExchangeRole(t *testing.T, root string) {
+	t.Helper()
+	if err := os.MkdirAll(RoleDir(root, "backend"), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(RoleDir(root, "backend"), "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestGitHubIssuesRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	setupExchangeRole(t, root)
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	task, err := CreateTaskPackage(root, "Local task", "backend", "local design", now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+
+	data, err := ExportTasks(root, []*TaskRecord{task}, TaskExportGitHubIssues)
+	if err != nil {
+		t.Fatalf("ExportTasks: %v", err)
+	}
+	var exported []map[string]any
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("exported json: %v\n%s", err, data)
+	}
+	body, _ := exported[0]["body"].(string)
+	if !strings.Contains(body, "local design") || !strings.Contains(body, taskMarker(task.TaskID)) {
+		t.Fatalf("exported body = %q", body)
+	}
+
+	// Simulate `gh issue create` assigning a URL, plus one new issue.
+	exported[0]["url"] = "https://github.com/acme/app/issues/7"
+	exported[0]["number"] = 7
+	exported = append(exported, map[string]any{
+		"number": 8, "title": "From GitHub", "body": "Fix the bug", "state": "OPEN",
+		"labels": []map[string]string{{"name": "role:backend"}}, "url": "https://github.com/acme/app/issues/8",
+	}, map[string]any{
+		"number": 9, "title": "Closed upstream", "state": "CLOSED", "url": "https://github.com/acme/app/issues/9",
+	})
+	synced, _ := json.Marshal(exported)
+	issues, err := ParseGitHubIssuesJSON(synced)
+	if err != nil {
+		t.Fatalf("ParseGitHubIssuesJSON: %v", err)
+	}
+	if issues[0].TaskID != task.TaskID || strings.Contains(issues[0].Body, "agent-team-task") {
+		t.Fatalf("issue[0] = %+v", issues[0])
+	}
+
+	results, err := ImportExternalIssues(root, issues, TaskImportOptions{}, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("ImportExternalIssues: %v", err)
+	}
+	if len(results) != 2 || results[0].Action != TaskImportUpdate || results[1].Action != TaskImportCreate {
+		t.Fatalf("results = %+v", results)
+	}
+	linked, _, _ := LoadTaskRecord(root, task.TaskID)
+	if linked.ExternalRefs[ExternalSystemGitHub] != "https://github.com/acme/app/issues/7" {
+		t.Fatalf("ExternalRefs = %v", linked.ExternalRefs)
+	}
+	created, _, _ := LoadTaskRecord(root, results[1].TaskID)
+	if created.Role != "backend" || created.ExternalRefs[ExternalSystemGitHub] != "https://github.com/acme/app/issues/8" {
+		t.Fatalf("created = %+v", created)
+	}
+	ctx, _ := os.ReadFile(TaskContextPath(root, created.TaskID))
+	if !strings.Contains(string(ctx), "Fix the bug") {
+		t.Fatalf("context.md = %s", ctx)
+	}
+
+	issues[1].Title = "From GitHub (renamed)"
+	again, err := ImportExternalIssues(root, issues, TaskImportOptions{}, now.Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("ImportExternalIssues again: %v", err)
+	}
+	if again[0].Action != TaskImportExists || again[1].Action != TaskImportUpdate || again[1].TaskID != created.TaskID {
+		t.Fatalf("resync = %+v", again)
+	}
+	if tasks, _ := ListTasks(root, false); len(tasks) != 2 {
+		t.Fatalf("tasks after resync = %d, want 2", len(tasks))
+	}
+}
+
+func TestImportExternalIssuesWithDuplicateTitles(t *testing.T) {
+	root := t.TempDir()
+	setupExchangeRole(t, root)
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	issues, err := ParseGitHubIssuesJSON([]byte(`[
+		{"number": 1, "title": "Fix flaky test", "state": "OPEN", "labels": [{"name": "role:backend"}], "url": "https://github.com/acme/app/issues/1"},
+		{"number": 2, "title": "Fix flaky test", "state": "OPEN", "labels": [{"name": "role:backend"}], "url": "https://github.com/acme/app/issues/2"}
+	]`))
+	if err != nil {
+		t.Fatalf("ParseGitHubIssuesJSON: %v", err)
+	}
+
+	results, err := ImportExternalIssues(root, issues, TaskImportOptions{}, now)
+	if err != nil {
+		t.Fatalf("ImportExternalIssues: %v", err)
+	}
+	if len(results) != 2 || results[0].TaskID == results[1].TaskID {
+		t.Fatalf("duplicate titles should create separate tasks: %+v", results)
+	}
+	for i, result := range results {
+		record, _, err := LoadTaskRecord(root, result.TaskID)
+		if err != nil {
+			t.Fatalf("LoadTaskRecord(%s): %v", result.TaskID, err)
+		}
+		if want := issues[i].Ref; record.ExternalRefs[ExternalSystemGitHub] != want {
+			t.Fatalf("task %s ExternalRefs = %v, want %s", result.TaskID, record.ExternalRefs, want)
+		}
+	}
+
+	again, err := ImportExternalIssues(root, issues, TaskImportOptions{}, now)
+	if err != nil {
+		t.Fatalf("re-import: %v", err)
+	}
+	for i, result := range again {
+		if result.Action != TaskImportExists || result.TaskID != results[i].TaskID {
+			t.Fatalf("re-import should find the imported tasks, got %+v", again)
+		}
+	}
+}
+
+func TestExportTasksJiraCSVAndMarkdown(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	task, err := CreateTaskPackageWithOptions(root, TaskCreateOptions{
+		Title: "Ship it", Role: "backend", ExternalRefs: map[string]string{ExternalSystemJira: "APP-3"},
+	}, now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackageWithOptions: %v", err)
+	}
+
+	data, err := ExportTasks(root, []*TaskRecord{task}, TaskExportJiraCSV)
+	if err != nil {
+		t.Fatalf("ExportTasks jira: %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(rows) != 2 || rows[0][0] != "Summary" || rows[1][0] != "Ship it" || rows[1][1] != "APP-3" || rows[1][3] != "To Do" || rows[1][6] != task.TaskID {
+		t.Fatalf("rows = %q", rows)
+	}
+
+	data, err = ExportTasks(root, []*TaskRecord{task}, TaskExportMarkdown)
+	if err != nil {
+		t.Fatalf("ExportTasks markdown: %v", err)
+	}
+	items, err := ParseTaskImportMarkdown(data)
+	if err != nil || len(items) != 1 || items[0].Key != task.TaskID {
+		t.Fatalf("markdown round trip = %+v, %v", items, err)
+	}
+	results, err := ImportTasks(root, items, TaskImportOptions{DryRun: true}, now)
+	if err != nil || results[0].Action != TaskImportExists {
+		t.Fatalf("re-import of exported markdown = %+v, %v", results, err)
+	}
+
+	if _, err := ExportTasks(root, nil, "xml"); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
@@ -0,0 +1,186 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TaskHistoryAction names an entry in a task's history.jsonl.
+type TaskHistoryAction string
+
+const (
+	TaskHistoryCreated    TaskHistoryAction = "created"
+	TaskHistoryTransition TaskHistoryAction = "transition"
+	TaskHistoryComment    TaskHistoryAction = "comment"
+)
+
+// TaskHistoryEntry is one line of history.jsonl. Data keeps values a
+// transition overwrote (previous worker, cleared timestamps, merged SHA) so
+// reassignments and reopenings stay traceable after task.yaml is rewritten.
+type TaskHistoryEntry struct {
+	Time     string            `json:"time"`
+	Action   TaskHistoryAction `json:"action"`
+	Actor    string            `json:"actor"`
+	From     TaskStatus        `json:"from,omitempty"`
+	To       TaskStatus        `json:"to,omitempty"`
+	WorkerID string            `json:"worker_id,omitempty"`
+	Reason   string            `json:"reason,omitempty"`
+	Text     string            `json:"text,omitempty"`
+	Data     map[string]string `json:"data,omitempty"`
+}
+
+// TaskHistorySummary condenses a task's history for task show.
+type TaskHistorySummary struct {
+	Entries     int
+	Transitions int
+	Comments    int
+	Workers     []string
+	Last        *TaskHistoryEntry
+}
+
+// TaskHistoryPath returns history.jsonl inside the task package at location.
+func TaskHistoryPath(root, taskID string, location TaskRecordLocation) string {
+	return filepath.Join(taskDirByLocation(root, taskID, location), "history.jsonl")
+}
+
+// TaskHistoryActor identifies who performs a task action: AGENT_TEAM_ACTOR
+// when set (workers and automation), otherwise the OS user.
+func TaskHistoryActor() string {
+	for _, key := range []string{"AGENT_TEAM_ACTOR", "USER", "USERNAME"} {
+		if actor := strings.TrimSpace(os.Getenv(key)); actor != "" {
+			return actor
+		}
+	}
+	return "unknown"
+}
+
+// AppendTaskHistory appends entry to the task's history.jsonl, filling in
+// the time and actor when empty.
+func AppendTaskHistory(root, taskID string, entry TaskHistoryEntry, now time.Time) error {
+	_, location, err := LoadTaskRecord(root, taskID)
+	if err != nil {
+		return err
+	}
+	if entry.Time == "" {
+		entry.Time = now.UTC().Format(time.RFC3339)
+	}
+	if entry.Actor == "" {
+		entry.Actor = TaskHistoryActor()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal task history: %w", err)
+	}
+	data = append(data, '\n')
+
+	path := TaskHistoryPath(root, taskID, location)
+	unlock, err := LockState(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("open history.jsonl: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("append task history: %w", err)
+	}
+	return nil
+}
+
+// AddTaskComment records a free-form comment on the task timeline.
+func AddTaskComment(root, taskID, text string, now time.Time) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("comment text is required")
+	}
+	return AppendTaskHistory(root, taskID, TaskHistoryEntry{Action: TaskHistoryComment, Text: text}, now)
+}
+
+// ReadTaskHistory returns the task's history in file order. Tasks created
+// before history.jsonl existed have an empty history.
+func ReadTaskHistory(root, taskID string) ([]TaskHistoryEntry, error) {
+	_, location, err := LoadTaskRecord(root, taskID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(TaskHistoryPath(root, taskID, location))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open history.jsonl: %w", err)
+	}
+	defer f.Close()
+
+	var entries []TaskHistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry TaskHistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("parse history.jsonl line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history.jsonl: %w", err)
+	}
+	return entries, nil
+}
+
+// SummarizeTaskHistory counts entries by action and lists the workers that
+// held the task, in order of first assignment.
+func SummarizeTaskHistory(entries []TaskHistoryEntry) TaskHistorySummary {
+	summary := TaskHistorySummary{Entries: len(entries)}
+	for i := range entries {
+		entry := &entries[i]
+		switch entry.Action {
+		case TaskHistoryTransition:
+			summary.Transitions++
+		case TaskHistoryComment:
+			summary.Comments++
+		}
+		if entry.WorkerID != "" {
+			summary.Workers = appendUnique(summary.Workers, entry.WorkerID)
+		}
+		summary.Last = entry
+	}
+	return summary
+}
+
+// recordTaskTransition appends a transition entry after the state change has
+// been saved. Like event logging, a failed append only produces a warning.
+func recordTaskTransition(root string, record *TaskRecord, from TaskStatus, reason string, data map[string]string, now time.Time) {
+	for key, value := range data {
+		if value == "" {
+			delete(data, key)
+		}
+	}
+	if len(data) == 0 {
+		data = nil
+	}
+	entry := TaskHistoryEntry{
+		Action:   TaskHistoryTransition,
+		From:     from,
+		To:       record.Status,
+		WorkerID: record.WorkerID,
+		Reason:   strings.TrimSpace(reason),
+		Data:     data,
+	}
+	if err := AppendTaskHistory(root, record.TaskID, entry, now); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: record history for task '%s': %v\n", record.TaskID, err)
+	}
+}
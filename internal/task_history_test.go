@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTaskHistoryRecordsTransitions(t *testing.T) {
+	t.Setenv("AGENT_TEAM_ACTOR", "controller")
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	record, err := CreateTaskPackage(root, "History task", "backend", "", now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	if _, err := BindTaskToWorker(root, record.TaskID, "backend-001", "", now.Add(time.Minute)); err != nil {
+		t.Fatalf("BindTaskToWorker: %v", err)
+	}
+	if _, err := MarkTaskDone(root, record.TaskID, "ready for review", now.Add(2*time.Minute)); err != nil {
+		t.Fatalf("MarkTaskDone: %v", err)
+	}
+	if _, err := BindTaskToWorker(root, record.TaskID, "backend-002", "review found a race", now.Add(3*time.Minute)); err != nil {
+		t.Fatalf("BindTaskToWorker reopen: %v", err)
+	}
+	t.Setenv("AGENT_TEAM_ACTOR", "backend-002")
+	if err := AddTaskComment(root, record.TaskID, "  fixed by locking the cache  ", now.Add(4*time.Minute)); err != nil {
+		t.Fatalf("AddTaskComment: %v", err)
+	}
+	if err := AddTaskComment(root, record.TaskID, " ", now); err == nil {
+		t.Fatal("expected error for empty comment")
+	}
+
+	entries, err := ReadTaskHistory(root, record.TaskID)
+	if err != nil {
+		t.Fatalf("ReadTaskHistory: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if entries[0].Action != TaskHistoryCreated || entries[0].Actor != "controller" || entries[0].Time != "2026-03-21T10:00:00Z" {
+		t.Fatalf("created entry = %+v", entries[0])
+	}
+	if entries[1].From != TaskStatusDraft || entries[1].To != TaskStatusAssigned || entries[1].WorkerID != "backend-001" || entries[1].Data != nil {
+		t.Fatalf("assign entry = %+v", entries[1])
+	}
+	if entries[2].To != TaskStatusVerifying || entries[2].Reason != "ready for review" {
+		t.Fatalf("done entry = %+v", entries[2])
+	}
+	reopen := entries[3]
+	if reopen.From != TaskStatusVerifying || reopen.WorkerID != "backend-002" || reopen.Reason != "review found a race" {
+		t.Fatalf("reopen entry = %+v", reopen)
+	}
+	if reopen.Data["previous_worker_id"] != "backend-001" || reopen.Data["cleared_verifying_at"] != "2026-03-21T10:02:00Z" {
+		t.Fatalf("reopen data = %v", reopen.Data)
+	}
+	if entries[4].Action != TaskHistoryComment || entries[4].Text != "fixed by locking the cache" || entries[4].Actor != "backend-002" {
+		t.Fatalf("comment entry = %+v", entries[4])
+	}
+
+	summary := SummarizeTaskHistory(entries)
+	if summary.Entries != 5 || summary.Transitions != 3 || summary.Comments != 1 || summary.Last.Action != TaskHistoryComment {
+		t.Fatalf("summary = %+v", summary)
+	}
+	if len(summary.Workers) != 2 || summary.Workers[0] != "backend-001" || summary.Workers[1] != "backend-002" {
+		t.Fatalf("summary workers = %v", summary.Workers)
+	}
+}
+
+func TestTaskHistoryMovesWithArchivedPackage(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	record, err := CreateTaskPackage(root, "Archive history", "backend", "", now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	if _, err := BindTaskToWorker(root, record.TaskID, "backend-001", "", now); err != nil {
+		t.Fatalf("BindTaskToWorker: %v", err)
+	}
+	if _, err := MarkTaskDone(root, record.TaskID, "", now); err != nil {
+		t.Fatalf("MarkTaskDone: %v", err)
+	}
+	if err := os.WriteFile(TaskVerificationPath(root, record.TaskID), []byte("# Verification\n\n## Result\n- pass\n"), 0644); err != nil {
+		t.Fatalf("write verification: %v", err)
+	}
+	if _, err := ArchiveTask(root, record.TaskID, "deadbeef", "", false, now); err != nil {
+		t.Fatalf("ArchiveTask: %v", err)
+	}
+
+	entries, err := ReadTaskHistory(root, record.TaskID)
+	if err != nil {
+		t.Fatalf("ReadTaskHistory: %v", err)
+	}
+	last := entries[len(entries)-1]
+	if len(entries) != 4 || last.To != TaskStatusArchived || last.Data["merged_sha"] != "deadbeef" || last.Data["verification"] != "pass" {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if _, err := os.Stat(TaskHistoryPath(root, record.TaskID, TaskRecordLocationArchived)); err != nil {
+		t.Fatalf("archived history.jsonl: %v", err)
+	}
+}
@@ -0,0 +1,339 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TaskImportItem is one task entry in an import file. Key is the stable
+// external key used to recognise tasks on re-import; it defaults to the
+// slugified title.
+type TaskImportItem struct {
+	Key       string   `yaml:"key,omitempty"`
+	Title     string   `yaml:"title"`
+	Role      string   `yaml:"role,omitempty"`
+	Design    string   `yaml:"design,omitempty"`
+	Phase     string   `yaml:"phase,omitempty"`
+	Template  string   `yaml:"template,omitempty"`
+	DependsOn []string `yaml:"depends_on,omitempty"`
+}
+
+// TaskImportAction is what an import does with one item.
+type TaskImportAction string
+
+const (
+	TaskImportCreate TaskImportAction = "create"
+	TaskImportExists TaskImportAction = "exists"
+)
+
+// TaskImportResult reports the outcome for one item. TaskID is empty for
+// items that would be created by a dry run.
+type TaskImportResult struct {
+	Item      TaskImportItem
+	Action    TaskImportAction
+	TaskID    string
+	DependsOn []string
+}
+
+// TaskImportOptions controls ImportTasks. BaseDir resolves relative design
+// paths (the repository root is tried next); Role and Phase are defaults for
+// items that do not set their own.
+type TaskImportOptions struct {
+	BaseDir string
+	Role    string
+	Phase   string
+	DryRun  bool
+}
+
+var (
+	taskImportCheckboxRe = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.+)$`)
+	taskImportFieldRe    = regexp.MustCompile(`^\s+[-*+]\s+([A-Za-z_]+):\s*(.*)$`)
+)
+
+// ParseTaskImportMarkdown reads unchecked checklist items as tasks. Indented
+// "- field: value" bullets below an item set key, role, design, phase,
+// template and depends_on (comma separated). Checked items are skipped.
+func ParseTaskImportMarkdown(data []byte) ([]TaskImportItem, error) {
+	var items []TaskImportItem
+	var current *TaskImportItem
+	skipping := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if m := taskImportCheckboxRe.FindStringSubmatch(text); m != nil && m[1] == "" {
+			current, skipping = nil, m[2] != " "
+			if !skipping {
+				items = append(items, TaskImportItem{Title: strings.TrimSpace(m[3])})
+				current = &items[len(items)-1]
+			}
+			continue
+		}
+		if m := taskImportFieldRe.FindStringSubmatch(text); m != nil && (current != nil || skipping) {
+			if skipping {
+				continue
+			}
+			if err := setTaskImportField(current, strings.ToLower(m[1]), strings.TrimSpace(m[2])); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			continue
+		}
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			continue
+		}
+		current, skipping = nil, false
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read markdown: %w", err)
+	}
+	return items, nil
+}
+
+func setTaskImportField(item *TaskImportItem, field, value string) error {
+	switch field {
+	case "key":
+		item.Key = value
+	case "role":
+		item.Role = value
+	case "design":
+		item.Design = value
+	case "phase":
+		item.Phase = value
+	case "template":
+		item.Template = value
+	case "depends_on", "depends-on", "dependencies":
+		for _, dep := range strings.Split(value, ",") {
+			if dep = strings.TrimSpace(dep); dep != "" && !strings.EqualFold(dep, "none") {
+				item.DependsOn = append(item.DependsOn, dep)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown task field %q", field)
+	}
+	return nil
+}
+
+// ParseTaskImportYAML accepts either a list of items or a mapping with a
+// "tasks" list plus optional "role" and "phase" defaults.
+func ParseTaskImportYAML(data []byte) ([]TaskImportItem, error) {
+	var list []TaskImportItem
+	if err := yaml.Unmarshal(data, &list); err == nil {
+		return list, nil
+	}
+	var doc struct {
+		Role  string           `yaml:"role"`
+		Phase string           `yaml:"phase"`
+		Tasks []TaskImportItem `yaml:"tasks"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse task import yaml: %w", err)
+	}
+	for i := range doc.Tasks {
+		if doc.Tasks[i].Role == "" {
+			doc.Tasks[i].Role = doc.Role
+		}
+		if doc.Tasks[i].Phase == "" {
+			doc.Tasks[i].Phase = doc.Phase
+		}
+	}
+	return doc.Tasks, nil
+}
+
+// TaskImportKey returns the stable key for item: its key, or the slug of
+// the whole title.
+func TaskImportKey(item TaskImportItem) string {
+	if key := strings.TrimSpace(item.Key); key != "" {
+		return key
+	}
+	return Slugify(item.Title, math.MaxInt)
+}
+
+// ImportTasks creates a task package for every item whose key is neither an
+// existing task's import key nor an existing task ID, links new tasks to their phase, and resolves
+// depends_on entries (keys from the same import, existing import keys, or
+// task IDs) to task IDs. Every item is validated before anything is written.
+func ImportTasks(root string, items []TaskImportItem, opts TaskImportOptions, now time.Time) ([]TaskImportResult, error) {
+	existing, err := ListTasks(root, false)
+	if err != nil {
+		return nil, err
+	}
+	byKey := map[string]string{}
+	taskIDs := map[string]bool{}
+	for _, task := range existing {
+		taskIDs[task.TaskID] = true
+		if task.ImportKey != "" {
+			byKey[task.ImportKey] = task.TaskID
+		}
+	}
+
+	results := make([]TaskImportResult, len(items))
+	batchKeys := map[string]int{}
+	titles := map[string]bool{}
+	for i := range items {
+		item := items[i]
+		item.Title = strings.TrimSpace(item.Title)
+		if item.Title == "" {
+			return nil, fmt.Errorf("task %d: title is required", i+1)
+		}
+		if item.Role == "" {
+			item.Role = opts.Role
+		}
+		if item.Phase == "" {
+			item.Phase = opts.Phase
+		}
+		item.Key = TaskImportKey(item)
+		if prev, ok := batchKeys[item.Key]; ok {
+			return nil, fmt.Errorf("task %d: key '%s' already used by task %d", i+1, item.Key, prev+1)
+		}
+		batchKeys[item.Key] = i
+		results[i] = TaskImportResult{Item: item, Action: TaskImportCreate, TaskID: byKey[item.Key]}
+		if results[i].TaskID == "" && taskIDs[item.Key] {
+			// Exported task lists use the task ID as key when there is no import key.
+			results[i].TaskID = item.Key
+		}
+		if results[i].TaskID != "" {
+			results[i].Action = TaskImportExists
+			continue
+		}
+		if titles[item.Title] {
+			return nil, fmt.Errorf("task %d: duplicate title %q", i+1, item.Title)
+		}
+		titles[item.Title] = true
+		if item.Role == "" {
+			return nil, fmt.Errorf("task '%s': role is required (set it per task or pass --role)", item.Key)
+		}
+		if _, err := ResolveRole(root, item.Role); err != nil {
+			return nil, fmt.Errorf("task '%s': %w", item.Key, err)
+		}
+		if item.Phase != "" {
+			phase, err := LoadPlanningRecord(root, item.Phase)
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", item.Key, err)
+			}
+			if phase.Kind != PlanningKindPhase {
+				return nil, fmt.Errorf("task '%s': '%s' is a %s, not a phase", item.Key, item.Phase, phase.Kind)
+			}
+		}
+		if item.Design != "" {
+			if _, err := resolveTaskImportDesign(root, opts.BaseDir, item.Design); err != nil {
+				return nil, fmt.Errorf("task '%s': %w", item.Key, err)
+			}
+		}
+		if item.Template != "" {
+			if _, err := ResolveTaskTemplate(root, item.Role, item.Template); err != nil {
+				return nil, fmt.Errorf("task '%s': %w", item.Key, err)
+			}
+		}
+	}
+	for i := range results {
+		for _, dep := range results[i].Item.DependsOn {
+			if _, ok := batchKeys[dep]; !ok && byKey[dep] == "" && !taskIDs[dep] {
+				return nil, fmt.Errorf("task '%s': unknown dependency '%s'", results[i].Item.Key, dep)
+			}
+		}
+	}
+	if opts.DryRun {
+		for i := range results {
+			results[i].DependsOn = resolveTaskImportDeps(results, batchKeys, byKey, results[i].Item.DependsOn)
+		}
+		return results, nil
+	}
+
+	// Create in file order; dependencies on later items are filled in once
+	// every task has an ID.
+	for i := range results {
+		if results[i].Action != TaskImportCreate {
+			continue
+		}
+		item := results[i].Item
+		design, designPath := "", ""
+		if item.Design != "" {
+			designPath, _ = resolveTaskImportDesign(root, opts.BaseDir, item.Design)
+			data, err := os.ReadFile(designPath)
+			if err != nil {
+				return results[:i], fmt.Errorf("task '%s': read design: %w", item.Key, err)
+			}
+			design = string(data)
+		}
+		record, err := CreateTaskPackageWithOptions(root, TaskCreateOptions{
+			Title:      item.Title,
+			Role:       item.Role,
+			Design:     design,
+			DesignPath: item.Design,
+			Phase:      item.Phase,
+			Template:   item.Template,
+			ImportKey:  item.Key,
+		}, now)
+		if err != nil {
+			return results[:i], fmt.Errorf("task '%s': %w", item.Key, err)
+		}
+		results[i].TaskID = record.TaskID
+		if item.Phase != "" {
+			if _, err := LinkPlanning(root, item.Phase, record.TaskID, now); err != nil {
+				return results[:i+1], fmt.Errorf("task '%s': link phase: %w", item.Key, err)
+			}
+		}
+	}
+	for i := range results {
+		results[i].DependsOn = resolveTaskImportDeps(results, batchKeys, byKey, results[i].Item.DependsOn)
+		if results[i].Action != TaskImportCreate || len(results[i].DependsOn) == 0 {
+			continue
+		}
+		record, _, err := LoadTaskRecord(root, results[i].TaskID)
+		if err != nil {
+			return results, err
+		}
+		for _, dep := range results[i].DependsOn {
+			record.DependsOn = appendUnique(record.DependsOn, dep)
+		}
+		if err := SaveTaskRecord(root, record); err != nil {
+			return results, fmt.Errorf("task '%s': save dependencies: %w", results[i].Item.Key, err)
+		}
+	}
+	return results, nil
+}
+
+// resolveTaskImportDeps maps dependency keys to task IDs. Dependencies on
+// tasks a dry run has not created yet are reported by key.
+func resolveTaskImportDeps(results []TaskImportResult, batchKeys map[string]int, byKey map[string]string, deps []string) []string {
+	var ids []string
+	for _, dep := range deps {
+		id := dep
+		if idx, ok := batchKeys[dep]; ok && results[idx].TaskID != "" {
+			id = results[idx].TaskID
+		} else if existing := byKey[dep]; existing != "" {
+			id = existing
+		}
+		ids = appendUnique(ids, id)
+	}
+	return ids
+}
+
+func resolveTaskImportDesign(root, baseDir, path string) (string, error) {
+	if filepath.IsAbs(path) {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("design %s: %w", path, err)
+		}
+		return path, nil
+	}
+	for _, dir := range []string{baseDir, root} {
+		if dir == "" {
+			continue
+		}
+		candidate := filepath.Join(dir, path)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("design file not found: %s", path)
+}
@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseTaskImportMarkdown(t *testing.T) {
+	data := []byte(`# Login plan
+
+- [ ] Build login form
+  - role: frontend
+  - key: login-form
+  - depends_on: login-api
+- [x] Already done
+  - role: backend
+- [ ] Login API
+  - design: docs/login.md
+
+Notes below are ignored.
+`)
+	items, err := ParseTaskImportMarkdown(data)
+	if err != nil {
+		t.Fatalf("ParseTaskImportMarkdown: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("items = %+v", items)
+	}
+	if items[0].Title != "Build login form" || items[0].Role != "frontend" || items[0].Key != "login-form" || !slices.Equal(items[0].DependsOn, []string{"login-api"}) {
+		t.Fatalf("items[0] = %+v", items[0])
+	}
+	if items[1].Title != "Login API" || items[1].Design != "docs/login.md" || TaskImportKey(items[1]) != "login-api" {
+		t.Fatalf("items[1] = %+v", items[1])
+	}
+	if _, err := ParseTaskImportMarkdown([]byte("- [ ] Task\n  - owner: me\n")); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestParseTaskImportYAMLDefaults(t *testing.T) {
+	items, err := ParseTaskImportYAML([]byte("role: backend\nphase: p1\ntasks:\n  - title: A\n  - title: B\n    role: qa\n"))
+	if err != nil {
+		t.Fatalf("ParseTaskImportYAML: %v", err)
+	}
+	if len(items) != 2 || items[0].Role != "backend" || items[1].Role != "qa" || items[1].Phase != "p1" {
+		t.Fatalf("items = %+v", items)
+	}
+	items, err = ParseTaskImportYAML([]byte("- title: A\n  depends_on: [b]\n"))
+	if err != nil || len(items) != 1 || items[0].DependsOn[0] != "b" {
+		t.Fatalf("list form = %+v, %v", items, err)
+	}
+}
+
+func TestImportTasksIsIdempotentAndLinksPhase(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	if err := os.MkdirAll(RoleDir(root, "backend"), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(RoleDir(root, "backend"), "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	phase, err := CreatePlanningRecord(root, PlanningKindPhase, "Phase", now)
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord: %v", err)
+	}
+	items := []TaskImportItem{
+		{Title: "Second", DependsOn: []string{"first"}},
+		{Title: "First"},
+	}
+	opts := TaskImportOptions{Role: "backend", Phase: phase.ID}
+
+	preview, err := ImportTasks(root, items, TaskImportOptions{Role: "backend", Phase: phase.ID, DryRun: true}, now)
+	if err != nil {
+		t.Fatalf("ImportTasks dry-run: %v", err)
+	}
+	if len(preview) != 2 || preview[0].Action != TaskImportCreate || preview[0].TaskID != "" {
+		t.Fatalf("preview = %+v", preview)
+	}
+	if tasks, _ := ListTasks(root, false); len(tasks) != 0 {
+		t.Fatalf("dry run created %d tasks", len(tasks))
+	}
+
+	results, err := ImportTasks(root, items, opts, now)
+	if err != nil {
+		t.Fatalf("ImportTasks: %v", err)
+	}
+	second, _, err := LoadTaskRecord(root, results[0].TaskID)
+	if err != nil {
+		t.Fatalf("LoadTaskRecord: %v", err)
+	}
+	if second.ImportKey != "second" || !slices.Equal(second.DependsOn, []string{results[1].TaskID}) || !slices.Equal(second.ParentIDs, []string{phase.ID}) {
+		t.Fatalf("second = %+v", second)
+	}
+	loaded, _ := LoadPlanningRecord(root, phase.ID)
+	if len(loaded.TaskIDs) != 2 {
+		t.Fatalf("phase TaskIDs = %v", loaded.TaskIDs)
+	}
+
+	again, err := ImportTasks(root, append(items, TaskImportItem{Title: "Third"}), opts, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("ImportTasks again: %v", err)
+	}
+	if again[0].Action != TaskImportExists || again[1].Action != TaskImportExists || again[2].Action != TaskImportCreate {
+		t.Fatalf("re-import = %+v", again)
+	}
+	if tasks, _ := ListTasks(root, false); len(tasks) != 3 {
+		t.Fatalf("tasks after re-import = %d, want 3", len(tasks))
+	}
+}
+
+func TestImportTasksValidatesBeforeWriting(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(RoleDir(root, "backend"), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(RoleDir(root, "backend"), "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	items := []TaskImportItem{{Title: "Good", Role: "backend"}, {Title: "Bad", Role: "backend", DependsOn: []string{"nowhere"}}}
+	if _, err := ImportTasks(root, items, TaskImportOptions{}, time.Now().UTC()); err == nil {
+		t.Fatal("expected unknown dependency error")
+	}
+	if tasks, _ := ListTasks(root, false); len(tasks) != 0 {
+		t.Fatalf("failed validation still created %d tasks", len(tasks))
+	}
+}
+
+func TestImportTasksKeepsCollidingTitlesApart(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	if err := os.MkdirAll(RoleDir(root, "backend"), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(RoleDir(root, "backend"), "SKILL.md"), []byte("# backend\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	prefix := "Migrate the billing service to the new ledger schema"
+	items := []TaskImportItem{
+		{Key: "a", Title: "Auth API"},
+		{Key: "b", Title: "auth api!"},
+		{Title: prefix + " (part one)"},
+		{Title: prefix + " (part two)"},
+	}
+	results, err := ImportTasks(root, items, TaskImportOptions{Role: "backend"}, now)
+	if err != nil {
+		t.Fatalf("ImportTasks: %v", err)
+	}
+	seen := map[string]bool{}
+	for i, result := range results {
+		if result.Action != TaskImportCreate || seen[result.TaskID] {
+			t.Fatalf("results[%d] = %+v", i, result)
+		}
+		seen[result.TaskID] = true
+		record, _, err := LoadTaskRecord(root, result.TaskID)
+		if err != nil {
+			t.Fatalf("LoadTaskRecord(%s): %v", result.TaskID, err)
+		}
+		if record.ImportKey != result.Item.Key || record.Title != items[i].Title {
+			t.Fatalf("task %s = %+v, want key %s", result.TaskID, record, result.Item.Key)
+		}
+	}
+
+	again, err := ImportTasks(root, items, TaskImportOptions{Role: "backend"}, now)
+	if err != nil {
+		t.Fatalf("re-import: %v", err)
+	}
+	for i, result := range again {
+		if result.Action != TaskImportExists || result.TaskID != results[i].TaskID {
+			t.Fatalf("re-import results[%d] = %+v", i, result)
+		}
+	}
+}
@@ -7,8 +7,6 @@ import (
 	"sort"
 	"strings"
 	"time"
-
-	"gopkg.in/yaml.v3"
 )
 
 var saveTaskRecordAt = saveTaskRecordAtImpl
@@ -93,31 +91,112 @@ func GenerateTaskID(title string, now time.Time) string {
 	return fmt.Sprintf("%s-%s", now.UTC().Format("2006-01-02-15-04-05"), Slugify(title, 48))
 }
 
+// uniqueTaskID returns GenerateTaskID(title, now), adding a -2, -3, ...
+// suffix while an active, archived or deprecated task already uses the ID,
+// so tasks with the same title created in the same second stay separate.
+func uniqueTaskID(root, title string, now time.Time) string {
+	base := GenerateTaskID(title, now)
+	taskID := base
+	for n := 2; fileExists(TaskDir(root, taskID)) || fileExists(TaskArchiveDir(root, taskID)) || fileExists(TaskDeprecatedDir(root, taskID)); n++ {
+		taskID = fmt.Sprintf("%s-%d", base, n)
+	}
+	return taskID
+}
+
 func CreateTaskPackage(root, title, role, design string, now time.Time) (*TaskRecord, error) {
-	taskID := GenerateTaskID(title, now)
+	return CreateTaskPackageWithOptions(root, TaskCreateOptions{Title: title, Role: role, Design: design}, now)
+}
+
+// CreateTaskPackageWithOptions creates a task package rendered from the task
+// template selected by opts (see ResolveTaskTemplate).
+func CreateTaskPackageWithOptions(root string, opts TaskCreateOptions, now time.Time) (*TaskRecord, error) {
+	tmpl, err := ResolveTaskTemplate(root, opts.Role, opts.Template)
+	if err != nil {
+		return nil, err
+	}
+	taskID := uniqueTaskID(root, opts.Title, now)
 	record := &TaskRecord{
 		TaskID:    taskID,
-		Title:     title,
-		Role:      role,
+		Title:     opts.Title,
+		Role:      opts.Role,
 		Status:    TaskStatusDraft,
 		TaskPath:  TaskRelPath(taskID),
 		CreatedAt: now.UTC().Format(time.RFC3339),
 	}
+	files, err := tmpl.Render(TaskTemplateData{
+		TaskID:     record.TaskID,
+		Title:      record.Title,
+		Role:       record.Role,
+		Design:     strings.TrimSpace(opts.Design),
+		DesignPath: opts.DesignPath,
+		Phase:      opts.Phase,
+		CreatedAt:  record.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var fields taskTemplateYAML
+	if overlay, ok := files["task.yaml"]; ok {
+		if fields, err = parseTaskYAMLTemplate(overlay); err != nil {
+			return nil, err
+		}
+	}
+	for _, parentID := range fields.ParentIDs {
+		if _, err := LoadPlanningRecord(root, parentID); err != nil {
+			return nil, fmt.Errorf("task template parent: %w", err)
+		}
+	}
+	record.Requirement = fields.Requirement
+	record.SubTaskID = fields.SubTaskID
+	for _, dep := range append(fields.DependsOn, opts.DependsOn...) {
+		record.DependsOn = appendUnique(record.DependsOn, dep)
+	}
+	record.ImportKey = opts.ImportKey
+	for _, refs := range []map[string]string{fields.ExternalRefs, opts.ExternalRefs} {
+		for system, ref := range refs {
+			if record.ExternalRefs == nil {
+				record.ExternalRefs = map[string]string{}
+			}
+			record.ExternalRefs[system] = ref
+		}
+	}
 
 	taskDir := TaskDir(root, taskID)
-	if err := os.MkdirAll(taskDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(taskDir), 0755); err != nil {
+		return nil, fmt.Errorf("create task directory: %w", err)
+	}
+	// Mkdir rather than MkdirAll: a task created concurrently under the same
+	// ID must not be overwritten.
+	if err := os.Mkdir(taskDir, 0755); err != nil {
+		if os.IsExist(err) {
+			return nil, fmt.Errorf("task %s already exists", taskID)
+		}
 		return nil, fmt.Errorf("create task directory: %w", err)
 	}
 	if err := saveTaskRecordAt(taskDir, record); err != nil {
 		return nil, err
 	}
-	if err := os.WriteFile(TaskContextPath(root, taskID), []byte(defaultTaskContext(record, design)), 0644); err != nil {
+	if err := WriteFileAtomic(TaskContextPath(root, taskID), []byte(files["context.md"]), 0644); err != nil {
 		return nil, fmt.Errorf("write context.md: %w", err)
 	}
-	if err := os.WriteFile(TaskVerificationPath(root, taskID), []byte(defaultTaskVerification()), 0644); err != nil {
+	if err := WriteFileAtomic(TaskVerificationPath(root, taskID), []byte(files["verification.md"]), 0644); err != nil {
 		return nil, fmt.Errorf("write verification.md: %w", err)
 	}
-	return record, nil
+	if err := AppendTaskHistory(root, taskID, TaskHistoryEntry{Action: TaskHistoryCreated, To: record.Status}, now); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: record history for task '%s': %v\n", taskID, err)
+	}
+	EmitEvent(root, Event{Type: EventTaskCreated, TaskID: record.TaskID, Role: record.Role, To: string(record.Status), Message: record.Title})
+	if len(fields.ParentIDs) == 0 {
+		return record, nil
+	}
+	// Template parents are linked like --phase, so each parent lists the task.
+	for _, parentID := range fields.ParentIDs {
+		if _, err := LinkPlanning(root, parentID, taskID, now); err != nil {
+			return nil, fmt.Errorf("link task to template parent: %w", err)
+		}
+	}
+	record, _, err = LoadTaskRecord(root, taskID)
+	return record, err
 }
 
 func LoadTaskRecord(root, taskID string) (*TaskRecord, TaskRecordLocation, error) {
@@ -138,7 +217,6 @@ func SaveTaskRecord(root string, record *TaskRecord) error {
 	if record == nil {
 		return fmt.Errorf("task record is nil")
 	}
-	record.LegacyDoneAt = ""
 	var dir string
 	switch record.Status {
 	case TaskStatusArchived:
@@ -188,7 +266,7 @@ func ListTasks(root string, activeOnly bool) ([]*TaskRecord, error) {
 	return tasks, nil
 }
 
-func BindTaskToWorker(root, taskID, workerID string, now time.Time) (*TaskRecord, error) {
+func BindTaskToWorker(root, taskID, workerID, reason string, now time.Time) (*TaskRecord, error) {
 	record, location, err := LoadTaskRecord(root, taskID)
 	if err != nil {
 		return nil, err
@@ -208,6 +286,21 @@ func BindTaskToWorker(root, taskID, workerID string, now time.Time) (*TaskRecord
 		return nil, fmt.Errorf("task '%s' cannot be assigned from status '%s'", taskID, record.Status)
 	}
 
+	from := record.Status
+	payload := taskHookPayload(HookEventTaskAssign, record, from, TaskStatusAssigned)
+	payload.WorkerID = workerID
+	if err := RunPreHooks(root, payload); err != nil {
+		return nil, err
+	}
+
+	previous := map[string]string{
+		"previous_worker_id":   record.WorkerID,
+		"previous_assigned_at": record.AssignedAt,
+		"cleared_verifying_at": record.VerifyingAt,
+	}
+	if record.WorkerID == workerID {
+		delete(previous, "previous_worker_id")
+	}
 	record.Status = TaskStatusAssigned
 	record.WorkerID = workerID
 	record.TaskPath = TaskRelPath(taskID)
@@ -219,10 +312,13 @@ func BindTaskToWorker(root, taskID, workerID string, now time.Time) (*TaskRecord
 	if err := SaveTaskRecord(root, record); err != nil {
 		return nil, err
 	}
+	recordTaskTransition(root, record, from, reason, previous, now)
+	RunPostHooks(root, taskHookPayload(HookEventTaskAssign, record, from, record.Status))
+	emitTaskTransition(root, record, from)
 	return record, nil
 }
 
-func MarkTaskDone(root, taskID string, now time.Time) (*TaskRecord, error) {
+func MarkTaskDone(root, taskID, reason string, now time.Time) (*TaskRecord, error) {
 	record, location, err := LoadTaskRecord(root, taskID)
 	if err != nil {
 		return nil, err
@@ -233,15 +329,22 @@ func MarkTaskDone(root, taskID string, now time.Time) (*TaskRecord, error) {
 	if err := ValidateTaskTransition(record.Status, TaskStatusVerifying); err != nil {
 		return nil, err
 	}
+	from := record.Status
+	if err := RunPreHooks(root, taskHookPayload(HookEventTaskDone, record, from, TaskStatusVerifying)); err != nil {
+		return nil, err
+	}
 	record.Status = TaskStatusVerifying
 	record.VerifyingAt = now.UTC().Format(time.RFC3339)
 	if err := SaveTaskRecord(root, record); err != nil {
 		return nil, err
 	}
+	recordTaskTransition(root, record, from, reason, nil, now)
+	RunPostHooks(root, taskHookPayload(HookEventTaskDone, record, from, record.Status))
+	emitTaskTransition(root, record, from)
 	return record, nil
 }
 
-func ArchiveTask(root, taskID, mergedSHA string, strict bool, now time.Time) (*TaskRecord, error) {
+func ArchiveTask(root, taskID, mergedSHA, reason string, strict bool, now time.Time) (*TaskRecord, error) {
 	if strings.TrimSpace(mergedSHA) == "" {
 		return nil, fmt.Errorf("merged SHA is required")
 	}
@@ -265,16 +368,28 @@ func ArchiveTask(root, taskID, mergedSHA string, strict bool, now time.Time) (*T
 	if err := ValidateArchiveReadiness(result, strict); err != nil {
 		return nil, err
 	}
+	from := record.Status
+	payload := taskHookPayload(HookEventTaskArchive, record, from, TaskStatusArchived)
+	payload.MergedSHA = mergedSHA
+	if err := RunPreHooks(root, payload); err != nil {
+		return nil, err
+	}
 
 	record.Status = TaskStatusArchived
 	record.TaskPath = TaskArchiveRelPath(taskID)
 	record.ArchivedAt = now.UTC().Format(time.RFC3339)
 	record.DeprecatedAt = ""
 	record.MergedSHA = mergedSHA
-	return moveTaskPackage(root, record, TaskRecordLocationActive, TaskRecordLocationArchived)
+	if _, err := moveTaskPackage(root, record, TaskRecordLocationActive, TaskRecordLocationArchived); err != nil {
+		return nil, err
+	}
+	recordTaskTransition(root, record, from, reason, map[string]string{"merged_sha": mergedSHA, "verification": string(result)}, now)
+	RunPostHooks(root, taskHookPayload(HookEventTaskArchive, record, from, record.Status))
+	emitTaskTransition(root, record, from)
+	return record, nil
 }
 
-func DeprecateTask(root, taskID string, now time.Time) (*TaskRecord, error) {
+func DeprecateTask(root, taskID, reason string, now time.Time) (*TaskRecord, error) {
 	record, location, err := LoadTaskRecord(root, taskID)
 	if err != nil {
 		return nil, err
@@ -288,13 +403,34 @@ func DeprecateTask(root, taskID string, now time.Time) (*TaskRecord, error) {
 	if err := ValidateTaskTransition(record.Status, TaskStatusDeprecated); err != nil {
 		return nil, err
 	}
+	from := record.Status
+	if err := RunPreHooks(root, taskHookPayload(HookEventTaskDeprecate, record, from, TaskStatusDeprecated)); err != nil {
+		return nil, err
+	}
 
 	record.Status = TaskStatusDeprecated
 	record.TaskPath = TaskDeprecatedRelPath(taskID)
 	record.DeprecatedAt = now.UTC().Format(time.RFC3339)
 	record.ArchivedAt = ""
 	record.MergedSHA = ""
-	return moveTaskPackage(root, record, TaskRecordLocationActive, TaskRecordLocationDeprecated)
+	if _, err := moveTaskPackage(root, record, TaskRecordLocationActive, TaskRecordLocationDeprecated); err != nil {
+		return nil, err
+	}
+	recordTaskTransition(root, record, from, reason, nil, now)
+	RunPostHooks(root, taskHookPayload(HookEventTaskDeprecate, record, from, record.Status))
+	emitTaskTransition(root, record, from)
+	return record, nil
+}
+
+func emitTaskTransition(root string, record *TaskRecord, from TaskStatus) {
+	EmitEvent(root, Event{
+		Type:     EventTaskTransition,
+		TaskID:   record.TaskID,
+		WorkerID: record.WorkerID,
+		Role:     record.Role,
+		From:     string(from),
+		To:       string(record.Status),
+	})
 }
 
 func moveTaskPackage(root string, record *TaskRecord, from, to TaskRecordLocation) (*TaskRecord, error) {
@@ -337,15 +473,9 @@ func loadTaskRecordFromDir(dir string) (*TaskRecord, error) {
 		return nil, fmt.Errorf("read task.yaml: %w", err)
 	}
 	var record TaskRecord
-	if err := yaml.Unmarshal(data, &record); err != nil {
+	if err := decodeMigratedYAML(SchemaKindTask, data, &record); err != nil {
 		return nil, fmt.Errorf("parse task.yaml: %w", err)
 	}
-	if record.Status == TaskStatus("done") {
-		record.Status = TaskStatusVerifying
-		if record.VerifyingAt == "" {
-			record.VerifyingAt = record.LegacyDoneAt
-		}
-	}
 	if !ValidTaskStatus(record.Status) {
 		return nil, fmt.Errorf("invalid task status: %s", record.Status)
 	}
@@ -356,26 +486,8 @@ func saveTaskRecordAtImpl(dir string, record *TaskRecord) error {
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("create task directory: %w", err)
 	}
-	record.LegacyDoneAt = ""
-	data, err := yaml.Marshal(record)
-	if err != nil {
-		return fmt.Errorf("marshal task record: %w", err)
-	}
-	if err := os.WriteFile(filepath.Join(dir, "task.yaml"), data, 0644); err != nil {
-		return fmt.Errorf("write task.yaml: %w", err)
-	}
-	return nil
-}
-
-func defaultTaskContext(record *TaskRecord, design string) string {
-	if strings.TrimSpace(design) == "" {
-		design = "- None provided yet."
-	}
-	return fmt.Sprintf("# Task Context\n\n- Task ID: `%s`\n- Title: %s\n- Role: `%s`\n\n## Background\n\n- TODO\n\n## Scope\n\n- TODO\n\n## Acceptance\n\n- TODO\n\n## Constraints\n\n- Follow the approved workflow and repository rules.\n- Keep the task scoped to this assignment.\n\n## Design\n\n%s\n", record.TaskID, record.Title, record.Role, strings.TrimSpace(design))
-}
-
-func defaultTaskVerification() string {
-	return "# Verification\n\n## Acceptance Criteria\n- TODO\n\n## Test Scope\n- Unit Test Coverage Required: yes\n- E2E Required: no\n\n## Checks Performed\n- Not run yet.\n\n## Result\n- pending\n\n## Issues\n- None.\n\n## Verified By\n- qa\n\n## Verified At\n- TODO\n"
+	record.SchemaVersion = CurrentSchemaVersion(SchemaKindTask)
+	return saveYAMLState(filepath.Join(dir, "task.yaml"), &record.Revision, record)
 }
 
 // Legacy .tasks helpers are kept for read-only compatibility during migration.
@@ -45,17 +45,17 @@ func TestListTasksActiveOnly(t *testing.T) {
 	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
 	active, _ := CreateTaskPackage(root, "Active Task", "backend", "", now)
 	archived, _ := CreateTaskPackage(root, "Archived Task", "backend", "", now.Add(time.Minute))
-	if _, err := BindTaskToWorker(root, archived.TaskID, "backend-001", now.Add(2*time.Minute)); err != nil {
+	if _, err := BindTaskToWorker(root, archived.TaskID, "backend-001", "", now.Add(2*time.Minute)); err != nil {
 		t.Fatalf("BindTaskToWorker archived: %v", err)
 	}
-	if _, err := MarkTaskDone(root, archived.TaskID, now.Add(3*time.Minute)); err != nil {
+	if _, err := MarkTaskDone(root, archived.TaskID, "", now.Add(3*time.Minute)); err != nil {
 		t.Fatalf("MarkTaskDone archived: %v", err)
 	}
 	verificationPath := TaskVerificationPath(root, archived.TaskID)
 	if err := os.WriteFile(verificationPath, []byte("# Verification\n\n## Result\n- pass\n"), 0644); err != nil {
 		t.Fatalf("WriteFile verification: %v", err)
 	}
-	if _, err := ArchiveTask(root, archived.TaskID, "abc123", false, now.Add(4*time.Minute)); err != nil {
+	if _, err := ArchiveTask(root, archived.TaskID, "abc123", "", false, now.Add(4*time.Minute)); err != nil {
 		t.Fatalf("ArchiveTask: %v", err)
 	}
 	list, err := ListTasks(root, true)
@@ -74,13 +74,13 @@ func TestBindTaskToWorkerAllowsReassignFromVerifying(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTaskPackage: %v", err)
 	}
-	if _, err := BindTaskToWorker(root, record.TaskID, "backend-001", now.Add(time.Minute)); err != nil {
+	if _, err := BindTaskToWorker(root, record.TaskID, "backend-001", "", now.Add(time.Minute)); err != nil {
 		t.Fatalf("BindTaskToWorker: %v", err)
 	}
-	if _, err := MarkTaskDone(root, record.TaskID, now.Add(2*time.Minute)); err != nil {
+	if _, err := MarkTaskDone(root, record.TaskID, "", now.Add(2*time.Minute)); err != nil {
 		t.Fatalf("MarkTaskDone: %v", err)
 	}
-	record, err = BindTaskToWorker(root, record.TaskID, "backend-002", now.Add(3*time.Minute))
+	record, err = BindTaskToWorker(root, record.TaskID, "backend-002", "", now.Add(3*time.Minute))
 	if err != nil {
 		t.Fatalf("BindTaskToWorker reassign: %v", err)
 	}
@@ -96,10 +96,10 @@ func TestArchiveTaskRollsBackOnWriteFailure(t *testing.T) {
 	if err != nil {
 		t.Fatalf("CreateTaskPackage: %v", err)
 	}
-	if _, err := BindTaskToWorker(root, record.TaskID, "backend-001", now.Add(time.Minute)); err != nil {
+	if _, err := BindTaskToWorker(root, record.TaskID, "backend-001", "", now.Add(time.Minute)); err != nil {
 		t.Fatalf("BindTaskToWorker: %v", err)
 	}
-	if _, err := MarkTaskDone(root, record.TaskID, now.Add(2*time.Minute)); err != nil {
+	if _, err := MarkTaskDone(root, record.TaskID, "", now.Add(2*time.Minute)); err != nil {
 		t.Fatalf("MarkTaskDone: %v", err)
 	}
 
@@ -116,7 +116,7 @@ func TestArchiveTaskRollsBackOnWriteFailure(t *testing.T) {
 	if err := os.WriteFile(verificationPath, []byte("# Verification\n\n## Result\n- pass\n"), 0644); err != nil {
 		t.Fatalf("WriteFile verification: %v", err)
 	}
-	_, err = ArchiveTask(root, record.TaskID, "deadbeef", false, now.Add(3*time.Minute))
+	_, err = ArchiveTask(root, record.TaskID, "deadbeef", "", false, now.Add(3*time.Minute))
 	if err == nil {
 		t.Fatal("expected archive failure")
 	}
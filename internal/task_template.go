@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed templates/task/*.tmpl
+var taskTemplateFS embed.FS
+
+// DefaultTaskTemplateName is the role package template used when a task is
+// created without --template.
+const DefaultTaskTemplateName = "default"
+
+// taskTemplateFiles are the files a task template may provide. Missing files
+// fall back to the built-in templates (task.yaml has no built-in).
+var taskTemplateFiles = []string{"context.md", "verification.md", "task.yaml"}
+
+// TaskCreateOptions describes a task package to create.
+type TaskCreateOptions struct {
+	Title        string
+	Role         string
+	Design       string // design document content
+	DesignPath   string // where Design was read from, if anywhere
+	Phase        string // phase the task will be linked to
+	Template     string // explicit template name; empty selects the role default
+	DependsOn    []string
+	ImportKey    string
+	ExternalRefs map[string]string
+}
+
+// TaskTemplateData is the data available to task templates.
+type TaskTemplateData struct {
+	TaskID     string
+	Title      string
+	Role       string
+	Design     string
+	DesignPath string
+	Phase      string
+	CreatedAt  string
+}
+
+// TaskTemplate is a resolved template directory. Dir is empty for the
+// built-in template.
+type TaskTemplate struct {
+	Name string
+	Dir  string
+}
+
+// ProjectTaskTemplatesDir returns .agent-team/templates/task.
+func ProjectTaskTemplatesDir(root string) string {
+	return filepath.Join(AgentTeamDir(root), "templates", "task")
+}
+
+// RoleTaskTemplatesDir returns references/task-templates inside a role package.
+func RoleTaskTemplatesDir(rolePath string) string {
+	return filepath.Join(rolePath, "references", "task-templates")
+}
+
+// ResolveTaskTemplate finds the template for a new task. A named template is
+// looked up in .agent-team/templates/task/<name>/ and then in the role
+// package's references/task-templates/<name>/; a missing named template is an
+// error. Without a name, .agent-team/templates/task/<role>/ and the role
+// package's task-templates/default/ are tried before the built-in template.
+func ResolveTaskTemplate(root, role, name string) (TaskTemplate, error) {
+	rolePath := ""
+	if match, err := ResolveRole(root, role); err == nil {
+		rolePath = match.Path
+	}
+	var candidates []string
+	if name != "" {
+		if err := validateTaskTemplateName(name); err != nil {
+			return TaskTemplate{}, err
+		}
+		candidates = append(candidates, filepath.Join(ProjectTaskTemplatesDir(root), name))
+		if rolePath != "" {
+			candidates = append(candidates, filepath.Join(RoleTaskTemplatesDir(rolePath), name))
+		}
+	} else {
+		if role != "" && validateTaskTemplateName(role) == nil {
+			candidates = append(candidates, filepath.Join(ProjectTaskTemplatesDir(root), role))
+		}
+		if rolePath != "" {
+			candidates = append(candidates, filepath.Join(RoleTaskTemplatesDir(rolePath), DefaultTaskTemplateName))
+		}
+	}
+	for _, dir := range candidates {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return TaskTemplate{Name: filepath.Base(dir), Dir: dir}, nil
+		}
+	}
+	if name != "" {
+		return TaskTemplate{}, fmt.Errorf("task template '%s' not found in %s or role '%s'", name, ProjectTaskTemplatesDir(root), role)
+	}
+	return TaskTemplate{}, nil
+}
+
+func validateTaskTemplateName(name string) error {
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid task template name: %s", name)
+	}
+	return nil
+}
+
+// Render renders every template file for data. Files the template does not
+// provide are rendered from the built-in templates, except task.yaml which is
+// omitted.
+func (t TaskTemplate) Render(data TaskTemplateData) (map[string]string, error) {
+	rendered := make(map[string]string, len(taskTemplateFiles))
+	for _, file := range taskTemplateFiles {
+		content, source, err := t.read(file)
+		if err != nil {
+			return nil, err
+		}
+		if content == "" {
+			continue
+		}
+		tmpl, err := template.New(file).Option("missingkey=error").Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("parse task template %s: %w", source, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("render task template %s: %w", source, err)
+		}
+		rendered[file] = strings.TrimRight(buf.String(), "\n") + "\n"
+	}
+	return rendered, nil
+}
+
+func (t TaskTemplate) read(file string) (content, source string, err error) {
+	if t.Dir != "" {
+		path := filepath.Join(t.Dir, file)
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return string(data), path, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", "", fmt.Errorf("read task template %s: %w", path, err)
+		}
+	}
+	builtin := "templates/task/" + file + ".tmpl"
+	data, err := taskTemplateFS.ReadFile(builtin)
+	if err != nil {
+		return "", "", nil
+	}
+	return string(data), builtin, nil
+}
+
+// taskTemplateYAML holds the task.yaml fields a template may set. Identity,
+// status, timestamps and revision stay under agent-team's control.
+type taskTemplateYAML struct {
+	Requirement  string            `yaml:"requirement"`
+	SubTaskID    int               `yaml:"sub_task_id"`
+	ParentIDs    []string          `yaml:"parent_ids"`
+	DependsOn    []string          `yaml:"depends_on"`
+	ExternalRefs map[string]string `yaml:"external_refs"`
+}
+
+var taskTemplateYAMLFields = []string{"requirement", "sub_task_id", "parent_ids", "depends_on", "external_refs"}
+
+// parseTaskYAMLTemplate decodes a rendered template task.yaml, rejecting keys
+// a template may not set so they are not silently dropped.
+func parseTaskYAMLTemplate(rendered string) (taskTemplateYAML, error) {
+	var fields taskTemplateYAML
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(rendered), &doc); err != nil {
+		return fields, fmt.Errorf("parse task template task.yaml: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return fields, nil
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return fields, fmt.Errorf("parse task template task.yaml: expected a mapping")
+	}
+	for i := 0; i < len(mapping.Content); i += 2 {
+		if key := mapping.Content[i].Value; !slices.Contains(taskTemplateYAMLFields, key) {
+			return fields, fmt.Errorf("task template task.yaml: field %q cannot be set by a template (allowed: %s)", key, strings.Join(taskTemplateYAMLFields, ", "))
+		}
+	}
+	if err := mapping.Decode(&fields); err != nil {
+		return fields, fmt.Errorf("parse task template task.yaml: %w", err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTaskTemplateFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile %s: %v", name, err)
+	}
+}
+
+func TestCreateTaskPackageDefaultTemplate(t *testing.T) {
+	root := t.TempDir()
+	record, err := CreateTaskPackage(root, "Plain", "backend", "  design notes  ", time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CreateTaskPackage: %v", err)
+	}
+	data, _ := os.ReadFile(TaskContextPath(root, record.TaskID))
+	for _, needle := range []string{"- Task ID: `" + record.TaskID + "`", "- Role: `backend`\n\n## Background", "## Design\n\ndesign notes\n"} {
+		if !strings.Contains(string(data), needle) {
+			t.Fatalf("context.md missing %q:\n%s", needle, data)
+		}
+	}
+	data, _ = os.ReadFile(TaskVerificationPath(root, record.TaskID))
+	if !strings.Contains(string(data), "## Result\n- pending\n") {
+		t.Fatalf("verification.md = %s", data)
+	}
+}
+
+func TestCreateTaskPackageWithProjectTemplate(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(ProjectTaskTemplatesDir(root), "bugfix")
+	writeTaskTemplateFile(t, dir, "context.md", "# Bug {{.Title}}\n\nRole {{.Role}} in phase {{.Phase}} ({{.DesignPath}})\n")
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	phase, err := CreatePlanningRecord(root, PlanningKindPhase, "Stabilize", now)
+	if err != nil {
+		t.Fatalf("CreatePlanningRecord: %v", err)
+	}
+	writeTaskTemplateFile(t, dir, "task.yaml", "requirement: triage\ndepends_on: [task-a]\nparent_ids: ["+phase.ID+"]\nexternal_refs:\n  jira: BUG-1\n  github: org/repo#1\n")
+
+	record, err := CreateTaskPackageWithOptions(root, TaskCreateOptions{
+		Title: "Crash on start", Role: "backend", DesignPath: "docs/bug.md", Phase: "phase-1", Template: "bugfix",
+		DependsOn: []string{"task-b", "task-a"}, ExternalRefs: map[string]string{"github": "org/repo#2"},
+	}, now)
+	if err != nil {
+		t.Fatalf("CreateTaskPackageWithOptions: %v", err)
+	}
+	if record.Requirement != "triage" || record.Status != TaskStatusDraft ||
+		!slices.Equal(record.DependsOn, []string{"task-a", "task-b"}) || !slices.Equal(record.ParentIDs, []string{phase.ID}) ||
+		record.ExternalRefs["jira"] != "BUG-1" || record.ExternalRefs["github"] != "org/repo#2" {
+		t.Fatalf("record = %+v", record)
+	}
+	if linked, err := LoadPlanningRecord(root, phase.ID); err != nil || !slices.Equal(linked.TaskIDs, []string{record.TaskID}) {
+		t.Fatalf("template parent not linked back: %+v, %v", linked, err)
+	}
+	data, _ := os.ReadFile(TaskContextPath(root, record.TaskID))
+	if string(data) != "# Bug Crash on start\n\nRole backend in phase phase-1 (docs/bug.md)\n" {
+		t.Fatalf("context.md = %q", data)
+	}
+	if _, err := os.Stat(TaskVerificationPath(root, record.TaskID)); err != nil {
+		t.Fatalf("verification.md should fall back to the built-in template: %v", err)
+	}
+}
+
+func TestCreateTaskPackageRejectsUnsupportedTemplateFields(t *testing.T) {
+	root := t.TempDir()
+	now := time.Date(2026, 3, 21, 10, 0, 0, 0, time.UTC)
+	for _, tc := range []struct{ yaml, want string }{
+		{"requirement: triage\nstatus: archived\n", `field "status" cannot be set`},
+		{"priority: high\n", `field "priority" cannot be set`},
+		{"parent_ids: [missing-phase]\n", "planning not found: missing-phase"},
+	} {
+		dir := filepath.Join(ProjectTaskTemplatesDir(root), "bugfix")
+		writeTaskTemplateFile(t, dir, "task.yaml", tc.yaml)
+		_, err := CreateTaskPackageWithOptions(root, TaskCreateOptions{Title: "Crash", Role: "backend", Template: "bugfix"}, now)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("template %q: err = %v, want %q", tc.yaml, err, tc.want)
+		}
+	}
+	if tasks, _ := ListTasks(root, false); len(tasks) != 0 {
+		t.Fatalf("rejected templates created tasks: %+v", tasks)
+	}
+}
+
+func TestResolveTaskTemplate(t *testing.T) {
+	root := t.TempDir()
+	roleDir := RoleDir(root, "qa")
+	writeTaskTemplateFile(t, roleDir, "SKILL.md", "# qa\n")
+	writeTaskTemplateFile(t, filepath.Join(RoleTaskTemplatesDir(roleDir), DefaultTaskTemplateName), "context.md", "qa default\n")
+	writeTaskTemplateFile(t, filepath.Join(RoleTaskTemplatesDir(roleDir), "e2e"), "context.md", "qa e2e\n")
+
+	for _, tc := range []struct{ role, name, wantName string }{
+		{"qa", "", DefaultTaskTemplateName},
+		{"qa", "e2e", "e2e"},
+		{"backend", "", ""},
+	} {
+		tmpl, err := ResolveTaskTemplate(root, tc.role, tc.name)
+		if err != nil {
+			t.Fatalf("ResolveTaskTemplate(%s, %s): %v", tc.role, tc.name, err)
+		}
+		if tmpl.Name != tc.wantName {
+			t.Fatalf("ResolveTaskTemplate(%s, %s) = %+v, want %s", tc.role, tc.name, tmpl, tc.wantName)
+		}
+	}
+	if _, err := ResolveTaskTemplate(root, "qa", "missing"); err == nil {
+		t.Fatal("expected error for missing named template")
+	}
+	if _, err := ResolveTaskTemplate(root, "qa", "../qa"); err == nil {
+		t.Fatal("expected error for path-like template name")
+	}
+}